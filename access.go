@@ -0,0 +1,91 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// DocumentAccessGrant lets a user other than the thread's own download its documents,
+// the grantee gets urls and downloads with their own user id
+type DocumentAccessGrant struct {
+	UserID string `json:"userId"`
+	// ExpiresAt, when set, is when the grant stops letting the user download
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// GrantedBy is the user that granted access
+	GrantedBy string    `json:"grantedBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DocumentAccessResponse is the response received from granting document access
+type DocumentAccessResponse struct {
+	Data DocumentAccessData     `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// DocumentAccessData is the response data received from granting document access
+type DocumentAccessData struct {
+	Grant DocumentAccessGrant `json:"grant"`
+}
+
+// DocumentAccessListResponse is the response received from listing a thread's document access grants
+type DocumentAccessListResponse struct {
+	Data DocumentAccessListData `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// DocumentAccessListData is the response data received from listing a thread's document access grants
+type DocumentAccessListData struct {
+	Grants []DocumentAccessGrant `json:"grants"`
+}
+
+// GrantDocumentAccess lets grantee download the documents of the user's thread, until exp has passed
+// or for as long as the thread exists when exp is zero
+func (c *Client) GrantDocumentAccess(ctx context.Context, thread string, user string, grantee string, exp time.Duration, callOpts ...CallOption) (*DocumentAccessResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf("%v/api/v1/docubot/%v/access?%v", c.DocubotAPIURLBase, thread, params.Encode())
+	grant := DocumentAccessGrant{UserID: grantee}
+	if exp > 0 {
+		expiresAt := time.Now().Add(exp).UTC()
+		grant.ExpiresAt = &expiresAt
+	}
+	req, err := c.newRequest(ctx, "POST", url, map[string]interface{}{"grant": grant})
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentAccessResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// RevokeDocumentAccess stops grantee from downloading the documents of the user's thread,
+// urls already handed out stay valid until they expire
+func (c *Client) RevokeDocumentAccess(ctx context.Context, thread string, user string, grantee string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf("%v/api/v1/docubot/%v/access/%v?%v", c.DocubotAPIURLBase, thread, url.PathEscape(grantee), params.Encode())
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// ListDocumentAccess lists the users granted access to the documents of the user's thread
+func (c *Client) ListDocumentAccess(ctx context.Context, thread string, user string, callOpts ...CallOption) (*DocumentAccessListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf("%v/api/v1/docubot/%v/access?%v", c.DocubotAPIURLBase, thread, params.Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentAccessListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
@@ -0,0 +1,45 @@
+package docubotlib
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// pdfUAHeader is set by docubot on document downloads that conform to PDF/UA
+const pdfUAHeader string = "X-Docubot-Pdf-Ua"
+
+// AccessibilityOptions asks docubot to generate a Document as a tagged, accessible pdf
+type AccessibilityOptions struct {
+	// Tagged adds the structure tags screen readers navigate by
+	Tagged bool `json:"tagged"`
+	// Language is the document's language, such as "en-US"
+	Language string `json:"language,omitempty"`
+	// Title is the title shown instead of the file name by pdf readers
+	Title string `json:"title,omitempty"`
+	// Headings maps css classes to heading levels 1 through 6, for templates that style
+	// headings with classes instead of h1 to h6 elements
+	Headings map[string]int `json:"headings,omitempty"`
+	// PDFUA requires the file to conform to PDF/UA, generation fails when it can't
+	PDFUA bool `json:"pdfUa"`
+}
+
+// Validate checks the options can be met, PDF/UA needs tags, a language, and a title
+func (o *AccessibilityOptions) Validate() error {
+	for class, level := range o.Headings {
+		if level < 1 || level > 6 {
+			return fmt.Errorf("docubot: heading level %v for class %q must be between 1 and 6", level, class)
+		}
+	}
+	if o.PDFUA && (!o.Tagged || o.Language == "" || o.Title == "") {
+		return errors.New("docubot: PDF/UA documents must be tagged and have a language and title")
+	}
+	return nil
+}
+
+// pdfUA reports whether the download response says the document conforms to PDF/UA
+func pdfUA(header http.Header) bool {
+	conforms, _ := strconv.ParseBool(header.Get(pdfUAHeader))
+	return conforms
+}
@@ -0,0 +1,102 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// AnalyticsOptions limits analytics to a time range
+type AnalyticsOptions struct {
+	// From only includes threads active at or after this time when set
+	From time.Time
+	// To only includes threads active before this time when set
+	To time.Time
+}
+
+func (o AnalyticsOptions) params() url.Values {
+	params := url.Values{}
+	if !o.From.IsZero() {
+		params.Set("from", o.From.UTC().Format(time.RFC3339))
+	}
+	if !o.To.IsZero() {
+		params.Set("to", o.To.UTC().Format(time.RFC3339))
+	}
+	return params
+}
+
+// QuestionMetrics is a data model of how users answer one question
+type QuestionMetrics struct {
+	VariableName string `json:"variableName"`
+	Question     string `json:"question"`
+	// Asked is the number of times the question was asked
+	Asked int `json:"asked"`
+	// Answered is the number of times the question was eventually answered
+	Answered int `json:"answered"`
+	// AverageAnswerTimeMs is the average time between asking the question and receiving a valid answer
+	AverageAnswerTimeMs int64 `json:"averageAnswerTimeMs"`
+	// ValidationRetries is the total number of rejected answers
+	ValidationRetries int `json:"validationRetries"`
+	// SkipRate is the fraction of threads that abandoned the interview at this question
+	SkipRate float64 `json:"skipRate"`
+}
+
+// AverageAnswerTime is the average time between asking the question and receiving a valid answer
+func (m QuestionMetrics) AverageAnswerTime() time.Duration {
+	return time.Duration(m.AverageAnswerTimeMs) * time.Millisecond
+}
+
+// RetriesPerAnswer is the average number of rejected answers before a valid one
+func (m QuestionMetrics) RetriesPerAnswer() float64 {
+	if m.Answered == 0 {
+		return 0
+	}
+	return float64(m.ValidationRetries) / float64(m.Answered)
+}
+
+// QuestionAnalyticsResponse is the response received from getting a tree's question analytics
+type QuestionAnalyticsResponse struct {
+	Data QuestionAnalyticsData  `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// QuestionAnalyticsData is the response data received from getting a tree's question analytics
+type QuestionAnalyticsData struct {
+	Questions []QuestionMetrics `json:"questions"`
+}
+
+// MostConfusing returns up to n questions ordered by retries per answer and then skip rate, worst first
+func (d QuestionAnalyticsData) MostConfusing(n int) []QuestionMetrics {
+	questions := append([]QuestionMetrics(nil), d.Questions...)
+	sort.SliceStable(questions, func(i, j int) bool {
+		a, b := questions[i], questions[j]
+		if a.RetriesPerAnswer() != b.RetriesPerAnswer() {
+			return a.RetriesPerAnswer() > b.RetriesPerAnswer()
+		}
+		return a.SkipRate > b.SkipRate
+	})
+	if n >= 0 && n < len(questions) {
+		questions = questions[:n]
+	}
+	return questions
+}
+
+// GetQuestionAnalytics gets per question answer metrics for a tree
+func (c *Client) GetQuestionAnalytics(ctx context.Context, docTreeID string, opts AnalyticsOptions, callOpts ...CallOption) (*QuestionAnalyticsResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf(
+		"%v/api/v1/analytics/trees/%v/questions?%v",
+		c.DocubotAPIURLBase,
+		docTreeID,
+		opts.params().Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response QuestionAnalyticsResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
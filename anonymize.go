@@ -0,0 +1,160 @@
+package docubotlib
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+var (
+	fakeFirstNames = []string{"Alex", "Jordan", "Taylor", "Morgan", "Casey", "Riley", "Jamie", "Avery", "Quinn", "Drew"}
+	fakeLastNames  = []string{"Smith", "Garcia", "Nguyen", "Johnson", "Patel", "Brown", "Lopez", "Kim", "Miller", "Davis"}
+	fakeStreets    = []string{"Maple", "Oak", "Cedar", "Pine", "Elm", "Willow", "Lakeview", "Hillcrest"}
+)
+
+// Anonymizer replaces sensitive variables with realistic fake values so real conversation flows can be debugged
+// without handling client data. The same original value always gets the same fake, which keeps conditions
+// that compare variables to each other behaving the same.
+type Anonymizer struct {
+	// Redactor decides which variables are replaced
+	Redactor *Redactor
+	// Tree, when set, gives the entity type of each variable so dates stay dates and numbers stay numbers
+	Tree *DocumentTree
+	// Fakes are used instead of generated fakes for the variables they name
+	Fakes map[string]interface{}
+	// Salt changes every generated fake, use a different salt to stop fakes being matched across clones
+	Salt string
+}
+
+// NewAnonymizer initializes an anonymizer that replaces the tree's sensitive variables, as decided by redactor.
+// A nil redactor replaces the variables NewRedactorFromTree finds sensitive in the tree.
+func NewAnonymizer(tree *DocumentTree, redactor *Redactor) *Anonymizer {
+	if redactor == nil {
+		redactor = NewRedactorFromTree(tree)
+	}
+	return &Anonymizer{Redactor: redactor, Tree: tree}
+}
+
+// AnonymizeVariables returns a copy of variables with every sensitive value replaced by a fake
+func (a *Anonymizer) AnonymizeVariables(variables map[string]interface{}) map[string]interface{} {
+	anonymized := copyVariables(variables)
+	for name, v := range anonymized {
+		if fake, ok := a.Fakes[name]; ok {
+			anonymized[name] = fake
+			continue
+		}
+		if v == nil || !a.Redactor.IsSensitive(name) {
+			continue
+		}
+		anonymized[name] = a.fake(name, v)
+	}
+	return anonymized
+}
+
+// AnonymizeSnapshot returns a copy of a snapshot with its sensitive variables, user, and attributes replaced
+func (a *Anonymizer) AnonymizeSnapshot(snapshot *ThreadSnapshot) *ThreadSnapshot {
+	anonymized := *snapshot
+	anonymized.Variables = a.AnonymizeVariables(snapshot.Variables)
+	anonymized.Thread.ID = ""
+	anonymized.Thread.UserID = "anon-" + a.hash("user", snapshot.Thread.UserID)[:12]
+	if snapshot.Thread.Attributes != nil {
+		anonymized.Thread.Attributes = map[string]string{}
+		for name, value := range snapshot.Thread.Attributes {
+			anonymized.Thread.Attributes[name] = shapeFake(a.random("attribute:"+name, value), value)
+		}
+	}
+	return &anonymized
+}
+
+// CloneThreadAnonymized snapshots the provided user's thread, anonymizes it, and restores it as a new thread
+func (c *Client) CloneThreadAnonymized(ctx context.Context, thread string, user string, anonymizer *Anonymizer, opts RestoreThreadOptions, callOpts ...CallOption) (*StartThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	snapshot, err := c.SnapshotThread(ctx, thread, user)
+	if err != nil {
+		return nil, err
+	}
+	return c.RestoreThread(ctx, anonymizer.AnonymizeSnapshot(snapshot), opts)
+}
+
+// fake returns a fake for the variable's value that looks like the kind of value it replaces
+func (a *Anonymizer) fake(name string, v interface{}) interface{} {
+	original := fmt.Sprintf("%v", v)
+	r := a.random(name, original)
+	entityType := ""
+	if question := a.Tree.Question(name); question != nil {
+		entityType = strings.ToLower(question.EntityType)
+	}
+	lower := strings.ToLower(name)
+	switch {
+	case entityType == EntityTypeDate || entityType == "dateofbirth" || entityType == "dob":
+		return fakeDate(r, original)
+	case entityType == EntityTypeNumber || entityType == EntityTypeCurrency:
+		return shapeFake(r, original)
+	case entityType == "ssn" || strings.Contains(lower, "ssn"):
+		return fmt.Sprintf("9%02d-%02d-%04d", r.Intn(100), 1+r.Intn(99), 1+r.Intn(9999))
+	case strings.Contains(lower, "email") || strings.Contains(original, "@"):
+		return fmt.Sprintf("%v.%v@example.com", strings.ToLower(pick(r, fakeFirstNames)), strings.ToLower(pick(r, fakeLastNames)))
+	case strings.Contains(lower, "phone"):
+		return fmt.Sprintf("555-01%02d", r.Intn(100))
+	case strings.Contains(lower, "address") || strings.Contains(lower, "street"):
+		return fmt.Sprintf("%v %v St", 100+r.Intn(9900), pick(r, fakeStreets))
+	case strings.Contains(lower, "firstname") || strings.Contains(lower, "first_name"):
+		return pick(r, fakeFirstNames)
+	case strings.Contains(lower, "lastname") || strings.Contains(lower, "last_name") || strings.Contains(lower, "surname"):
+		return pick(r, fakeLastNames)
+	case strings.Contains(lower, "name"):
+		return pick(r, fakeFirstNames) + " " + pick(r, fakeLastNames)
+	}
+	if _, err := time.Parse(canonicalDateLayout, original); err == nil {
+		return fakeDate(r, original)
+	}
+	return shapeFake(r, original)
+}
+
+// random returns a source seeded from the salt, the variable, and its value
+func (a *Anonymizer) random(name string, value string) *rand.Rand {
+	sum := sha256.Sum256([]byte(a.Salt + "\x00" + name + "\x00" + value))
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+}
+
+func (a *Anonymizer) hash(name string, value string) string {
+	sum := sha256.Sum256([]byte(a.Salt + "\x00" + name + "\x00" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+// fakeDate returns a date within a few years of the original, or in the recent past if it isn't a date
+func fakeDate(r *rand.Rand, original string) string {
+	t, err := time.Parse(canonicalDateLayout, original)
+	if err != nil {
+		t = time.Date(1985, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return t.AddDate(r.Intn(7)-3, 0, r.Intn(365)).Format(canonicalDateLayout)
+}
+
+// shapeFake replaces every letter and digit of original with a random one, keeping case, spacing, and punctuation
+func shapeFake(r *rand.Rand, original string) string {
+	out := []rune(original)
+	for i, c := range out {
+		switch {
+		case c >= 'a' && c <= 'z':
+			out[i] = 'a' + rune(r.Intn(26))
+		case c >= 'A' && c <= 'Z':
+			out[i] = 'A' + rune(r.Intn(26))
+		case c >= '0' && c <= '9':
+			out[i] = '0' + rune(r.Intn(10))
+			if i == 0 && out[i] == '0' && len(out) > 1 {
+				out[i] = '1' + rune(r.Intn(9))
+			}
+		}
+	}
+	return string(out)
+}
+
+func pick(r *rand.Rand, values []string) string {
+	return values[r.Intn(len(values))]
+}
@@ -0,0 +1,63 @@
+package docubotlib
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entity types of questions answered with typed values
+const (
+	EntityTypeText   string = "text"
+	EntityTypeDate   string = "date"
+	EntityTypeNumber string = "number"
+	// EntityTypeCurrency questions are answered with an amount of money, see Money
+	EntityTypeCurrency string = "currency"
+	// EntityTypeMultipleChoice questions are answered with one of the keys of their MetaData.Choices
+	EntityTypeMultipleChoice string = "multipleChoice"
+)
+
+// DateAnswer returns the message that answers a date question with t
+func DateAnswer(t time.Time) string {
+	return t.Format(canonicalDateLayout)
+}
+
+// NumberAnswer returns the message that answers a number question with a number written in the locale
+func NumberAnswer(locale Locale, input string) (string, error) {
+	return locale.ParseNumber(input)
+}
+
+// NormalizeAnswer converts a user's answer to a question of entityType, written the way the locale does,
+// into the canonical form docubot expects. Answers to other entity types are only trimmed.
+func NormalizeAnswer(locale Locale, entityType string, input string) (string, error) {
+	switch strings.ToLower(entityType) {
+	case EntityTypeDate:
+		t, err := locale.ParseDate(input)
+		if err != nil {
+			return "", err
+		}
+		return DateAnswer(t), nil
+	case EntityTypeNumber:
+		return locale.ParseNumber(input)
+	case EntityTypeCurrency:
+		m, err := locale.ParseMoney(input)
+		if err != nil {
+			return "", err
+		}
+		return m.String(), nil
+	}
+	return strings.TrimSpace(input), nil
+}
+
+// NormalizeAnswerFor converts a user's answer to the question asking for variable in the tree,
+// answers to multiple choice questions are matched to a choice's key with ChoiceAnswer
+func NormalizeAnswerFor(locale Locale, tree *DocumentTree, variable string, input string) (string, error) {
+	question := tree.Question(variable)
+	if question == nil {
+		return "", fmt.Errorf("docubot: no question asks for variable %q", variable)
+	}
+	if strings.EqualFold(question.EntityType, EntityTypeMultipleChoice) {
+		return ChoiceAnswer(locale, question, input)
+	}
+	return NormalizeAnswer(locale, question.EntityType, input)
+}
@@ -0,0 +1,122 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// API key scopes
+const (
+	ScopeMessages     string = "messages"
+	ScopePreview      string = "preview"
+	ScopeDocuments    string = "documents"
+	ScopeThreadsRead  string = "threads:read"
+	ScopeThreadsWrite string = "threads:write"
+	ScopeTrees        string = "trees"
+	ScopeAdmin        string = "admin"
+)
+
+// APIKey is a data model
+type APIKey struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Key is the public half of the credentials passed to NewClient
+	Key string `json:"key"`
+	// Secret is only returned when the key is created
+	Secret     string     `json:"secret,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateAPIKeyOptions describes the key created by CreateAPIKey
+type CreateAPIKeyOptions struct {
+	Name string `json:"name"`
+	// Scopes limits what the key can do, a key without scopes has full access
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpiresAt makes the key stop working at the provided time
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// APIKeyResponse is the response received from creating or updating an api key
+type APIKeyResponse struct {
+	Data APIKeyData             `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// APIKeyData is the response data received from creating or updating an api key
+type APIKeyData struct {
+	APIKey APIKey `json:"apiKey"`
+}
+
+// APIKeyListResponse is the response received from listing api keys
+type APIKeyListResponse struct {
+	Data APIKeyListData         `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// APIKeyListData is the response data received from listing api keys
+type APIKeyListData struct {
+	APIKeys []APIKey `json:"apiKeys"`
+}
+
+// CreateAPIKey creates an api key for the account, the returned secret can't be retrieved again
+func (c *Client) CreateAPIKey(ctx context.Context, opts CreateAPIKeyOptions, callOpts ...CallOption) (*APIKeyResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/keys", c.DocubotAPIURLBase)
+	req, err := c.newRequest(ctx, "POST", url, opts)
+	if err != nil {
+		return nil, err
+	}
+	var response APIKeyResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ListAPIKeys lists the account's api keys, secrets are never included
+func (c *Client) ListAPIKeys(ctx context.Context, callOpts ...CallOption) (*APIKeyListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/keys", c.DocubotAPIURLBase)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response APIKeyListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// SetAPIKeyScopes replaces the scopes of an api key
+func (c *Client) SetAPIKeyScopes(ctx context.Context, id string, scopes []string, callOpts ...CallOption) (*APIKeyResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/keys/%v", c.DocubotAPIURLBase, id)
+	req, err := c.newRequest(
+		ctx,
+		"PATCH",
+		url,
+		map[string]interface{}{
+			"scopes": scopes,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response APIKeyResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// RevokeAPIKey revokes an api key, requests made with it fail immediately afterwards
+func (c *Client) RevokeAPIKey(ctx context.Context, id string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/keys/%v", c.DocubotAPIURLBase, id)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
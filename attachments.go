@@ -0,0 +1,63 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// QuestionAttachment is a data model for reference material shown with a question, such as an exhibit
+// the user should consult before answering
+type QuestionAttachment struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	// Description is shown with the attachment, such as "the lease's first page"
+	Description string `json:"description,omitempty"`
+	// URL is where the attachment is downloaded from, in message responses it is signed for the thread's user
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// QuestionAttachmentResponse is the response received from uploading an attachment to a question
+type QuestionAttachmentResponse struct {
+	Data QuestionAttachmentData `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// QuestionAttachmentData is the response data received from uploading an attachment to a question
+type QuestionAttachmentData struct {
+	Attachment QuestionAttachment `json:"attachment"`
+}
+
+// Attachments returns the question's reference material
+func (n *QuestionNode) Attachments() []QuestionAttachment {
+	if n.MetaData == nil {
+		return nil
+	}
+	return n.MetaData.Attachments
+}
+
+// UploadQuestionAttachment uploads reference material to the tree's question asking for the variable, it is added
+// to the question's metadata and sent with message responses asking the question. Images and pdfs are shown
+// by most chat uis.
+func (c *Client) UploadQuestionAttachment(ctx context.Context, docTreeID string, variable string, filename string, contentType string, file io.Reader, callOpts ...CallOption) (*QuestionAttachmentResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees/%v/questions/%v/attachments", c.DocubotAPIURLBase, docTreeID, variable)
+	var response QuestionAttachmentResponse
+	err := c.uploadMultipart(ctx, url, filename, contentType, file, &response)
+	return &response, err
+}
+
+// DeleteQuestionAttachment removes an attachment from the tree's question asking for the variable
+func (c *Client) DeleteQuestionAttachment(ctx context.Context, docTreeID string, variable string, attachmentID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees/%v/questions/%v/attachments/%v", c.DocubotAPIURLBase, docTreeID, variable, attachmentID)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
@@ -0,0 +1,92 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Headers attributing a call to the person and channel it was made for
+const (
+	actorIDHeader      string = "X-Docubot-Actor-Id"
+	actorChannelHeader string = "X-Docubot-Actor-Channel"
+	actorIPHeader      string = "X-Docubot-Actor-Ip"
+)
+
+// Actor is who supplied an answer, such as the staff member typing it on a client's behalf
+type Actor struct {
+	// ID identifies the person, such as a staff member's ID
+	ID string `json:"id,omitempty"`
+	// Channel is where the answer came from, such as "web", "sms", or "phone"
+	Channel string `json:"channel,omitempty"`
+	// IP is the address of the person's device
+	IP string `json:"ip,omitempty"`
+}
+
+// WithActor attributes the call to the actor, docubot stores the actor with every answer or variable
+// the call records so ListAnswerAudit can show who supplied it
+func WithActor(actor Actor) CallOption {
+	return func(o *callOptions) {
+		if actor.ID != "" {
+			o.headers.Set(actorIDHeader, actor.ID)
+		}
+		if actor.Channel != "" {
+			o.headers.Set(actorChannelHeader, actor.Channel)
+		}
+		if actor.IP != "" {
+			o.headers.Set(actorIPHeader, actor.IP)
+		}
+	}
+}
+
+// Sources of an AnswerAuditEntry
+const (
+	// AuditSourceMessage entries were answered by a message sent to the thread
+	AuditSourceMessage string = "message"
+	// AuditSourceVariables entries were written with SetDocubotVariables
+	AuditSourceVariables string = "variables"
+)
+
+// AnswerAuditEntry is a data model for a change to one of a thread's variables
+type AnswerAuditEntry struct {
+	VariableName string      `json:"variableName"`
+	Value        interface{} `json:"value"`
+	// Source is how the variable was changed, such as AuditSourceMessage
+	Source string `json:"source"`
+	// Actor is who the change was attributed to with WithActor, empty when it wasn't
+	Actor Actor `json:"actor"`
+	// Sender is the sender of the message that answered the variable, for AuditSourceMessage entries
+	Sender    string    `json:"sender,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AnswerAuditListResponse is the response received from listing a thread's audit trail
+type AnswerAuditListResponse struct {
+	Data AnswerAuditListData `json:"data"`
+	Meta Page                `json:"meta"`
+}
+
+// AnswerAuditListData is the response data received from listing a thread's audit trail
+type AnswerAuditListData struct {
+	Entries []AnswerAuditEntry `json:"entries"`
+}
+
+// ListAnswerAudit lists a page of the changes to the provided user's thread's variables, oldest first
+func (c *Client) ListAnswerAudit(ctx context.Context, thread string, user string, opts ListOptions, callOpts ...CallOption) (*AnswerAuditListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := opts.params()
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/audit?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response AnswerAuditListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
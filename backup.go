@@ -0,0 +1,235 @@
+package docubotlib
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// accountArchiveVersion is the format version written to an account archive's manifest
+const accountArchiveVersion int = 1
+
+// ExportOptions customizes ExportAccount
+type ExportOptions struct {
+	// IncludeThreads adds every thread and its variables to the archive
+	IncludeThreads bool
+	// Redactor, when set, masks sensitive thread variables in the archive
+	Redactor *Redactor
+}
+
+// AccountManifest describes the contents of an account archive
+type AccountManifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Trees     int       `json:"trees"`
+	Documents int       `json:"documents"`
+	Threads   int       `json:"threads"`
+}
+
+// ThreadExport is a thread and its variables as stored in an account archive
+type ThreadExport struct {
+	Thread    Thread                 `json:"thread"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// ImportOptions customizes ImportAccount
+type ImportOptions struct {
+	// NamePrefix is prepended to the DocumentName of every imported tree
+	NamePrefix string
+}
+
+// ImportReport describes what ImportAccount created
+type ImportReport struct {
+	// Trees maps the archived tree IDs to the IDs of the trees created for them
+	Trees map[string]string
+	// Documents maps the archived document IDs to the IDs of the documents created for them
+	Documents map[string]string
+	// SkippedThreads is the number of archived threads, threads aren't imported
+	SkippedThreads int
+}
+
+// ExportAccount writes every DocumentTree and Document on the account, and optionally every thread,
+// to w as a gzipped tar archive that ImportAccount can restore
+func (c *Client) ExportAccount(ctx context.Context, w io.Writer, opts ExportOptions, callOpts ...CallOption) (*AccountManifest, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	manifest := &AccountManifest{Version: accountArchiveVersion, CreatedAt: time.Now().UTC()}
+	cursor := ""
+	for {
+		page, err := c.ListDocumentTrees(ctx, ListOptions{Cursor: cursor})
+		if err != nil {
+			return nil, err
+		}
+		for _, tree := range page.Data.DocumentTrees {
+			// listings may omit the questions, so every tree is fetched in full
+			full, err := c.GetDocumentTree(ctx, tree.ID)
+			if err != nil {
+				return nil, err
+			}
+			if err := writeArchiveJSON(archive, "trees/"+tree.ID+".json", full.Data.DocumentTree); err != nil {
+				return nil, err
+			}
+			manifest.Trees++
+			documents, err := c.ListDocuments(ctx, tree.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, document := range documents.Data.Documents {
+				if err := writeArchiveJSON(archive, "documents/"+document.ID+".json", document); err != nil {
+					return nil, err
+				}
+				manifest.Documents++
+			}
+		}
+		cursor = page.Meta.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+	if opts.IncludeThreads {
+		if err := c.exportThreads(ctx, archive, opts, manifest); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeArchiveJSON(archive, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, gz.Close()
+}
+
+func (c *Client) exportThreads(ctx context.Context, archive *tar.Writer, opts ExportOptions, manifest *AccountManifest) error {
+	cursor := ""
+	for {
+		page, err := c.ListThreads(ctx, ListThreadsOptions{Cursor: cursor})
+		if err != nil {
+			return err
+		}
+		for _, thread := range page.Data.Threads {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			variables, err := c.getDocubotVariables(ctx, thread.ID, thread.UserID)
+			if err != nil {
+				return err
+			}
+			export := ThreadExport{
+				Thread:    thread,
+				Variables: opts.Redactor.RedactVariables(variables.Data.Variables),
+			}
+			if err := writeArchiveJSON(archive, "threads/"+thread.ID+".json", export); err != nil {
+				return err
+			}
+			manifest.Threads++
+		}
+		cursor = page.Meta.NextCursor
+		if cursor == "" {
+			return nil
+		}
+	}
+}
+
+func writeArchiveJSON(archive *tar.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = archive.Write(data)
+	return err
+}
+
+// ImportAccount creates the DocumentTrees and Documents stored in an archive written by ExportAccount.
+// Everything is created with new IDs, documents are attached to the newly created trees.
+func (c *Client) ImportAccount(ctx context.Context, r io.Reader, opts ImportOptions, callOpts ...CallOption) (*ImportReport, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	archive := tar.NewReader(gz)
+	var trees []DocumentTree
+	var documents []Document
+	report := &ImportReport{Trees: map[string]string{}, Documents: map[string]string{}}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch dir := path.Dir(header.Name); {
+		case header.Name == "manifest.json":
+			var manifest AccountManifest
+			if err := json.NewDecoder(archive).Decode(&manifest); err != nil {
+				return nil, err
+			}
+			if manifest.Version > accountArchiveVersion {
+				return nil, fmt.Errorf("docubot: archive version %v is newer than this library supports", manifest.Version)
+			}
+		case dir == "trees":
+			var tree DocumentTree
+			if err := json.NewDecoder(archive).Decode(&tree); err != nil {
+				return nil, err
+			}
+			trees = append(trees, tree)
+		case dir == "documents":
+			var document Document
+			if err := json.NewDecoder(archive).Decode(&document); err != nil {
+				return nil, err
+			}
+			documents = append(documents, document)
+		case dir == "threads" && strings.HasSuffix(header.Name, ".json"):
+			report.SkippedThreads++
+		}
+	}
+	return report, c.createTrees(ctx, trees, documents, opts, report)
+}
+
+// createTrees creates the trees and their documents with new IDs, recording the IDs in report
+func (c *Client) createTrees(ctx context.Context, trees []DocumentTree, documents []Document, opts ImportOptions, report *ImportReport) error {
+	for _, tree := range trees {
+		oldID := tree.ID
+		tree.ID = ""
+		tree.DocumentName = opts.NamePrefix + tree.DocumentName
+		created, err := c.CreateDocumentTree(ctx, &tree)
+		if err != nil {
+			return err
+		}
+		report.Trees[oldID] = created.Data.DocumentTree.ID
+	}
+	for _, document := range documents {
+		oldID := document.ID
+		treeID, ok := report.Trees[document.DocumentTreeID]
+		if !ok {
+			return fmt.Errorf("docubot: document %v belongs to a tree that isn't being imported", oldID)
+		}
+		document.ID = ""
+		document.DocumentTreeID = treeID
+		created, err := c.CreateDocument(ctx, &document)
+		if err != nil {
+			return err
+		}
+		report.Documents[oldID] = created.Data.Document.ID
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrBatchNotExecuted is the error of a BatchOperation whose batch hasn't been executed yet
+var ErrBatchNotExecuted = errors.New("docubot: batch not executed")
+
+// BatchOperation is one call queued in a Batch, its result is set once the batch is executed
+type BatchOperation struct {
+	method string
+	path   string
+	body   interface{}
+	// prepare, when set, builds the body as the batch is executed
+	prepare func(ctx context.Context) (interface{}, error)
+
+	// StatusCode is the status docubot answered the operation with
+	StatusCode int
+	// Err is why the operation failed, such as an *APIError, nil when it succeeded
+	Err  error
+	data json.RawMessage
+}
+
+// Decode decodes the operation's response into v, such as a *MessageResponse for an operation queued
+// by Batch.SendMessage
+func (o *BatchOperation) Decode(v interface{}) error {
+	if o.Err != nil {
+		return o.Err
+	}
+	return json.Unmarshal(o.data, v)
+}
+
+// Batch queues several calls and sends them to docubot as a single request, reducing round trips for
+// synchronization jobs. Operations run in the order they were queued. Build one with Client.Batch.
+type Batch struct {
+	client *Client
+	// Atomic makes docubot run the operations in a transaction, when one fails none of them are applied
+	Atomic     bool
+	operations []*BatchOperation
+}
+
+// Batch starts a batch of calls
+func (c *Client) Batch() *Batch {
+	return &Batch{client: c}
+}
+
+func (b *Batch) queue(method string, path string, body interface{}) *BatchOperation {
+	operation := &BatchOperation{method: method, path: path, body: body, Err: ErrBatchNotExecuted}
+	b.operations = append(b.operations, operation)
+	return operation
+}
+
+// SetVariables queues writing variables to the provided user's thread, they are encrypted as SetDocubotVariables
+// encrypts them when the batch is executed
+func (b *Batch) SetVariables(thread string, user string, variables map[string]interface{}) *BatchOperation {
+	params := url.Values{}
+	params.Set("user", user)
+	operation := b.queue("PATCH", fmt.Sprintf("/api/v1/docubot/%v/variables?%v", thread, params.Encode()), nil)
+	operation.prepare = func(ctx context.Context) (interface{}, error) {
+		prepared, err := b.client.prepareVariables(ctx, thread, variables)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"variables": prepared}, nil
+	}
+	return operation
+}
+
+// SendMessage queues sending a message to docubot, its response decodes into a MessageResponse. The answer is
+// normalized like SendMessage's, encrypt designated answers with EncryptAnswer.
+func (b *Batch) SendMessage(message string, thread string, sender string, docTreeID string) *BatchOperation {
+	return b.queue("POST", "/api/v1/docubot", b.client.messageBody(message, thread, sender, docTreeID, ""))
+}
+
+// DocumentURL queues getting the provided user's docubot document url, its response decodes into a DocumentURLResponse
+func (b *Batch) DocumentURL(thread string, user string, exp time.Duration) *BatchOperation {
+	seconds, err := urlDurationSeconds(exp)
+	if err != nil {
+		// not queued, the batch is sent without it
+		return &BatchOperation{Err: err}
+	}
+	params := url.Values{}
+	params.Set("user", user)
+	params.Set("duration", fmt.Sprintf("%v", seconds))
+	return b.queue("GET", fmt.Sprintf("/api/v1/docubot/%v/doc/url?%v", thread, params.Encode()), nil)
+}
+
+// batchResult is docubot's answer to one operation of a batch
+type batchResult struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// Execute sends the queued operations and sets their results. The error is only for the batch request itself,
+// each operation's failure is in its Err. With Atomic, every operation fails when one does.
+func (b *Batch) Execute(ctx context.Context, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	if len(b.operations) == 0 {
+		return nil
+	}
+	operations := make([]map[string]interface{}, len(b.operations))
+	for i, operation := range b.operations {
+		operations[i] = map[string]interface{}{"method": operation.method, "path": operation.path}
+		body := operation.body
+		if operation.prepare != nil {
+			var err error
+			if body, err = operation.prepare(ctx); err != nil {
+				return err
+			}
+		}
+		if body != nil {
+			operations[i]["body"] = body
+		}
+	}
+	url := fmt.Sprintf("%v/api/v1/batch", b.client.DocubotAPIURLBase)
+	req, err := b.client.newRequest(ctx, "POST", url, map[string]interface{}{"operations": operations, "atomic": b.Atomic})
+	if err != nil {
+		return err
+	}
+	if b.client.retries(ctx) && req.Header.Get("Idempotency-Key") == "" {
+		// retried batches need a key so docubot doesn't run the operations twice
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Idempotency-Key", key)
+	}
+	var response struct {
+		Data struct {
+			Results []batchResult `json:"results"`
+		} `json:"data"`
+	}
+	if err := b.client.doJSON(req, nil, &response); err != nil {
+		return err
+	}
+	if len(response.Data.Results) != len(b.operations) {
+		return fmt.Errorf("docubot: batch of %v operations answered with %v results", len(b.operations), len(response.Data.Results))
+	}
+	for i, result := range response.Data.Results {
+		operation := b.operations[i]
+		operation.StatusCode = result.Status
+		operation.data = result.Body
+		operation.Err = nil
+		if result.Status < 200 || result.Status > 299 {
+			operation.Err = b.client.responseError(&http.Response{
+				StatusCode: result.Status,
+				Header:     http.Header{},
+				Body:       io.NopCloser(bytes.NewReader(result.Body)),
+			}, nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package docubotlib
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBatchPreparesLikeSingleCalls(t *testing.T) {
+	f, c := newFakeDocubot(t)
+	c.VariableEncryption = testEncryption()
+	c.AnswerNormalizers = []AnswerNormalizer{strings.TrimSpace}
+	b := c.Batch()
+	set := b.SetVariables("thread_1", "user", map[string]interface{}{"ssn": testSSN, "name": "Jane Roe"})
+	send := b.SendMessage("  yes  ", "thread_1", "user", "tree")
+	if err := b.Execute(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if set.Err != nil || send.Err != nil {
+		t.Fatal(set.Err, send.Err)
+	}
+	if f.sent(testSSN) {
+		t.Error("a batch sent an encrypted variable in plaintext")
+	}
+	if !f.sent("Jane Roe") || !f.sent(encryptedVariablePrefix) {
+		t.Error("the batch didn't send the variables")
+	}
+	if f.sent("  yes  ") || !f.sent(`"yes"`) {
+		t.Error("the batch didn't normalize the message")
+	}
+}
@@ -0,0 +1,207 @@
+package docubotlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// callHistoryBodyLimit is how many bytes of each request and response body a RecordedCall keeps
+const callHistoryBodyLimit int = 4 << 10
+
+// redactedHeaders carry credentials, their values are never recorded
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", WebhookSignatureHeader}
+
+// RecordedCall is a request sent to docubot and its response, as kept by Client.CallHistory. Retries and hedged
+// copies of a request are recorded as calls of their own.
+type RecordedCall struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// RequestHeader and ResponseHeader have credentials masked
+	RequestHeader http.Header `json:"requestHeader"`
+	// RequestBody and ResponseBody are the first 4KB of the bodies with sensitive values masked, bodies that
+	// aren't text, such as pdfs and uploads, aren't kept
+	RequestBody    string      `json:"requestBody,omitempty"`
+	StatusCode     int         `json:"statusCode,omitempty"`
+	ResponseHeader http.Header `json:"responseHeader,omitempty"`
+	ResponseBody   string      `json:"responseBody,omitempty"`
+	// Truncated is set when a body was longer than what was kept
+	Truncated bool `json:"truncated,omitempty"`
+	// Err is why the request failed without a response
+	Err       string        `json:"error,omitempty"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// RecentCalls returns the most recent calls the client made, oldest first, the last CallHistory of them.
+// A call whose response body is still being read has what has been read so far.
+func (c *Client) RecentCalls() []RecordedCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := make([]RecordedCall, 0, len(c.history))
+	for i := range c.history {
+		call := *c.history[(c.historyNext+i)%len(c.history)]
+		call.RequestHeader = call.RequestHeader.Clone()
+		call.ResponseHeader = call.ResponseHeader.Clone()
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// recordCall adds the request to the history before it is sent, nil when the client keeps no history
+func (c *Client) recordCall(req *http.Request) *RecordedCall {
+	if c.CallHistory <= 0 {
+		return nil
+	}
+	call := &RecordedCall{
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		RequestHeader: redactHeader(req.Header),
+		StartedAt:     time.Now(),
+	}
+	if req.GetBody != nil && recordableContentType(req.Header.Get("Content-Type")) {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, int64(callHistoryBodyLimit+1)))
+			body.Close()
+			call.RequestBody, call.Truncated = c.redactRecordedBody(data, true)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.history) < c.CallHistory {
+		c.history = append(c.history, call)
+		return call
+	}
+	if len(c.history) > c.CallHistory {
+		c.history, c.historyNext = c.history[len(c.history)-c.CallHistory:], 0
+	}
+	c.history[c.historyNext] = call
+	c.historyNext = (c.historyNext + 1) % len(c.history)
+	return call
+}
+
+// finishCall records the outcome of the call's request, the response body is recorded as it is read
+func (c *Client) finishCall(call *RecordedCall, resp *http.Response, err error) {
+	if call == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call.Duration = time.Since(call.StartedAt)
+	if err != nil {
+		call.Err = err.Error()
+		return
+	}
+	call.StatusCode = resp.StatusCode
+	call.ResponseHeader = redactHeader(resp.Header)
+	if recordableContentType(resp.Header.Get("Content-Type")) {
+		resp.Body = &recordingBody{ReadCloser: resp.Body, client: c, call: call}
+	}
+}
+
+// recordingBody keeps the start of a response body as it is read
+type recordingBody struct {
+	io.ReadCloser
+	client *Client
+	call   *RecordedCall
+	data   []byte
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if len(b.data) <= callHistoryBodyLimit {
+		b.data = append(b.data, p[:n]...)
+	}
+	if err != nil {
+		b.record()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.record()
+	return b.ReadCloser.Close()
+}
+
+func (b *recordingBody) record() {
+	body, truncated := b.client.redactRecordedBody(b.data, false)
+	b.client.mu.Lock()
+	b.call.ResponseBody = body
+	b.call.Truncated = b.call.Truncated || truncated
+	b.client.mu.Unlock()
+}
+
+// redactRecordedBody masks a body for the history: the values of json fields named after the variables the
+// client's Redactor was given, and whatever its patterns match. Without a Redactor the messages sent are masked
+// too, they are the user's answers, and DefaultRedactionPatterns apply.
+func (c *Client) redactRecordedBody(data []byte, request bool) (string, bool) {
+	truncated := len(data) > callHistoryBodyLimit
+	if truncated {
+		data = data[:callHistoryBodyLimit]
+	}
+	redactor := c.Redactor
+	maskMessages := request && redactor == nil
+	if redactor == nil {
+		redactor = NewRedactor()
+	}
+	var v interface{}
+	if !truncated && json.Unmarshal(data, &v) == nil {
+		if body, ok := v.(map[string]interface{}); ok && maskMessages {
+			if _, ok := body["message"]; ok {
+				body["message"] = redactor.mask()
+			}
+		}
+		if masked, err := json.Marshal(redactJSONFields(redactor, v)); err == nil {
+			data = masked
+		}
+	} else if maskMessages && bytes.Contains(data, []byte(`"message"`)) {
+		// the message can't be told apart from the rest of a body that isn't valid json
+		return redactor.mask(), truncated
+	}
+	return redactor.RedactString(string(data), nil), truncated
+}
+
+// redactJSONFields masks the values of object fields named after the redactor's sensitive variables, at any depth.
+// An allowlist redactor can't tell variables from other fields, only its patterns apply.
+func redactJSONFields(redactor *Redactor, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if redactor.sensitive[key] {
+				v[key] = redactor.mask()
+			} else {
+				v[key] = redactJSONFields(redactor, value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactJSONFields(redactor, value)
+		}
+	}
+	return v
+}
+
+// redactHeader copies header with the values of credential headers masked
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// recordableContentType reports whether bodies of the content type are text worth keeping
+func recordableContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, text := range []string{"json", "text/", "x-www-form-urlencoded", "xml"} {
+		if strings.Contains(contentType, text) {
+			return true
+		}
+	}
+	return false
+}
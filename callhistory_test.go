@@ -0,0 +1,33 @@
+package docubotlib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCallHistoryMasksMessages(t *testing.T) {
+	_, c := newFakeDocubot(t)
+	c.CallHistory = 10
+	c.SendMessage("Jane Roe", "thread_1", "user", "tree")
+	c.SendMessage(strings.Repeat("Jane Roe ", 1000), "thread_1", "user", "tree")
+	calls := c.RecentCalls()
+	if len(calls) != 2 {
+		t.Fatalf("recorded %v calls, want 2", len(calls))
+	}
+	for i, call := range calls {
+		if strings.Contains(call.RequestBody, "Jane Roe") {
+			t.Errorf("call %v recorded the message: %q", i+1, call.RequestBody)
+		}
+	}
+	if !strings.Contains(calls[0].RequestBody, "thread_1") {
+		t.Errorf("recorded %q, want the rest of the body kept", calls[0].RequestBody)
+	}
+
+	c = NewClient(c.DocubotAPIURLBase, "key", "secret")
+	c.CallHistory = 10
+	c.Redactor = NewRedactor("ssn")
+	c.SendMessage("Jane Roe", "thread_1", "user", "tree")
+	if body := c.RecentCalls()[0].RequestBody; !strings.Contains(body, "Jane Roe") {
+		t.Errorf("recorded %q, a client with a Redactor decides what is masked", body)
+	}
+}
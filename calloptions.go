@@ -0,0 +1,141 @@
+package docubotlib
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CallOption customizes a single request made by a client method
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	headers    http.Header
+	query      url.Values
+	dryRun     bool
+	readOnly   bool
+	hedgeDelay time.Duration
+	onPage     func(PageProgress)
+	maxRetries int
+	timeout    time.Duration
+	// readYourWrites, minVariablesVersion and consistencyWait are how reads of variables wait for writes
+	readYourWrites      bool
+	minVariablesVersion int64
+	consistencyWait     time.Duration
+}
+
+type callOptionsKey struct{}
+
+// WithHeaders adds headers to the request, they replace any header the client sets with the same name
+func WithHeaders(headers http.Header) CallOption {
+	return func(o *callOptions) {
+		for name, values := range headers {
+			o.headers[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// WithHeader sets a single header on the request
+func WithHeader(name string, value string) CallOption {
+	return func(o *callOptions) {
+		o.headers.Set(name, value)
+	}
+}
+
+// WithQuery adds query parameters to the request url, they replace any parameter the client sets with the same name
+func WithQuery(query url.Values) CallOption {
+	return func(o *callOptions) {
+		for name, values := range query {
+			o.query[name] = append([]string(nil), values...)
+		}
+	}
+}
+
+// WithQueryParam sets a single query parameter on the request url
+func WithQueryParam(name string, value string) CallOption {
+	return func(o *callOptions) {
+		o.query.Set(name, value)
+	}
+}
+
+// WithMaxRetries overrides the client's MaxRetries for the call
+func WithMaxRetries(n int) CallOption {
+	return func(o *callOptions) {
+		o.maxRetries = n
+	}
+}
+
+// WithoutRetries sends the call once whatever the client's MaxRetries, such as on a path where a fast failure
+// beats a slow success
+func WithoutRetries() CallOption {
+	return WithMaxRetries(0)
+}
+
+// WithTimeout limits how long each attempt of the call, until its response body is read, can take. Retries get
+// a fresh timeout, set a deadline on the context to limit the call as a whole.
+func WithTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithCallOptions returns a context carrying opts, they apply to every call made with the context, after the
+// options already on it and before the options passed to the call itself. Middleware layers of an application
+// can use it to tighten timeouts or disable retries for a path without changing the calls made on it.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	return withCallOptions(ctx, opts)
+}
+
+// withCallOptions returns a context carrying opts after any options already on ctx,
+// so methods built on other methods pass them on to every request they make
+func withCallOptions(ctx context.Context, opts []CallOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	existing, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	combined := make([]CallOption, 0, len(existing)+len(opts))
+	combined = append(combined, existing...)
+	combined = append(combined, opts...)
+	return context.WithValue(ctx, callOptionsKey{}, combined)
+}
+
+// resolveCallOptions returns the client's defaults with the call options carried by ctx applied
+func (c *Client) resolveCallOptions(ctx context.Context) callOptions {
+	opts, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	o := callOptions{
+		headers:    http.Header{},
+		query:      url.Values{},
+		dryRun:     c.DryRun,
+		readOnly:   c.ReadOnly,
+		hedgeDelay: c.HedgeDelay,
+		maxRetries: c.MaxRetries,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// applyCallOptions applies the call options carried by the request's context,
+// it fails with ErrReadOnly for requests that would change what docubot stores in read only mode
+func (c *Client) applyCallOptions(req *http.Request) error {
+	o := c.resolveCallOptions(req.Context())
+	if o.readOnly && mutates(req) {
+		return ErrReadOnly
+	}
+	for name, values := range o.headers {
+		req.Header[name] = values
+	}
+	if len(o.query) > 0 {
+		query := req.URL.Query()
+		for name, values := range o.query {
+			query[name] = values
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+	if o.dryRun && mutates(req) {
+		req.Header.Set(dryRunHeader, "true")
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Delivery statuses of a ChannelDelivery
+const (
+	DeliveryDelivered string = "delivered"
+	DeliveryFailed    string = "failed"
+	// DeliveryBounced is the status of a delivery the channel accepted and reported undeliverable later, see
+	// FallbackChannel.Bounced
+	DeliveryBounced string = "bounced"
+)
+
+// MessageChannel delivers docubot's messages to a thread's user outside of the adapter the interview runs in,
+// such as by SMS or email. Implementations look up the user's address from the thread's user.
+type MessageChannel interface {
+	// Name identifies the channel in delivery statuses, such as "sms"
+	Name() string
+	// Deliver sends the messages to the user, in order
+	Deliver(ctx context.Context, user string, messages []string) error
+}
+
+// NewMessageChannel returns a MessageChannel delivering with the function
+func NewMessageChannel(name string, deliver func(ctx context.Context, user string, messages []string) error) MessageChannel {
+	return &funcChannel{name: name, deliver: deliver}
+}
+
+type funcChannel struct {
+	name    string
+	deliver func(ctx context.Context, user string, messages []string) error
+}
+
+func (c *funcChannel) Name() string {
+	return c.name
+}
+
+func (c *funcChannel) Deliver(ctx context.Context, user string, messages []string) error {
+	return c.deliver(ctx, user, messages)
+}
+
+// ChannelDelivery is the status of delivering messages to a user over one channel
+type ChannelDelivery struct {
+	Channel string
+	// Status is DeliveryDelivered, DeliveryFailed, or DeliveryBounced
+	Status string
+	Err    error
+	At     time.Time
+}
+
+// DeliveryError is returned when no channel of a FallbackChannel delivered the messages
+type DeliveryError struct {
+	User string
+	// Deliveries are the failed attempts, one per channel tried
+	Deliveries []ChannelDelivery
+}
+
+func (e *DeliveryError) Error() string {
+	failures := make([]string, 0, len(e.Deliveries))
+	for _, delivery := range e.Deliveries {
+		if delivery.Err == nil {
+			failures = append(failures, delivery.Channel+": "+delivery.Status)
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("%v: %v", delivery.Channel, delivery.Err))
+	}
+	return fmt.Sprintf("docubot: no channel delivered to %v: %v", e.User, strings.Join(failures, ", "))
+}
+
+// FallbackChannel delivers over the first of its channels that works, such as SMS and then email, so an
+// interview doesn't stall when one channel bounces. The latest delivery status of each user is kept until Forget
+// is called, such as once their interview is complete. It is safe for concurrent use.
+type FallbackChannel struct {
+	// Channels are tried in order
+	Channels []MessageChannel
+	// OnFallback, when set, is called every time a channel fails and the next one is tried
+	OnFallback func(user string, failed ChannelDelivery)
+
+	mu    sync.Mutex
+	users map[string]*fallbackUser
+}
+
+// fallbackUser is the latest messages delivered to a user and how each channel did with them
+type fallbackUser struct {
+	messages   []string
+	deliveries []ChannelDelivery
+}
+
+// NewFallbackChannel initializes a chain trying the channels in order
+func NewFallbackChannel(channels ...MessageChannel) *FallbackChannel {
+	return &FallbackChannel{Channels: channels}
+}
+
+// Name names the chain after its channels, such as "sms,email"
+func (f *FallbackChannel) Name() string {
+	names := make([]string, 0, len(f.Channels))
+	for _, channel := range f.Channels {
+		names = append(names, channel.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// Deliver sends the messages over the first channel that accepts them, a DeliveryError is returned when none does
+func (f *FallbackChannel) Deliver(ctx context.Context, user string, messages []string) error {
+	f.mu.Lock()
+	if f.users == nil {
+		f.users = map[string]*fallbackUser{}
+	}
+	f.users[user] = &fallbackUser{messages: messages}
+	f.mu.Unlock()
+	return f.deliverFrom(ctx, user, messages, 0)
+}
+
+// Bounced records that the channel reported the user's latest delivery undeliverable, such as from an SMS
+// provider's delivery receipt, and delivers the messages over the channels after it
+func (f *FallbackChannel) Bounced(ctx context.Context, user string, channel string) error {
+	f.mu.Lock()
+	state := f.users[user]
+	if state == nil {
+		f.mu.Unlock()
+		return fmt.Errorf("docubot: no delivery to %v to bounce", user)
+	}
+	next := -1
+	bounced := ChannelDelivery{Channel: channel, Status: DeliveryBounced, At: time.Now()}
+	for i := len(state.deliveries) - 1; i >= 0; i-- {
+		if state.deliveries[i].Channel == channel {
+			state.deliveries[i].Status = DeliveryBounced
+			bounced = state.deliveries[i]
+			break
+		}
+	}
+	for i, c := range f.Channels {
+		if c.Name() == channel {
+			next = i + 1
+		}
+	}
+	messages := state.messages
+	f.mu.Unlock()
+	if next < 0 {
+		return fmt.Errorf("docubot: unknown channel %q", channel)
+	}
+	if next == len(f.Channels) {
+		return &DeliveryError{User: user, Deliveries: []ChannelDelivery{bounced}}
+	}
+	if f.OnFallback != nil {
+		f.OnFallback(user, bounced)
+	}
+	return f.deliverFrom(ctx, user, messages, next)
+}
+
+// Status returns how each channel did with the user's latest messages, in the order they were tried
+func (f *FallbackChannel) Status(user string) []ChannelDelivery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	state := f.users[user]
+	if state == nil {
+		return nil
+	}
+	return append([]ChannelDelivery(nil), state.deliveries...)
+}
+
+// Forget drops the user's delivery status
+func (f *FallbackChannel) Forget(user string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.users, user)
+}
+
+// deliverFrom tries the channels starting at the index until one delivers the messages
+func (f *FallbackChannel) deliverFrom(ctx context.Context, user string, messages []string, from int) error {
+	failed := &DeliveryError{User: user}
+	for i := from; i < len(f.Channels); i++ {
+		channel := f.Channels[i]
+		err := channel.Deliver(ctx, user, messages)
+		delivery := ChannelDelivery{Channel: channel.Name(), Status: DeliveryDelivered, Err: err, At: time.Now()}
+		if err != nil {
+			delivery.Status = DeliveryFailed
+		}
+		f.record(user, delivery)
+		if err == nil {
+			return nil
+		}
+		failed.Deliveries = append(failed.Deliveries, delivery)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if f.OnFallback != nil && i < len(f.Channels)-1 {
+			f.OnFallback(user, delivery)
+		}
+	}
+	return failed
+}
+
+func (f *FallbackChannel) record(user string, delivery ChannelDelivery) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if state := f.users[user]; state != nil {
+		state.deliveries = append(state.deliveries, delivery)
+	}
+}
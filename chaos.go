@@ -0,0 +1,182 @@
+package docubotlib
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Faults a FaultInjector injects
+const (
+	FaultLatency         string = "latency"
+	FaultTimeout         string = "timeout"
+	FaultServerError     string = "serverError"
+	FaultMalformedJSON   string = "malformedJson"
+	FaultConnectionReset string = "connectionReset"
+)
+
+// defaultFaultTimeout is how long an injected timeout hangs when FaultInjector.Timeout isn't set
+const defaultFaultTimeout time.Duration = 30 * time.Second
+
+// faultTimeoutError is the error of an injected timeout, it reports itself as a net.Error timeout
+type faultTimeoutError struct{}
+
+func (faultTimeoutError) Error() string {
+	return "docubot: injected timeout"
+}
+
+func (faultTimeoutError) Timeout() bool {
+	return true
+}
+
+func (faultTimeoutError) Temporary() bool {
+	return true
+}
+
+// FaultInjector is a test transport failing requests on purpose, so retry, hedging, and fallback settings can be
+// checked against latency, timeouts, outages, malformed responses, and dropped connections before production.
+// Add its Middleware to a client, rates are probabilities between 0 and 1 drawn for each request.
+// It is safe for concurrent use. Don't use it in production.
+type FaultInjector struct {
+	// LatencyRate requests are delayed by up to Latency before being sent
+	LatencyRate float64
+	Latency     time.Duration
+	// TimeoutRate requests aren't sent, they hang for Timeout, 30 seconds when zero, or until their context is done
+	// and fail with a timeout
+	TimeoutRate float64
+	Timeout     time.Duration
+	// ServerErrorRate requests start a burst of ServerErrorBurst requests, at least one, answered with a 503
+	// without being sent
+	ServerErrorRate  float64
+	ServerErrorBurst int
+	// MalformedJSONRate requests are sent but their response body is cut in half
+	MalformedJSONRate float64
+	// ConnectionResetRate requests are sent but the connection is reset before the response arrives,
+	// like a server that did the work and then dropped the connection
+	ConnectionResetRate float64
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	burst    int
+	injected map[string]int
+}
+
+// NewFaultInjector initializes an injector drawing its faults from seed, the same seed draws the same faults
+// for the same sequence of requests
+func NewFaultInjector(seed int64) *FaultInjector {
+	return &FaultInjector{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Injected returns how many times each fault has been injected
+func (f *FaultInjector) Injected() map[string]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	injected := make(map[string]int, len(f.injected))
+	for fault, n := range f.injected {
+		injected[fault] = n
+	}
+	return injected
+}
+
+// draw picks the faults of one request
+func (f *FaultInjector) draw() (latency time.Duration, faults map[string]bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rand == nil {
+		f.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if f.injected == nil {
+		f.injected = map[string]int{}
+	}
+	faults = map[string]bool{}
+	hit := func(fault string, rate float64) bool {
+		if rate > 0 && f.rand.Float64() < rate {
+			faults[fault] = true
+			f.injected[fault]++
+			return true
+		}
+		return false
+	}
+	if hit(FaultLatency, f.LatencyRate) && f.Latency > 0 {
+		latency = time.Duration(f.rand.Int63n(int64(f.Latency)))
+	}
+	if f.burst > 0 {
+		f.burst--
+		faults[FaultServerError] = true
+		f.injected[FaultServerError]++
+		return latency, faults
+	}
+	if hit(FaultServerError, f.ServerErrorRate) {
+		f.burst = f.ServerErrorBurst - 1
+		return latency, faults
+	}
+	// the faults are exclusive, the first drawn wins
+	_ = hit(FaultTimeout, f.TimeoutRate) || hit(FaultConnectionReset, f.ConnectionResetRate) || hit(FaultMalformedJSON, f.MalformedJSONRate)
+	return latency, faults
+}
+
+// Middleware injects the faults into every request sent through it
+func (f *FaultInjector) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			latency, faults := f.draw()
+			if latency > 0 {
+				if err := sleep(req.Context(), latency); err != nil {
+					return nil, err
+				}
+			}
+			switch {
+			case faults[FaultServerError]:
+				return faultResponse(req, http.StatusServiceUnavailable, `{"code":"INTERNAL","errors":["injected server error"]}`), nil
+			case faults[FaultTimeout]:
+				timeout := f.Timeout
+				if timeout <= 0 {
+					timeout = defaultFaultTimeout
+				}
+				if err := sleep(req.Context(), timeout); err != nil {
+					return nil, err
+				}
+				return nil, faultTimeoutError{}
+			}
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case faults[FaultConnectionReset]:
+				resp.Body.Close()
+				return nil, &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+			case faults[FaultMalformedJSON]:
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				resp.Body = io.NopCloser(bytes.NewReader(body[:len(body)/2]))
+				resp.ContentLength = int64(len(body) / 2)
+				resp.Header.Del("Content-Length")
+			}
+			return resp, nil
+		})
+	}
+}
+
+func faultResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
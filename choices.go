@@ -0,0 +1,103 @@
+package docubotlib
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// diacriticFolds maps letters with diacritics to the letter without them, for matching answers typed
+// on keyboards or transcribed from speech that drop them
+var diacriticFolds = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a', 'ā': 'a',
+	'ç': 'c', 'č': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e', 'ē': 'e', 'ę': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ī': 'i',
+	'ñ': 'n', 'ń': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o', 'ō': 'o',
+	'ś': 's', 'š': 's',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u', 'ū': 'u',
+	'ý': 'y', 'ÿ': 'y',
+	'ź': 'z', 'ż': 'z', 'ž': 'z',
+}
+
+// foldChoice lowercases s, drops diacritics and punctuation, and collapses whitespace
+func foldChoice(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if folded, ok := diacriticFolds[r]; ok {
+			r = folded
+		}
+		switch {
+		case r == 'ß':
+			b.WriteString("ss")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// ChoiceKeys returns the keys of a multiple choice question in the order ChoiceAnswer numbers them, sorted
+func ChoiceKeys(question *QuestionNode) []string {
+	if question.MetaData == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(question.MetaData.Choices))
+	for key := range question.MetaData.Choices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ChoiceAnswer returns the key of the multiple choice question's choice the input picks, so channels such as
+// sms and voice can be forgiving about input. The input may be a choice's key, its label, or its number
+// counting from 1 in ChoiceKeys order, matched ignoring case, diacritics, and punctuation.
+// Numbers are read the way the locale writes them.
+func ChoiceAnswer(locale Locale, question *QuestionNode, input string) (string, error) {
+	keys := ChoiceKeys(question)
+	for _, key := range keys {
+		if key == input {
+			return key, nil
+		}
+	}
+	folded := foldChoice(input)
+	if folded == "" {
+		return "", fmt.Errorf("docubot: %q is not one of the choices", input)
+	}
+	for _, key := range keys {
+		if foldChoice(key) == folded {
+			return key, nil
+		}
+	}
+	var labelMatch []string
+	for _, key := range keys {
+		if foldChoice(question.MetaData.Choices[key]) == folded {
+			labelMatch = append(labelMatch, key)
+		}
+	}
+	if len(labelMatch) == 1 {
+		return labelMatch[0], nil
+	}
+	if len(labelMatch) > 1 {
+		return "", fmt.Errorf("docubot: %q matches more than one choice", input)
+	}
+	number, err := locale.ParseNumber(strings.Trim(strings.TrimSpace(input), "#.)"))
+	if err != nil {
+		return "", fmt.Errorf("docubot: %q is not one of the choices", input)
+	}
+	for _, key := range keys {
+		if canonical, err := locale.ParseNumber(key); err == nil && canonical == number {
+			return key, nil
+		}
+	}
+	if i, err := strconv.Atoi(number); err == nil && i >= 1 && i <= len(keys) {
+		return keys[i-1], nil
+	}
+	return "", fmt.Errorf("docubot: %q is not one of the choices", input)
+}
@@ -0,0 +1,159 @@
+// Command docubot runs diagnostics and maintenance against a docubot account.
+//
+//	docubot doctor [-url url] [-key key] [-secret secret] [-webhook url]...
+//	docubot reap -inactive duration [-tree id] [-export dir] [-archive] [-dry-run]
+//
+// The url, key, and secret default to the DOCUBOT_URL, DOCUBOT_API_KEY, and DOCUBOT_API_SECRET environment variables.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	docubotlib "github.com/auxai/docubot-go"
+)
+
+// defaultDocubotURL is used when neither -url nor DOCUBOT_URL is set
+const defaultDocubotURL string = "https://docubotapi.1law.com"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "doctor":
+			return doctor(args[1:], stdout, stderr)
+		case "reap":
+			return reap(args[1:], stdout, stderr)
+		}
+	}
+	fmt.Fprintln(stderr, "usage: docubot doctor|reap [flags]")
+	return 2
+}
+
+// clientFlags are the flags every command takes to reach docubot
+type clientFlags struct {
+	url     *string
+	key     *string
+	secret  *string
+	timeout *time.Duration
+}
+
+func addClientFlags(flags *flag.FlagSet, timeout time.Duration) clientFlags {
+	return clientFlags{
+		url:     flags.String("url", env("DOCUBOT_URL", defaultDocubotURL), "docubot's api url"),
+		key:     flags.String("key", os.Getenv("DOCUBOT_API_KEY"), "api key"),
+		secret:  flags.String("secret", os.Getenv("DOCUBOT_API_SECRET"), "api secret"),
+		timeout: flags.Duration("timeout", timeout, "how long the command can take"),
+	}
+}
+
+func (f clientFlags) client() *docubotlib.Client {
+	return docubotlib.NewClient(*f.url, *f.key, *f.secret)
+}
+
+// stringList collects a flag given more than once
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// doctor prints the findings of Client.Doctor, it exits with 1 when a check found a problem
+func doctor(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	conn := addClientFlags(flags, 30*time.Second)
+	var webhooks stringList
+	flags.Var(&webhooks, "webhook", "webhook endpoint to check, the registered endpoints when not given, repeatable")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *conn.timeout)
+	defer cancel()
+	report := conn.client().Doctor(ctx, docubotlib.DoctorOptions{WebhookURLs: webhooks})
+	for _, finding := range report.Findings {
+		fmt.Fprintf(stdout, "[%v] %v: %v\n", finding.Severity, finding.Check, finding.Message)
+		if finding.Fix != "" {
+			fmt.Fprintf(stdout, "    fix: %v\n", finding.Fix)
+		}
+	}
+	if !report.Healthy() {
+		return 1
+	}
+	return 0
+}
+
+// reap deletes or archives stale threads, it exits with 1 when a thread couldn't be reaped
+func reap(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("reap", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	conn := addClientFlags(flags, time.Hour)
+	inactive := flags.Duration("inactive", 0, "how long an unfinished thread can go without a message, such as 720h")
+	tree := flags.String("tree", "", "only reap threads of this tree")
+	export := flags.String("export", "", "directory the threads' snapshots are written to before they are reaped")
+	archive := flags.Bool("archive", false, "label the threads archived instead of deleting them")
+	dryRun := flags.Bool("dry-run", false, "list the stale threads without reaping them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *inactive <= 0 {
+		fmt.Fprintln(stderr, "reap: -inactive is required")
+		return 2
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *conn.timeout)
+	defer cancel()
+	reaper := &docubotlib.ThreadReaper{
+		Client:      conn.client(),
+		InactiveFor: *inactive,
+		Threads:     docubotlib.ListThreadsOptions{DocumentTreeID: *tree},
+		Archive:     *archive,
+	}
+	if *export != "" {
+		reaper.Export = docubotlib.NewFileStore(*export)
+	}
+	if *dryRun {
+		threads, err := reaper.Stale(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		for _, thread := range threads {
+			fmt.Fprintf(stdout, "%v\t%v\tinactive since %v\n", thread.ID, thread.UserID, thread.UpdatedAt.Format(time.RFC3339))
+		}
+		return 0
+	}
+	report, err := reaper.Run(ctx)
+	if report != nil {
+		for _, id := range report.Reaped {
+			fmt.Fprintf(stdout, "reaped %v\n", id)
+		}
+		for id, failure := range report.Failed {
+			fmt.Fprintf(stderr, "failed %v: %v\n", id, failure)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func env(name string, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
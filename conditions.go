@@ -0,0 +1,112 @@
+package docubotlib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Logical operators combining conditions
+const (
+	LogicalOperatorAnd string = "and"
+	LogicalOperatorOr  string = "or"
+)
+
+// Comparators used by conditions
+const (
+	ComparatorEqual              string = "=="
+	ComparatorNotEqual           string = "!="
+	ComparatorGreaterThan        string = ">"
+	ComparatorGreaterThanOrEqual string = ">="
+	ComparatorLessThan           string = "<"
+	ComparatorLessThanOrEqual    string = "<="
+	ComparatorContains           string = "contains"
+)
+
+// ValidComparator reports whether the comparator is understood by EvaluateCondition
+func ValidComparator(comparator string) bool {
+	switch comparator {
+	case ComparatorEqual, ComparatorNotEqual,
+		ComparatorGreaterThan, ComparatorGreaterThanOrEqual,
+		ComparatorLessThan, ComparatorLessThanOrEqual,
+		ComparatorContains:
+		return true
+	}
+	return false
+}
+
+// ValidLogicalOperator reports whether the operator is understood by EvaluateConditions, empty means and
+func ValidLogicalOperator(operator string) bool {
+	switch strings.ToLower(operator) {
+	case "", LogicalOperatorAnd, LogicalOperatorOr:
+		return true
+	}
+	return false
+}
+
+// EvaluateConditions reports whether the conditions hold for the variables.
+// An empty list of conditions always holds.
+func EvaluateConditions(operator string, conditions []QuestionCondition, variables map[string]interface{}) bool {
+	if len(conditions) == 0 {
+		return true
+	}
+	or := strings.ToLower(operator) == LogicalOperatorOr
+	for _, condition := range conditions {
+		holds := EvaluateCondition(condition, variables)
+		if or && holds {
+			return true
+		}
+		if !or && !holds {
+			return false
+		}
+	}
+	return !or
+}
+
+// EvaluateCondition reports whether a single condition holds for the variables.
+// Values that both parse as numbers are compared numerically, otherwise they are compared as text.
+// A condition on a variable that hasn't been answered only holds for !=.
+func EvaluateCondition(condition QuestionCondition, variables map[string]interface{}) bool {
+	raw, ok := variables[condition.VariableName]
+	if !ok || raw == nil {
+		return condition.Comparator == ComparatorNotEqual
+	}
+	value := fmt.Sprintf("%v", raw)
+	if condition.Comparator == ComparatorContains {
+		return strings.Contains(strings.ToLower(value), strings.ToLower(condition.Value))
+	}
+	var cmp int
+	a, aErr := strconv.ParseFloat(value, 64)
+	b, bErr := strconv.ParseFloat(condition.Value, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		cmp = compareFloats(a, b)
+	default:
+		cmp = strings.Compare(strings.ToLower(value), strings.ToLower(condition.Value))
+	}
+	switch condition.Comparator {
+	case ComparatorEqual:
+		return cmp == 0
+	case ComparatorNotEqual:
+		return cmp != 0
+	case ComparatorGreaterThan:
+		return cmp > 0
+	case ComparatorGreaterThanOrEqual:
+		return cmp >= 0
+	case ComparatorLessThan:
+		return cmp < 0
+	case ComparatorLessThanOrEqual:
+		return cmp <= 0
+	}
+	return false
+}
+
+func compareFloats(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package docubotlib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// ClientConfig is the effective configuration of a client with its secret masked, comparing it between replicas
+// shows how their environments drifted apart. It is safe to log.
+type ClientConfig struct {
+	APIURL          string `json:"apiUrl"`
+	PreviewAPIURL   string `json:"previewApiUrl,omitempty"`
+	MessagesAPIURL  string `json:"messagesApiUrl,omitempty"`
+	DocumentsAPIURL string `json:"documentsApiUrl,omitempty"`
+	APIKey          string `json:"apiKey"`
+	// APISecret is masked when set, empty when it isn't
+	APISecret string `json:"apiSecret,omitempty"`
+	// Timeout is the HTTPClient's timeout, empty when requests have none
+	Timeout      string `json:"timeout,omitempty"`
+	MaxRetries   int    `json:"maxRetries"`
+	RetryBackoff string `json:"retryBackoff,omitempty"`
+	HedgeDelay   string `json:"hedgeDelay,omitempty"`
+	DryRun       bool   `json:"dryRun"`
+	ReadOnly     bool   `json:"readOnly"`
+	SoftDelete   bool   `json:"softDelete"`
+	UseNumber    bool   `json:"useNumber"`
+	Location     string `json:"location,omitempty"`
+	// Middleware names the functions that built each middleware, such as "(*SLOTracker).Middleware"
+	Middleware  []string `json:"middleware,omitempty"`
+	Transforms  int      `json:"transforms"`
+	Normalizers int      `json:"answerNormalizers"`
+	// Limiter and Encoder are the types of the client's Limiter and Encoder, empty when they aren't set
+	Limiter   string   `json:"limiter,omitempty"`
+	Encoder   string   `json:"encoder,omitempty"`
+	Redactor  bool     `json:"redactor"`
+	Sanitizer bool     `json:"sanitizer"`
+	Encrypted []string `json:"encryptedVariables,omitempty"`
+	// CallHistory is how many calls the client keeps for RecentCalls
+	CallHistory int `json:"callHistory"`
+}
+
+// Config returns the client's effective configuration, with its secret masked
+func (c *Client) Config() ClientConfig {
+	config := ClientConfig{
+		APIURL:          c.DocubotAPIURLBase,
+		PreviewAPIURL:   c.DocubotPreviewAPIURLBase,
+		MessagesAPIURL:  c.DocubotMessagesAPIURLBase,
+		DocumentsAPIURL: c.DocubotDocumentsAPIURLBase,
+		APIKey:          c.DocubotAPIKey,
+		MaxRetries:      c.MaxRetries,
+		DryRun:          c.DryRun,
+		ReadOnly:        c.ReadOnly,
+		SoftDelete:      c.SoftDelete,
+		UseNumber:       c.UseNumber,
+		Transforms:      len(c.Transforms),
+		Normalizers:     len(c.AnswerNormalizers),
+		Redactor:        c.Redactor != nil,
+		Sanitizer:       c.Sanitizer != nil,
+		CallHistory:     c.CallHistory,
+	}
+	if c.DocubotAPISecret != "" {
+		config.APISecret = redactedValue
+	}
+	if c.HTTPClient != nil && c.HTTPClient.Timeout > 0 {
+		config.Timeout = c.HTTPClient.Timeout.String()
+	}
+	if c.RetryBackoff > 0 {
+		config.RetryBackoff = c.RetryBackoff.String()
+	}
+	if c.HedgeDelay > 0 {
+		config.HedgeDelay = c.HedgeDelay.String()
+	}
+	if c.Location != nil {
+		config.Location = c.Location.String()
+	}
+	for _, middleware := range c.Middleware {
+		config.Middleware = append(config.Middleware, middlewareName(middleware))
+	}
+	if c.Limiter != nil {
+		config.Limiter = fmt.Sprintf("%T", c.Limiter)
+	}
+	if c.Encoder != nil {
+		config.Encoder = fmt.Sprintf("%T", c.Encoder)
+	}
+	if c.VariableEncryption != nil {
+		config.Encrypted = c.VariableEncryption.Variables
+	}
+	return config
+}
+
+// Fingerprint returns a short hash of the configuration, replicas configured alike have the same fingerprint
+func (c ClientConfig) Fingerprint() string {
+	data, _ := json.Marshal(c)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// middlewareName names the function that built the middleware, without its package path and the suffix of
+// the closure it returned, such as "(*SLOTracker).Middleware"
+func middlewareName(middleware Middleware) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,137 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Polling of reads waiting for a version of a thread's variables
+const (
+	defaultConsistencyWait     time.Duration = 5 * time.Second
+	consistencyPollInterval    time.Duration = 100 * time.Millisecond
+	maxConsistencyPollInterval time.Duration = time.Second
+	// maxTrackedWrites is how many threads' write versions a client remembers for WithReadYourWrites
+	maxTrackedWrites int = 4096
+)
+
+// StaleReadError is returned when a thread's variables didn't reach the version a read waited for in time,
+// the stale variables are still returned with it
+type StaleReadError struct {
+	Thread     string
+	Version    int64
+	MinVersion int64
+}
+
+func (e *StaleReadError) Error() string {
+	return fmt.Sprintf("docubot: variables of thread %v are at version %v, waited for version %v", e.Thread, e.Version, e.MinVersion)
+}
+
+// WithReadYourWrites makes GetDocubotVariables wait until the thread's variables reflect every write this client
+// made to them with SetDocubotVariables and SendMessage, such as right before generating the thread's document
+func WithReadYourWrites() CallOption {
+	return func(o *callOptions) {
+		o.readYourWrites = true
+	}
+}
+
+// WithMinVariablesVersion makes GetDocubotVariables wait until the thread's variables are at version or later,
+// such as the Version of a write made by another process
+func WithMinVariablesVersion(version int64) CallOption {
+	return func(o *callOptions) {
+		if version > o.minVariablesVersion {
+			o.minVariablesVersion = version
+		}
+	}
+}
+
+// WithConsistencyWait bounds how long a read waits for the version of the variables it needs, 5 seconds by
+// default. A StaleReadError is returned once it runs out.
+func WithConsistencyWait(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.consistencyWait = d
+	}
+}
+
+// minVariablesVersion returns the version a read of the thread's variables has to wait for, zero when it doesn't
+func (c *Client) minVariablesVersion(ctx context.Context, thread string) int64 {
+	o := c.resolveCallOptions(ctx)
+	min := o.minVariablesVersion
+	if o.readYourWrites {
+		c.mu.Lock()
+		if written := c.writtenVersions[thread]; written > min {
+			min = written
+		}
+		c.mu.Unlock()
+	}
+	return min
+}
+
+// wroteVariables remembers the version of the thread's variables a write produced
+func (c *Client) wroteVariables(thread string, version int64) {
+	if version <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writtenVersions == nil {
+		c.writtenVersions = map[string]int64{}
+	}
+	if _, ok := c.writtenVersions[thread]; !ok && len(c.writtenVersions) >= maxTrackedWrites {
+		for forgotten := range c.writtenVersions {
+			delete(c.writtenVersions, forgotten)
+			break
+		}
+	}
+	if version > c.writtenVersions[thread] {
+		c.writtenVersions[thread] = version
+	}
+}
+
+// readVariablesVersion gets the thread's variables until they are at min or later, polling with a growing
+// interval. docubot is asked to hold the read until then with the minVersion parameter. Variables without a
+// version, from a docubot that doesn't report them, are returned as they are.
+func (c *Client) readVariablesVersion(ctx context.Context, thread string, user string, min int64) (*DocumentVariablesResponse, error) {
+	params := url.Values{}
+	params.Set("user", user)
+	if min > 0 {
+		params.Set("minVersion", strconv.FormatInt(min, 10))
+	}
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/variables?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+	wait := c.resolveCallOptions(ctx).consistencyWait
+	if wait <= 0 {
+		wait = defaultConsistencyWait
+	}
+	deadline := time.Now().Add(wait)
+	interval := consistencyPollInterval
+	for {
+		req, err := c.newRequest(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		var response DocumentVariablesResponse
+		if err = c.doJSON(req, nil, &response); err != nil {
+			return &response, err
+		}
+		version := response.Meta.Version
+		if min <= 0 || version <= 0 || version >= min {
+			return &response, nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return &response, &StaleReadError{Thread: thread, Version: version, MinVersion: min}
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return &response, err
+		}
+		if interval *= 2; interval > maxConsistencyPollInterval {
+			interval = maxConsistencyPollInterval
+		}
+	}
+}
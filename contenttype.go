@@ -0,0 +1,111 @@
+package docubotlib
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLength is the number of leading bytes inspected to detect a document's content type
+const sniffLength int = 2048
+
+// Content types of documents generated by docubot
+const (
+	ContentTypePDF  string = "application/pdf"
+	ContentTypeDOCX string = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	ContentTypeHTML string = "text/html"
+	ContentTypeRTF  string = "application/rtf"
+)
+
+var extensionsByContentType = map[string]string{
+	ContentTypePDF:       ".pdf",
+	ContentTypeDOCX:      ".docx",
+	ContentTypeHTML:      ".html",
+	ContentTypeRTF:       ".rtf",
+	"application/msword": ".doc",
+	"application/zip":    ".zip",
+	"text/plain":         ".txt",
+	"image/png":          ".png",
+	"image/jpeg":         ".jpg",
+}
+
+// DetectContentType returns the content type of a document from its Content-Type header,
+// falling back to the document's leading bytes when the header is missing or generic
+func DetectContentType(header string, head []byte) string {
+	if mediaType, _, err := mime.ParseMediaType(header); err == nil && !genericContentType(mediaType) {
+		return mediaType
+	}
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return ContentTypePDF
+	case bytes.HasPrefix(head, []byte("{\\rtf")):
+		return ContentTypeRTF
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		if bytes.Contains(head, []byte("word/")) {
+			return ContentTypeDOCX
+		}
+		return "application/zip"
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	return mediaType
+}
+
+func genericContentType(mediaType string) bool {
+	switch mediaType {
+	case "", "application/octet-stream", "binary/octet-stream", "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
+
+// ExtensionForContentType returns the file extension, including the dot, to save a document of the content type with
+func ExtensionForContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	if ext, ok := extensionsByContentType[mediaType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// DocumentDownload is a document being downloaded from docubot, the caller must close Body
+type DocumentDownload struct {
+	Body io.ReadCloser
+	// ContentType is the detected content type of the document
+	ContentType string
+	// Extension is the suggested file extension for the document, including the dot
+	Extension string
+	// Filename is the filename suggested by docubot, if any
+	Filename string
+	// PDFUA reports whether docubot says the document conforms to PDF/UA
+	PDFUA bool
+}
+
+// newDocumentDownload detects the content type of a successful download response
+func newDocumentDownload(resp *http.Response) *DocumentDownload {
+	buffered := bufio.NewReaderSize(resp.Body, sniffLength)
+	head, _ := buffered.Peek(sniffLength)
+	download := &DocumentDownload{
+		Body:        readCloser{Reader: buffered, Closer: resp.Body},
+		ContentType: DetectContentType(resp.Header.Get("Content-Type"), head),
+		PDFUA:       pdfUA(resp.Header),
+	}
+	download.Extension = ExtensionForContentType(download.ContentType)
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		download.Filename = params["filename"]
+	}
+	return download
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
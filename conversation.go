@@ -0,0 +1,192 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Directions of a ConversationMessage
+const (
+	// MessageOutgoing is an answer the conversation's user is sending to docubot
+	MessageOutgoing string = "outgoing"
+	// MessageIncoming is a reply message from docubot about to be delivered
+	MessageIncoming string = "incoming"
+)
+
+// ErrMessageRejected is returned by Send when a middleware rejected a message
+var ErrMessageRejected = errors.New("docubot: message rejected")
+
+// SSNPattern matches social security numbers written with dashes or spaces, for RejectPattern
+var SSNPattern = regexp.MustCompile(`\b\d{3}[- ]\d{2}[- ]\d{4}\b`)
+
+// ConversationMessage is a message passing through a Conversation's middleware
+type ConversationMessage struct {
+	Direction string
+	Thread    string
+	Sender    string
+	// Text is the message, middleware may rewrite it
+	Text string
+}
+
+// MessageMiddleware inspects or rewrites a message of a Conversation. Returning an error stops the message,
+// an outgoing answer isn't sent and an incoming message isn't delivered, and Send returns the error.
+type MessageMiddleware func(ctx context.Context, message *ConversationMessage) error
+
+// NormalizeWhitespace trims outgoing answers and collapses runs of whitespace inside them
+func NormalizeWhitespace(ctx context.Context, message *ConversationMessage) error {
+	if message.Direction == MessageOutgoing {
+		message.Text = strings.Join(strings.Fields(message.Text), " ")
+	}
+	return nil
+}
+
+// RejectPattern returns a middleware rejecting outgoing answers that match pattern, such as SSNPattern,
+// with an ErrMessageRejected giving reason
+func RejectPattern(pattern *regexp.Regexp, reason string) MessageMiddleware {
+	return func(ctx context.Context, message *ConversationMessage) error {
+		if message.Direction == MessageOutgoing && pattern.MatchString(message.Text) {
+			return fmt.Errorf("%w: %v", ErrMessageRejected, reason)
+		}
+		return nil
+	}
+}
+
+// MessageDelay returns docubot's suggested pause before showing the i-th reply message, zero when it suggested none
+func (d *MessageResponseData) MessageDelay(i int) time.Duration {
+	if i < 0 || i >= len(d.MessageDelaysMs) || d.MessageDelaysMs[i] < 0 {
+		return 0
+	}
+	return time.Duration(d.MessageDelaysMs[i]) * time.Millisecond
+}
+
+// Conversation is one user's chat on a thread, it delivers docubot's replies one message at a time
+// with the pauses docubot suggests between them, so a chat ui can show them as they would be typed
+type Conversation struct {
+	Client         *Client
+	Thread         string
+	Sender         string
+	DocumentTreeID string
+	// IgnorePacing delivers every reply message at once, ignoring the suggested pauses
+	IgnorePacing bool
+	// Middleware sees every message of the conversation in order, outgoing answers before they are sent
+	// and replies before they are delivered
+	Middleware []MessageMiddleware
+	// Tree is the conversation's document tree, Resolvers use it to tell which question an answer is for
+	Tree *DocumentTree
+	// Trees, when set and Tree isn't, is where the conversation's tree is loaded from
+	Trees *TreeCache
+	// Resolvers derive variables from answers before they are sent, keyed by the variable the question asks for
+	Resolvers map[string]DataResolver
+
+	// OnQuestion is called with the question docubot asks next, the last reply message, once it's delivered
+	OnQuestion func(question string)
+	// OnAnswerAccepted is called when docubot accepts an answer
+	OnAnswerAccepted func(answer string)
+	// OnValidationError is called when docubot rejects an answer
+	OnValidationError func(answer string, err *ValidationError)
+	// OnComplete is called once the interview is complete
+	OnComplete func(response *MessageResponse)
+	// OnDocumentReady is called with the thread's documents once they are generated
+	OnDocumentReady func(documents []ThreadDocument)
+
+	completed     bool
+	documentReady bool
+}
+
+// NewConversation starts a conversation of sender on the thread
+func (c *Client) NewConversation(thread string, sender string, docTreeID string) *Conversation {
+	return &Conversation{Client: c, Thread: thread, Sender: sender, DocumentTreeID: docTreeID}
+}
+
+// Send sends the message and calls deliver with each reply message once its suggested pause has passed.
+// Delivery stops when deliver or a middleware returns an error or ctx is done, the error is returned with the response.
+// The answer's resolver, if any, runs after the middleware and before the answer is sent.
+func (conv *Conversation) Send(ctx context.Context, message string, deliver func(message string) error, callOpts ...CallOption) (*MessageResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	message, err := conv.filter(ctx, MessageOutgoing, message)
+	if err != nil {
+		return nil, err
+	}
+	if err := conv.resolve(ctx, message); err != nil {
+		return nil, err
+	}
+	sent, err := conv.encrypt(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	response, err := conv.Client.sendMessage(ctx, sent, conv.Thread, conv.Sender, conv.DocumentTreeID, "", "")
+	var validation *ValidationError
+	if errors.As(err, &validation) && conv.OnValidationError != nil {
+		conv.OnValidationError(message, validation)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if conv.OnAnswerAccepted != nil {
+		conv.OnAnswerAccepted(message)
+	}
+	for i, reply := range response.Data.Messages {
+		if delay := response.Data.MessageDelay(i); delay > 0 && !conv.IgnorePacing {
+			if err := sleep(ctx, delay); err != nil {
+				return response, err
+			}
+		}
+		reply, err := conv.filter(ctx, MessageIncoming, reply)
+		if err != nil {
+			return response, err
+		}
+		if err := deliver(reply); err != nil {
+			return response, err
+		}
+		if i == len(response.Data.Messages)-1 && !response.Data.Complete && conv.OnQuestion != nil {
+			conv.OnQuestion(reply)
+		}
+	}
+	if response.Data.Complete && !conv.completed {
+		conv.completed = true
+		if conv.OnComplete != nil {
+			conv.OnComplete(response)
+		}
+	}
+	if response.Data.HasDocument && !conv.documentReady {
+		conv.documentReady = true
+		if conv.OnDocumentReady != nil {
+			conv.OnDocumentReady(response.Data.Documents)
+		}
+	}
+	return response, nil
+}
+
+// pendingQuestion returns the question of the conversation's tree docubot is asking, found by replaying
+// the thread's variables through a Simulation, with a copy of the variables. It is nil when the
+// interview is complete.
+func (conv *Conversation) pendingQuestion(ctx context.Context) (*QuestionNode, map[string]interface{}, error) {
+	tree := conv.Tree
+	if tree == nil {
+		var err error
+		if tree, err = conv.Trees.Get(ctx, conv.DocumentTreeID); err != nil {
+			return nil, nil, err
+		}
+	}
+	variables, err := conv.Client.getDocubotVariables(ctx, conv.Thread, conv.Sender)
+	if err != nil {
+		return nil, nil, err
+	}
+	question := NewSimulation(tree, variables.Data.Variables).Next()
+	return question, copyVariables(variables.Data.Variables), nil
+}
+
+// filter passes a message through the conversation's middleware
+func (conv *Conversation) filter(ctx context.Context, direction string, text string) (string, error) {
+	message := &ConversationMessage{Direction: direction, Thread: conv.Thread, Sender: conv.Sender, Text: text}
+	for _, middleware := range conv.Middleware {
+		if err := middleware(ctx, message); err != nil {
+			return "", err
+		}
+	}
+	return message.Text, nil
+}
@@ -0,0 +1,188 @@
+package docubotlib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scenario is a simulated interview of a tree, answering each question it reaches from Answers
+type Scenario struct {
+	Name    string
+	Answers map[string]interface{}
+}
+
+// Scenario returns a scenario answering the fixture's tree with the fixture's variables
+func (f Fixture) Scenario() Scenario {
+	return Scenario{Name: f.Name, Answers: f.Variables}
+}
+
+// ScenarioResult is how a scenario's simulated interview went
+type ScenarioResult struct {
+	Scenario string
+	// Asked are the variables asked for, in order
+	Asked []string
+	// Unanswered is the question the interview stopped at because the scenario has no answer for it,
+	// empty when the interview completed
+	Unanswered string
+}
+
+// QuestionCoverage is how the scenarios exercised one question of a tree
+type QuestionCoverage struct {
+	Variable   string
+	Conditions []ConditionCoverage
+	// Asked counts the scenarios that asked the question
+	Asked int
+	// Skipped counts the scenarios that reached the question and skipped it because its conditions didn't hold
+	Skipped int
+}
+
+// ConditionCoverage counts the scenarios in which a condition of a question was seen to hold, and not to hold
+type ConditionCoverage struct {
+	Condition QuestionCondition
+	True      int
+	False     int
+}
+
+// Branches returns how many branches the question has, asking it and, when it has conditions, skipping it,
+// and how many of them a scenario took
+func (q *QuestionCoverage) Branches() (covered int, total int) {
+	total = 1
+	if q.Asked > 0 {
+		covered++
+	}
+	if len(q.Conditions) > 0 {
+		total++
+		if q.Skipped > 0 {
+			covered++
+		}
+	}
+	return covered, total
+}
+
+// TreeCoverage is the branch coverage of a tree by a suite of scenarios
+type TreeCoverage struct {
+	// Questions are the tree's questions in tree order
+	Questions []QuestionCoverage
+	Results   []ScenarioResult
+}
+
+// SimulateScenarios runs each scenario as a simulated interview of the tree and reports which of the tree's
+// branches, whether each question is asked or skipped, the scenarios exercised
+func SimulateScenarios(tree *DocumentTree, scenarios []Scenario) *TreeCoverage {
+	coverage := &TreeCoverage{}
+	index := map[*QuestionNode]int{}
+	tree.Walk(func(node *QuestionNode) {
+		if node.VariableName == "" {
+			return
+		}
+		index[node] = len(coverage.Questions)
+		question := QuestionCoverage{Variable: node.VariableName}
+		for _, condition := range node.Conditions {
+			question.Conditions = append(question.Conditions, ConditionCoverage{Condition: condition})
+		}
+		coverage.Questions = append(coverage.Questions, question)
+	})
+	for _, scenario := range scenarios {
+		// outcomes seen in this scenario, so a question counts once however often it is evaluated
+		skipped := map[int]bool{}
+		conditions := map[[3]int]bool{}
+		simulation := NewSimulation(tree, nil)
+		simulation.evaluated = func(node *QuestionNode, holds bool, outcomes []bool) {
+			i, ok := index[node]
+			if !ok {
+				return
+			}
+			if !holds {
+				skipped[i] = true
+			}
+			for j, outcome := range outcomes {
+				value := 0
+				if outcome {
+					value = 1
+				}
+				conditions[[3]int{i, j, value}] = true
+			}
+		}
+		result := ScenarioResult{Scenario: scenario.Name}
+		for question := simulation.Next(); question != nil; question = simulation.Next() {
+			answer, ok := scenario.Answers[question.VariableName]
+			if !ok {
+				result.Unanswered = question.VariableName
+				break
+			}
+			simulation.Answer(answer)
+		}
+		result.Asked = simulation.Asked()
+		asked := map[string]bool{}
+		for _, v := range result.Asked {
+			asked[v] = true
+		}
+		for i := range coverage.Questions {
+			question := &coverage.Questions[i]
+			if asked[question.Variable] {
+				question.Asked++
+			}
+			if skipped[i] {
+				question.Skipped++
+			}
+			for j := range question.Conditions {
+				if conditions[[3]int{i, j, 1}] {
+					question.Conditions[j].True++
+				}
+				if conditions[[3]int{i, j, 0}] {
+					question.Conditions[j].False++
+				}
+			}
+		}
+		coverage.Results = append(coverage.Results, result)
+	}
+	return coverage
+}
+
+// Percent returns the percentage of the tree's branches the scenarios took
+func (c *TreeCoverage) Percent() float64 {
+	covered, total := 0, 0
+	for i := range c.Questions {
+		questionCovered, questionTotal := c.Questions[i].Branches()
+		covered += questionCovered
+		total += questionTotal
+	}
+	if total == 0 {
+		return 100
+	}
+	return float64(covered) * 100 / float64(total)
+}
+
+// Uncovered describes each branch of the tree no scenario took
+func (c *TreeCoverage) Uncovered() []string {
+	var uncovered []string
+	for _, question := range c.Questions {
+		if question.Asked == 0 {
+			uncovered = append(uncovered, fmt.Sprintf("%v is never asked", question.Variable))
+		}
+		if len(question.Conditions) > 0 && question.Skipped == 0 {
+			uncovered = append(uncovered, fmt.Sprintf("%v is never skipped", question.Variable))
+		}
+	}
+	return uncovered
+}
+
+// String writes a coverage report with a line per question and its conditions
+func (c *TreeCoverage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v scenarios, %.1f%% of branches covered\n", len(c.Results), c.Percent())
+	for _, question := range c.Questions {
+		covered, total := question.Branches()
+		fmt.Fprintf(&b, "%v: asked %v, skipped %v (%v/%v branches)\n", question.Variable, question.Asked, question.Skipped, covered, total)
+		for _, condition := range question.Conditions {
+			fmt.Fprintf(&b, "\t%v %v %v: true %v, false %v\n", condition.Condition.VariableName, condition.Condition.Comparator,
+				condition.Condition.Value, condition.True, condition.False)
+		}
+	}
+	for _, result := range c.Results {
+		if result.Unanswered != "" {
+			fmt.Fprintf(&b, "scenario %v has no answer for %v\n", result.Scenario, result.Unanswered)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package docubotlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of money held as a whole number of cents, so amounts never drift the way
+// floating point does. It is encoded in json as docubot's canonical number text, such as "1234.56".
+type Money struct {
+	// Cents is the amount in hundredths of the currency's unit
+	Cents int64
+	// Currency is the ISO 4217 code of the currency, such as "USD", empty when the amount's currency is implied
+	Currency string
+}
+
+// ParseMoney parses a canonical number, such as "1234.56", into an amount of money. Amounts with fractions
+// of a cent are rejected rather than rounded.
+func ParseMoney(canonical string, currency string) (Money, error) {
+	if !canonicalNumberPattern.MatchString(canonical) {
+		return Money{}, fmt.Errorf("docubot: %q is not an amount of money", canonical)
+	}
+	negative := strings.HasPrefix(canonical, "-")
+	digits := strings.TrimPrefix(canonical, "-")
+	integer, fraction := digits, ""
+	if i := strings.Index(digits, "."); i >= 0 {
+		integer, fraction = digits[:i], digits[i+1:]
+	}
+	if len(fraction) > 2 {
+		if strings.Trim(fraction[2:], "0") != "" {
+			return Money{}, fmt.Errorf("docubot: %q has a fraction of a cent", canonical)
+		}
+		fraction = fraction[:2]
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	cents, err := strconv.ParseInt(integer+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("docubot: %q is too large an amount of money", canonical)
+	}
+	if negative {
+		cents = -cents
+	}
+	return Money{Cents: cents, Currency: currency}, nil
+}
+
+// String returns the amount as a canonical number with two decimal places, such as "1234.56"
+func (m Money) String() string {
+	cents := m.Cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+	}
+	units, fraction := cents/100, cents%100
+	if cents < 0 {
+		// math.MinInt64 can't be negated, so the parts are negated after dividing
+		units, fraction = -units, -fraction
+	}
+	return fmt.Sprintf("%v%d.%02d", sign, units, fraction)
+}
+
+// Add returns the sum of the amounts, they must be in the same currency
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency && m.Currency != "" && o.Currency != "" {
+		return Money{}, fmt.Errorf("docubot: can't add %v to %v", o.Currency, m.Currency)
+	}
+	if (o.Cents > 0 && m.Cents > math.MaxInt64-o.Cents) || (o.Cents < 0 && m.Cents < math.MinInt64-o.Cents) {
+		return Money{}, fmt.Errorf("docubot: %v plus %v overflows", m, o)
+	}
+	currency := m.Currency
+	if currency == "" {
+		currency = o.Currency
+	}
+	return Money{Cents: m.Cents + o.Cents, Currency: currency}, nil
+}
+
+// MarshalJSON encodes the amount as canonical number text, the currency isn't encoded
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON decodes an amount written as canonical number text or as a json number,
+// the number's digits are parsed as written so they aren't rounded through a float64
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var canonical string
+	if err := json.Unmarshal(data, &canonical); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("docubot: %s is not an amount of money", data)
+		}
+		canonical = n.String()
+	}
+	parsed, err := ParseMoney(canonical, m.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MoneyVariable returns the variable's value as an amount of money in the currency
+func MoneyVariable(variables map[string]interface{}, name string, currency string) (Money, bool) {
+	switch v := variables[name].(type) {
+	case Money:
+		return v, true
+	case string:
+		m, err := ParseMoney(v, currency)
+		return m, err == nil
+	case json.Number:
+		m, err := ParseMoney(v.String(), currency)
+		return m, err == nil
+	}
+	return Money{}, false
+}
+
+// ParseMoney parses an amount of money written in the locale, such as "1.234,56 €". Unlike ParseCurrency,
+// amounts with fractions of a cent are rejected rather than rounded.
+func (l Locale) ParseMoney(s string) (Money, error) {
+	n := strings.TrimSpace(s)
+	for _, symbol := range []string{l.CurrencySymbol, l.CurrencyCode} {
+		if symbol != "" {
+			n = strings.ReplaceAll(n, symbol, "")
+		}
+	}
+	canonical, err := l.ParseNumber(n)
+	if err != nil {
+		return Money{}, fmt.Errorf("docubot: %q is not an amount of money", s)
+	}
+	return ParseMoney(canonical, l.CurrencyCode)
+}
+
+// FormatMoney writes an amount of money the way the locale does, such as "$1,234.56"
+func (l Locale) FormatMoney(m Money) string {
+	formatted, err := l.FormatCurrency(m.String())
+	if err != nil {
+		return m.String()
+	}
+	return formatted
+}
@@ -0,0 +1,125 @@
+package docubotlib
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dependency kinds
+const (
+	// DependencyCondition is a question whose conditions compare another variable
+	DependencyCondition string = "condition"
+	// DependencyParent is a question that is only reached through its parent question
+	DependencyParent string = "parent"
+)
+
+// Dependency is an edge of a DependencyGraph, the question asking for Variable depends on On
+type Dependency struct {
+	Variable string
+	On       string
+	Kind     string
+}
+
+// DependencyGraph is which variables each question of a tree depends on
+type DependencyGraph struct {
+	// Variables are the tree's variables in tree order
+	Variables []string
+	// Dependencies are the edges of the graph in tree order
+	Dependencies []Dependency
+}
+
+// DependencyGraph computes which variables the tree's questions depend on, through their conditions
+// and through the parent questions they are asked under
+func (t *DocumentTree) DependencyGraph() *DependencyGraph {
+	g := &DependencyGraph{Variables: t.Variables()}
+	seen := map[Dependency]bool{}
+	add := func(d Dependency) {
+		if d.Variable != "" && d.On != "" && d.Variable != d.On && !seen[d] {
+			seen[d] = true
+			g.Dependencies = append(g.Dependencies, d)
+		}
+	}
+	var visit func(node *QuestionNode, parent string)
+	visit = func(node *QuestionNode, parent string) {
+		for _, condition := range node.Conditions {
+			add(Dependency{Variable: node.VariableName, On: condition.VariableName, Kind: DependencyCondition})
+		}
+		add(Dependency{Variable: node.VariableName, On: parent, Kind: DependencyParent})
+		for i := range node.ChildQuestions {
+			visit(&node.ChildQuestions[i], node.VariableName)
+		}
+	}
+	if t != nil && t.EntryQuestion != nil {
+		visit(t.EntryQuestion, "")
+	}
+	return g
+}
+
+// AdjacencyList maps each variable to the variables it depends on, sorted
+func (g *DependencyGraph) AdjacencyList() map[string][]string {
+	list := map[string][]string{}
+	for _, v := range g.Variables {
+		list[v] = nil
+	}
+	for _, d := range g.Dependencies {
+		if !containsString(list[d.Variable], d.On) {
+			list[d.Variable] = append(list[d.Variable], d.On)
+		}
+	}
+	for v := range list {
+		sort.Strings(list[v])
+	}
+	return list
+}
+
+// Dependents returns every variable that depends on the variable directly or indirectly, in tree order:
+// the questions whose asking may change when the variable's question changes
+func (g *DependencyGraph) Dependents(variable string) []string {
+	reached := map[string]bool{variable: true}
+	for changed := true; changed; {
+		changed = false
+		for _, d := range g.Dependencies {
+			if reached[d.On] && !reached[d.Variable] {
+				reached[d.Variable] = true
+				changed = true
+			}
+		}
+	}
+	var dependents []string
+	for _, v := range g.Variables {
+		if v != variable && reached[v] {
+			dependents = append(dependents, v)
+		}
+	}
+	return dependents
+}
+
+// DOT writes the graph in the graphviz dot language, edges point from a question to what it depends on
+// and parent edges are dashed
+func (g *DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	for _, v := range g.Variables {
+		fmt.Fprintf(&b, "\t%v;\n", strconv.Quote(v))
+	}
+	for _, d := range g.Dependencies {
+		style := ""
+		if d.Kind == DependencyParent {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(&b, "\t%v -> %v%v;\n", strconv.Quote(d.Variable), strconv.Quote(d.On), style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
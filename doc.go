@@ -0,0 +1,16 @@
+// Package docubotlib is a client for the Docubot api, and for the interviews, documents, and webhooks it serves.
+//
+// # Integrations
+//
+// The package only depends on the standard library so it stays small enough for serverless builds. Integrations
+// with other systems are written against narrow interfaces the package defines, and adapters for a particular
+// system, which pull in that system's client library, belong in their own modules under integrations/ with their
+// own go.mod, so that importing this package never adds their dependencies:
+//
+//   - DocumentStore persists downloaded documents, such as in S3
+//   - Limiter shares the account's rate limit between replicas, such as in Redis with integrations/redis, and
+//     SeenEventStore remembers handled webhook events
+//   - EventPublisher forwards webhook events, such as to Kafka
+//   - Tracer traces the client's requests, such as with OpenTelemetry
+//   - KeyProvider supplies encryption keys, such as from a KMS
+package docubotlib
@@ -0,0 +1,255 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Severities of a DoctorFinding
+const (
+	DoctorOK      string = "ok"
+	DoctorWarning string = "warning"
+	DoctorProblem string = "problem"
+)
+
+// Checks run by Client.Doctor
+const (
+	DoctorCheckConnectivity string = "connectivity"
+	DoctorCheckTLS          string = "tls"
+	DoctorCheckCredentials  string = "credentials"
+	DoctorCheckClockSkew    string = "clockSkew"
+	DoctorCheckRateLimit    string = "rateLimit"
+	DoctorCheckWebhook      string = "webhook"
+)
+
+// EventPing is delivered by Client.Doctor to check that a webhook endpoint can be reached, handlers should ignore it
+const EventPing string = "ping"
+
+// defaultDoctorClockSkew is how far the local clock can be from docubot's before Doctor warns about it
+const defaultDoctorClockSkew time.Duration = 30 * time.Second
+
+// DoctorOptions configures Client.Doctor
+type DoctorOptions struct {
+	// WebhookURLs are the webhook endpoints to check, the account's registered endpoints when empty
+	WebhookURLs []string
+	// MaxClockSkew is how far the local clock can be from docubot's, 30 seconds when zero
+	MaxClockSkew time.Duration
+}
+
+// DoctorFinding is the outcome of one of Doctor's checks
+type DoctorFinding struct {
+	// Check is what was checked, such as DoctorCheckConnectivity
+	Check string
+	// Severity is DoctorOK, DoctorWarning, or DoctorProblem
+	Severity string
+	Message  string
+	// Fix is what to do about a warning or problem
+	Fix string
+}
+
+// DoctorReport lists Doctor's findings in the order the checks ran
+type DoctorReport struct {
+	Findings []DoctorFinding
+}
+
+// Healthy reports whether no check found a problem, warnings don't count
+func (r *DoctorReport) Healthy() bool {
+	for _, finding := range r.Findings {
+		if finding.Severity == DoctorProblem {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *DoctorReport) add(check string, severity string, message string, fix string) {
+	r.Findings = append(r.Findings, DoctorFinding{Check: check, Severity: severity, Message: message, Fix: fix})
+}
+
+// Doctor diagnoses the client's integration with docubot: connectivity, tls trust, credentials, clock skew,
+// rate limit status, and webhook reachability. Checks that depend on reaching docubot are skipped when it
+// can't be reached. Webhook endpoints are sent a signed EventPing delivery from this host.
+func (c *Client) Doctor(ctx context.Context, opts DoctorOptions, callOpts ...CallOption) *DoctorReport {
+	ctx = withCallOptions(ctx, callOpts)
+	report := &DoctorReport{}
+	base, err := url.Parse(c.DocubotAPIURLBase)
+	if err != nil || base.Host == "" {
+		report.add(DoctorCheckConnectivity, DoctorProblem, fmt.Sprintf("the api url %q is not a url", c.DocubotAPIURLBase), "set DocubotAPIURLBase to docubot's url, such as https://docubotapi.1law.com")
+		return report
+	}
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("%v/api/v1/status", c.DocubotAPIURLBase), nil)
+	if err != nil {
+		report.add(DoctorCheckConnectivity, DoctorProblem, err.Error(), "")
+		return report
+	}
+	start := time.Now()
+	resp, err := c.do(req)
+	latency := time.Since(start)
+	if err != nil {
+		c.diagnoseSendError(report, err)
+		return report
+	}
+	defer resp.Body.Close()
+	report.add(DoctorCheckConnectivity, DoctorOK, fmt.Sprintf("reached %v in %v", base.Host, latency.Round(time.Millisecond)), "")
+	if base.Scheme == "https" {
+		report.add(DoctorCheckTLS, DoctorOK, "docubot's certificate is trusted", "")
+	} else {
+		report.add(DoctorCheckTLS, DoctorWarning, "the api url doesn't use https, credentials and answers are sent in the clear", "use docubot's https url")
+	}
+	c.diagnoseCredentials(report, resp)
+	diagnoseClockSkew(report, resp, start, latency, opts.MaxClockSkew)
+	c.diagnoseRateLimit(report)
+	c.diagnoseWebhooks(ctx, report, opts.WebhookURLs)
+	return report
+}
+
+// diagnoseSendError explains why docubot's status endpoint couldn't be reached
+func (c *Client) diagnoseSendError(report *DoctorReport, err error) {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		report.add(DoctorCheckTLS, DoctorProblem, "docubot's certificate isn't signed by an authority this host trusts: "+err.Error(),
+			"install the missing ca certificates, or the proxy's if it intercepts tls, rather than disabling verification")
+	case errors.As(err, &hostname):
+		report.add(DoctorCheckTLS, DoctorProblem, "docubot's certificate is for another host: "+err.Error(),
+			"check DocubotAPIURLBase is docubot's url and not an alias or ip address")
+	case errors.As(err, &invalid):
+		report.add(DoctorCheckTLS, DoctorProblem, "docubot's certificate is invalid: "+err.Error(),
+			"an expired certificate often means the clock of this host is wrong, check it")
+	default:
+		report.add(DoctorCheckConnectivity, DoctorProblem, "can't reach docubot: "+err.Error(),
+			"check DocubotAPIURLBase, dns, and any proxy or firewall between this host and docubot")
+	}
+}
+
+// diagnoseCredentials checks the status response wasn't refused
+func (c *Client) diagnoseCredentials(report *DoctorReport, resp *http.Response) {
+	if c.DocubotAPIKey == "" || c.DocubotAPISecret == "" {
+		report.add(DoctorCheckCredentials, DoctorProblem, "the api key or secret is empty", "set the key and secret from the account's api keys")
+		return
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		report.add(DoctorCheckCredentials, DoctorProblem, "docubot refused the api key and secret",
+			"check the key and secret are a pair and that the key hasn't been revoked or expired")
+	case resp.StatusCode == http.StatusForbidden:
+		report.add(DoctorCheckCredentials, DoctorProblem, "the api key isn't allowed to call docubot",
+			"give the key the scopes the integration needs")
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		report.add(DoctorCheckCredentials, DoctorWarning, fmt.Sprintf("docubot's status endpoint answered %v", resp.Status),
+			"docubot may be having an incident, check its status page")
+	default:
+		report.add(DoctorCheckCredentials, DoctorOK, "docubot accepted the api key and secret", "")
+	}
+}
+
+// diagnoseClockSkew compares the local clock to docubot's Date header, allowing for the request's latency
+func diagnoseClockSkew(report *DoctorReport, resp *http.Response, start time.Time, latency time.Duration, max time.Duration) {
+	if max <= 0 {
+		max = defaultDoctorClockSkew
+	}
+	remote, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		report.add(DoctorCheckClockSkew, DoctorWarning, "docubot didn't send its time, the clock couldn't be checked", "")
+		return
+	}
+	// the header has a resolution of a second, skews inside it and the round trip aren't measurable
+	skew := start.Add(latency / 2).Sub(remote)
+	if skew < 0 {
+		skew = -skew
+	}
+	tolerance := max + latency + time.Second
+	switch {
+	case skew > defaultWebhookTolerance:
+		report.add(DoctorCheckClockSkew, DoctorProblem, fmt.Sprintf("this host's clock is %v off docubot's, webhook deliveries and signed urls will be refused", skew.Round(time.Second)),
+			"synchronize the clock with ntp")
+	case skew > tolerance:
+		report.add(DoctorCheckClockSkew, DoctorWarning, fmt.Sprintf("this host's clock is %v off docubot's", skew.Round(time.Second)),
+			"synchronize the clock with ntp")
+	default:
+		report.add(DoctorCheckClockSkew, DoctorOK, "this host's clock agrees with docubot's", "")
+	}
+}
+
+// diagnoseRateLimit reports the rate limit status of the status response
+func (c *Client) diagnoseRateLimit(report *DoctorReport) {
+	status := c.RateLimitStatus()
+	switch {
+	case !status.Known():
+		report.add(DoctorCheckRateLimit, DoctorOK, "docubot didn't report a rate limit", "")
+	case status.Exhausted():
+		report.add(DoctorCheckRateLimit, DoctorProblem, fmt.Sprintf("the rate limit of %v requests is used up until %v", status.Limit, status.Reset.Format(time.RFC3339)),
+			"spread requests out with a Limiter shared by every replica, or ask docubot for a higher limit")
+	case status.Limit > 0 && status.Remaining*10 < status.Limit:
+		report.add(DoctorCheckRateLimit, DoctorWarning, fmt.Sprintf("only %v of %v requests remain in the rate limit window", status.Remaining, status.Limit),
+			"spread requests out with a Limiter shared by every replica")
+	default:
+		report.add(DoctorCheckRateLimit, DoctorOK, fmt.Sprintf("%v of %v requests remain", status.Remaining, status.Limit), "")
+	}
+}
+
+// diagnoseWebhooks sends a signed ping to every webhook endpoint
+func (c *Client) diagnoseWebhooks(ctx context.Context, report *DoctorReport, urls []string) {
+	if len(urls) == 0 {
+		endpoints, err := c.ListWebhookEndpoints(ctx, ListOptions{})
+		if err != nil {
+			report.add(DoctorCheckWebhook, DoctorWarning, "the webhook endpoints couldn't be listed: "+err.Error(), "")
+			return
+		}
+		for _, endpoint := range endpoints.Data.WebhookEndpoints {
+			urls = append(urls, endpoint.URL)
+		}
+	}
+	if len(urls) == 0 {
+		report.add(DoctorCheckWebhook, DoctorOK, "no webhook endpoints are registered", "")
+		return
+	}
+	for _, endpoint := range urls {
+		c.diagnoseWebhook(ctx, report, endpoint)
+	}
+}
+
+func (c *Client) diagnoseWebhook(ctx context.Context, report *DoctorReport, endpoint string) {
+	payload, err := json.Marshal(WebhookEvent{ID: "ping_" + strconv.FormatInt(time.Now().UnixNano(), 36), Type: EventPing, CreatedAt: time.Now().UTC()})
+	if err != nil {
+		report.add(DoctorCheckWebhook, DoctorProblem, err.Error(), "")
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		report.add(DoctorCheckWebhook, DoctorProblem, fmt.Sprintf("%v is not a url", endpoint), "fix the endpoint's url")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, c.signWebhookPayload(payload, time.Now()))
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		report.add(DoctorCheckWebhook, DoctorProblem, fmt.Sprintf("can't reach %v: %v", endpoint, err),
+			"make the endpoint reachable from the internet, docubot delivers webhooks from outside your network")
+		return
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		report.add(DoctorCheckWebhook, DoctorProblem, fmt.Sprintf("%v refused a delivery signed with the api secret", endpoint),
+			"verify deliveries with the same api secret as this client")
+	case resp.StatusCode >= 500:
+		report.add(DoctorCheckWebhook, DoctorProblem, fmt.Sprintf("%v answered %v", endpoint, resp.Status),
+			"check the endpoint's logs, docubot keeps redelivering events it answers with errors")
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		report.add(DoctorCheckWebhook, DoctorWarning, fmt.Sprintf("%v answered %v", endpoint, resp.Status),
+			"answer deliveries with a 2xx status, even for events the endpoint ignores")
+	default:
+		report.add(DoctorCheckWebhook, DoctorOK, fmt.Sprintf("%v accepted a signed delivery", endpoint), "")
+	}
+}
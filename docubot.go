@@ -2,12 +2,13 @@ package docubotlib
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
@@ -18,8 +19,12 @@ type DocumentTree struct {
 	ID            string        `json:"id"`
 	DocumentName  string        `json:"documentName"`
 	EntryQuestion *QuestionNode `json:"entryQuestion,omitempty"`
-	UpdatedAt     time.Time     `json:"updatedAt"`
-	CreatedAt     time.Time     `json:"createdAt"`
+	// Version increases every time the tree is changed
+	Version int `json:"version,omitempty"`
+	// Groups are the titled sections of the tree's questions, in the order they are asked
+	Groups    []QuestionGroup `json:"groups,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+	CreatedAt time.Time       `json:"createdAt"`
 }
 
 // QuestionCondition is a data model
@@ -46,17 +51,37 @@ type QuestionNode struct {
 type QuestionNodeMetaData struct {
 	// Choices is what holds the choices of a multiple choice entity
 	Choices map[string]string `json:"choices,omitempty"`
+	// Attachments is reference material shown with the question, see UploadQuestionAttachment
+	Attachments []QuestionAttachment `json:"attachments,omitempty"`
+	// Group is the name of the QuestionGroup the question is in, child questions without a group are in it too
+	Group string `json:"group,omitempty"`
+	// RawMetaData holds every metadata field, including ones of entity types without a field above
+	RawMetaData map[string]interface{} `json:"-"`
 }
 
 // Document is a data model
 type Document struct {
-	ID             string    `json:"id"`
-	DocumentTreeID string    `json:"documentTreeId"`
-	HeaderHTML     string    `json:"headerHtml,omitempty"`
-	BodyHTML       string    `json:"bodyHtml,omitempty"`
-	FooterHTML     string    `json:"footerHtml,omitempty"`
-	UpdatedAt      time.Time `json:"updatedAt"`
-	CreatedAt      time.Time `json:"createdAt"`
+	ID             string `json:"id"`
+	DocumentTreeID string `json:"documentTreeId"`
+	HeaderHTML     string `json:"headerHtml,omitempty"`
+	BodyHTML       string `json:"bodyHtml,omitempty"`
+	FooterHTML     string `json:"footerHtml,omitempty"`
+	// Sections are optional parts of the document, placed in the html with SectionMarker
+	Sections []DocumentSection `json:"sections,omitempty"`
+	// Accessibility, when set, generates the document as a tagged, accessible pdf
+	Accessibility *AccessibilityOptions `json:"accessibility,omitempty"`
+	// SignatureFields are where signers sign the generated document
+	SignatureFields []SignatureField `json:"signatureFields,omitempty"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+	CreatedAt       time.Time        `json:"createdAt"`
+}
+
+// DocumentSection is a data model for a part of a Document that is only rendered when its conditions hold
+type DocumentSection struct {
+	Name            string              `json:"name"`
+	LogicalOperator string              `json:"logicalOperator"`
+	Conditions      []QuestionCondition `json:"conditions"`
+	HTML            string              `json:"html"`
 }
 
 // Client represents a Docubot API Client
@@ -65,6 +90,92 @@ type Client struct {
 	DocubotPreviewAPIURLBase string
 	DocubotAPIKey            string
 	DocubotAPISecret         string
+	// DocubotMessagesAPIURLBase, when set, is used instead of DocubotAPIURLBase to send messages
+	DocubotMessagesAPIURLBase string
+	// DocubotDocumentsAPIURLBase, when set, is used instead of DocubotAPIURLBase to download generated documents
+	// and get their urls, such as when downloads are routed through a cdn
+	DocubotDocumentsAPIURLBase string
+	// Redactor, when set, masks sensitive variables in errors produced by the client
+	Redactor *Redactor
+	// OnError, when set, receives errors from work the client does in the background,
+	// such as failed message queue attempts, that would otherwise never reach a caller
+	OnError func(error)
+	// UseNumber decodes numbers in variables as json.Number instead of float64,
+	// keeping large ids and currency amounts exact
+	UseNumber bool
+	// Sanitizer, when set, sanitizes Document html before CreateDocument and UpdateDocument upload it
+	Sanitizer *SanitizePolicy
+	// DryRun makes every call that would change what docubot stores validate its request instead,
+	// docubot is asked to validate it when it supports dry runs and otherwise nothing is sent
+	DryRun bool
+	// OnDryRun, when set, receives every request made in dry run mode
+	OnDryRun func(DryRunRequest)
+	// ReadOnly makes every call that would change what docubot stores fail with ErrReadOnly without sending anything,
+	// see WithReadOnly
+	ReadOnly bool
+	// SoftDelete makes DeleteDocumentTree and DeleteDocument move to the trash instead of deleting for good,
+	// see TrashDocumentTree
+	SoftDelete bool
+	// HTTPClient sends the client's requests, a default http.Client is used when nil
+	HTTPClient *http.Client
+	// Middleware wraps the transport of every request, the first middleware sees requests first
+	Middleware []Middleware
+	// MaxRetries is how many times a request that fails with a retryable error is sent again, see WithMaxRetries.
+	// Only requests that are safe to repeat are retried: reads, puts, deletes, and requests with an idempotency key.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, it doubles with every retry
+	RetryBackoff time.Duration
+	// Location, when set, is the time zone datetime variables received from docubot are written in
+	Location *time.Location
+	// Transforms rewrite the variables a preview document is generated from, in order, see VariableTransform
+	Transforms []VariableTransform
+	// HedgeDelay, when set, sends a second copy of a GET request that hasn't been answered after the delay
+	// and uses whichever response arrives first, see WithHedging
+	HedgeDelay time.Duration
+	// Encoder serializes request payloads, they are sent as json when nil
+	Encoder Encoder
+	// Limiter, when set, is waited on before every request is sent, so replicas can share the account's rate limit
+	Limiter Limiter
+	// AnswerNormalizers rewrite every message before it is sent, in order, such as DefaultAnswerNormalizers.
+	// Messages are sent as given when it is empty.
+	AnswerNormalizers []AnswerNormalizer
+	// VariableEncryption, when set, encrypts answers to its variables before conversations send them
+	// and decrypts them in variables received from GetDocubotVariables
+	VariableEncryption *VariableEncryption
+	// CallHistory is how many of the most recent requests and responses RecentCalls keeps, none when zero,
+	// so a misbehaving conversation can be inspected without verbose logging
+	CallHistory int
+
+	mu           sync.Mutex
+	rateLimit    RateLimitStatus
+	capabilities *Capabilities
+	// encoderUnsupported is set once docubot has rejected the Encoder's content type
+	encoderUnsupported bool
+	// history is a ring of the recent calls, historyNext the oldest once it is full
+	history     []*RecordedCall
+	historyNext int
+	// shuttingDown is closed once Shutdown is called, closed is set once it has drained the background work
+	shuttingDown chan struct{}
+	closed       bool
+	work         sync.WaitGroup
+	// writtenVersions are the versions of the threads' variables after this client's latest write to them
+	writtenVersions map[string]int64
+}
+
+// messagesURLBase is the base url messages are sent to
+func (c *Client) messagesURLBase() string {
+	if c.DocubotMessagesAPIURLBase != "" {
+		return c.DocubotMessagesAPIURLBase
+	}
+	return c.DocubotAPIURLBase
+}
+
+// documentsURLBase is the base url generated documents are requested from
+func (c *Client) documentsURLBase() string {
+	if c.DocubotDocumentsAPIURLBase != "" {
+		return c.DocubotDocumentsAPIURLBase
+	}
+	return c.DocubotAPIURLBase
 }
 
 // NewClient initializes a docubot client struct
@@ -102,6 +213,14 @@ type MessageResponseData struct {
 	Messages    []string `json:"messages"`
 	HasDocument bool     `json:"hasDocument"`
 	Complete    bool     `json:"complete"`
+	// MessageDelaysMs are docubot's suggested pauses, in milliseconds, before showing each of Messages
+	MessageDelaysMs []int64 `json:"messageDelaysMs,omitempty"`
+	// Documents lists every document generated for the thread, trees can produce more than one
+	Documents []ThreadDocument `json:"documents,omitempty"`
+	// Attachments is the reference material of the question docubot asks next, show it with the last message
+	Attachments []QuestionAttachment `json:"attachments,omitempty"`
+	// Group is the position of the group of the question docubot asks next, nil when it isn't in one
+	Group *GroupPosition `json:"group,omitempty"`
 }
 
 // MessageResponseMeta is the meta received from a message sent to docubot
@@ -110,17 +229,27 @@ type MessageResponseMeta struct {
 	UserID          string                            `json:"userId"`
 	DocumentName    string                            `json:"documentName"`
 	MessageMetaData map[string]map[string]interface{} `json:"messageMetaData"`
+	// Variant is the name of the tree variant serving the thread, empty when the tree has no variants
+	Variant string `json:"variant,omitempty"`
+	// VariablesVersion is the version of the thread's variables after the message, see WithMinVariablesVersion
+	VariablesVersion int64 `json:"variablesVersion,omitempty"`
 }
 
 // MessageResponseError is the response when there is an error
 type MessageResponseError struct {
-	Errors []string `json:"errors"`
+	Code       string                 `json:"code,omitempty"`
+	Errors     []string               `json:"errors"`
+	Validation *ValidationErrorDetail `json:"validation,omitempty"`
 }
 
 // DocumentURLResponse is the response received from getting a document's URL from docubot
 type DocumentURLResponse struct {
-	Data DocumentURLData        `json:"data"`
-	Meta map[string]interface{} `json:"meta"`
+	Data DocumentURLData `json:"data"`
+	Meta DocumentURLMeta `json:"meta"`
+	// ExpiresAt is when the url stops working, as reported by docubot or computed from the requested duration
+	ExpiresAt time.Time `json:"-"`
+	// Capped is set when docubot's expiry is earlier than the requested duration, docubot caps how long urls last
+	Capped bool `json:"-"`
 }
 
 // DocumentURLData is the response data received from getting a document's URL from docubot
@@ -130,8 +259,8 @@ type DocumentURLData struct {
 
 // DocumentVariablesResponse is the response received from getting a document's Variables from docubot
 type DocumentVariablesResponse struct {
-	Data DocumentVariablesData  `json:"data"`
-	Meta map[string]interface{} `json:"meta"`
+	Data DocumentVariablesData `json:"data"`
+	Meta DocumentVariablesMeta `json:"meta"`
 }
 
 // DocumentVariablesData is the response data received from getting a document's Variables from docubot
@@ -139,220 +268,335 @@ type DocumentVariablesData struct {
 	Variables map[string]interface{} `json:"variables"`
 }
 
-// SendMessage sends a message to docubot
-func (c *Client) SendMessage(message string, thread string, sender string, docTreeID string) (*MessageResponse, error) {
-	jsonStr, _ := json.Marshal(
-		map[string]interface{}{
-			"message":   message,
-			"thread":    thread,
-			"sender":    sender,
-			"docTreeId": docTreeID,
-		},
-	)
-	url := fmt.Sprintf("%v/api/v1/docubot", c.DocubotAPIURLBase)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonStr))
+// do sends the request to docubot, requests made in dry run mode are handled by doDryRun
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get(dryRunHeader) != "" {
+		return c.doDryRun(req)
+	}
+	if delay := c.resolveCallOptions(req.Context()).hedgeDelay; delay > 0 && hedgeable(req) {
+		return c.sendHedged(req, delay)
+	}
+	resp, err := c.sendWithRetries(req)
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.DocubotAPIKey, c.DocubotAPISecret)
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	return c.resendAsJSON(req, resp)
+}
+
+// send sends the request to docubot, recording the rate limit status of the response and the call's history
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.isClosed() {
+		return nil, ErrClosed
+	}
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	call := c.recordCall(req)
+	var resp *http.Response
+	var err error
+	if timeout := c.resolveCallOptions(req.Context()).timeout; timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		if resp, err = c.httpClient().Do(req.WithContext(ctx)); err != nil {
+			cancel()
+		} else {
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		}
+	} else {
+		resp, err = c.httpClient().Do(req)
+	}
+	c.finishCall(call, resp, err)
+	if err != nil {
+		return nil, err
+	}
+	if status, ok := parseRateLimitStatus(resp.Header); ok {
+		c.mu.Lock()
+		c.rateLimit = status
+		c.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// doStream sends the request and returns the body of a successful response, the caller must close it
+func (c *Client) doStream(req *http.Request, variables map[string]interface{}) (io.ReadCloser, error) {
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		var error MessageResponseError
-		json.NewDecoder(resp.Body).Decode(&error)
-		e := unknownErrorMessage
-		if len(error.Errors) > 0 {
-			e = error.Errors[0]
+		defer resp.Body.Close()
+		return nil, c.responseError(resp, variables)
+	}
+	return resp.Body, nil
+}
+
+// reportError passes err to OnError when it is set
+func (c *Client) reportError(err error) {
+	if c.OnError != nil && err != nil {
+		c.OnError(err)
+	}
+}
+
+// responseError builds the error returned for a non 2xx docubot response
+func (c *Client) responseError(resp *http.Response, variables map[string]interface{}) error {
+	var error MessageResponseError
+	json.NewDecoder(resp.Body).Decode(&error)
+	apiError := &APIError{
+		StatusCode: resp.StatusCode,
+		Code:       error.Code,
+		Message:    unknownErrorMessage,
+	}
+	apiError.RateLimit, _ = parseRateLimitStatus(resp.Header)
+	for _, e := range error.Errors {
+		apiError.Errors = append(apiError.Errors, c.Redactor.RedactString(e, variables))
+	}
+	if len(apiError.Errors) > 0 {
+		apiError.Message = apiError.Errors[0]
+	}
+	if error.Validation != nil {
+		return &ValidationError{
+			APIError:     *apiError,
+			VariableName: error.Validation.VariableName,
+			Constraint:   error.Validation.Constraint,
+			Reprompt:     c.Redactor.RedactString(error.Validation.Reprompt, variables),
 		}
-		return nil, errors.New(e)
 	}
-	var response MessageResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	return &response, err
+	return apiError
 }
 
-// SendPreviewMessage sends a preview message to docubot, this is a message that isn't stored on docubot at all
-func (c *Client) SendPreviewMessage(message string, variables map[string]interface{}, docTree *DocumentTree) (*PreviewMessageResponse, error) {
-	jsonStr, _ := json.Marshal(
-		map[string]interface{}{
-			"message":   message,
-			"docTree":   docTree,
-			"variables": variables,
-		},
-	)
-	url := fmt.Sprintf("%v/api/v1/preview", c.DocubotPreviewAPIURLBase)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonStr))
+// newRequest builds an authenticated docubot request, body is encoded as json when it isn't nil
+func (c *Client) newRequest(ctx context.Context, method string, url string, body interface{}) (*http.Request, error) {
+	var r io.Reader
+	contentType := ""
+	if body != nil {
+		var payload []byte
+		var err error
+		if ctx, payload, contentType, err = c.encodeBody(ctx, body); err != nil {
+			return nil, err
+		}
+		r = bytes.NewBuffer(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
 	if err != nil {
 		return nil, err
 	}
 	req.SetBasicAuth(c.DocubotAPIKey, c.DocubotAPISecret)
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	} else {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if err := c.applyCallOptions(req); err != nil {
 		return nil, err
 	}
+	return req, nil
+}
+
+// doJSON sends the request and decodes a successful response into response, which may be nil.
+// variables are the variables sent with the request, they are masked in errors by the client's Redactor.
+func (c *Client) doJSON(req *http.Request, variables map[string]interface{}, response interface{}) error {
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		var error MessageResponseError
-		json.NewDecoder(resp.Body).Decode(&error)
-		e := unknownErrorMessage
-		if len(error.Errors) > 0 {
-			e = error.Errors[0]
+		return c.responseError(resp, variables)
+	}
+	if response == nil {
+		return nil
+	}
+	return c.decodeJSON(resp.Body, response)
+}
+
+// decodeJSON decodes a response body, numbers in untyped values follow UseNumber
+func (c *Client) decodeJSON(r io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	if c.UseNumber {
+		decoder.UseNumber()
+	}
+	return decoder.Decode(v)
+}
+
+// SendMessage sends a message to docubot, WithActor attributes the answer to the person who supplied it
+func (c *Client) SendMessage(message string, thread string, sender string, docTreeID string, callOpts ...CallOption) (*MessageResponse, error) {
+	return c.sendMessage(withCallOptions(context.Background(), callOpts), message, thread, sender, docTreeID, "", "")
+}
+
+// sendMessage sends a message to docubot, a non empty idempotencyKey lets docubot discard retried duplicates.
+// The role is left out of the request when empty, docubot treats the message as the end user's.
+func (c *Client) sendMessage(ctx context.Context, message string, thread string, sender string, docTreeID string, role SenderRole, idempotencyKey string) (*MessageResponse, error) {
+	url := fmt.Sprintf("%v/api/v1/docubot", c.messagesURLBase())
+	req, err := c.newRequest(ctx, "POST", url, c.messageBody(message, thread, sender, docTreeID, role))
+	if err != nil {
+		return nil, err
+	}
+	if idempotencyKey == "" && c.retries(ctx) && req.Header.Get("Idempotency-Key") == "" {
+		// retried messages need a key so docubot doesn't record the answer twice
+		if idempotencyKey, err = newIdempotencyKey(); err != nil {
+			return nil, err
 		}
-		return nil, errors.New(e)
 	}
-	var response PreviewMessageResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	return &response, err
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	var response MessageResponse
+	err = c.doJSON(req, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	c.wroteVariables(thread, response.Meta.VariablesVersion)
+	return &response, nil
 }
 
-// GetPreviewDoc gets a preview document that isn't stored permanently
-func (c *Client) GetPreviewDoc(variables map[string]interface{}, document *Document) (io.ReadCloser, error) {
-	jsonStr, _ := json.Marshal(
+// messageBody is the body of a message sent to docubot, with the answer normalized
+func (c *Client) messageBody(message string, thread string, sender string, docTreeID string, role SenderRole) map[string]interface{} {
+	body := map[string]interface{}{
+		"message":   c.normalizeAnswer(message),
+		"thread":    thread,
+		"sender":    sender,
+		"docTreeId": docTreeID,
+	}
+	if role != "" {
+		body["role"] = role
+	}
+	return body
+}
+
+// SendPreviewMessage sends a preview message to docubot, this is a message that isn't stored on docubot at all
+func (c *Client) SendPreviewMessage(message string, variables map[string]interface{}, docTree *DocumentTree, callOpts ...CallOption) (*PreviewMessageResponse, error) {
+	return c.sendPreviewMessage(withCallOptions(context.Background(), callOpts), message, variables, docTree)
+}
+
+func (c *Client) sendPreviewMessage(ctx context.Context, message string, variables map[string]interface{}, docTree *DocumentTree) (*PreviewMessageResponse, error) {
+	url := fmt.Sprintf("%v/api/v1/preview", c.DocubotPreviewAPIURLBase)
+	req, err := c.newRequest(
+		ctx,
+		"POST",
+		url,
 		map[string]interface{}{
-			"document":  document,
+			"message":   message,
+			"docTree":   docTree,
 			"variables": variables,
 		},
 	)
-	url := fmt.Sprintf(
-		"%v/api/v1/preview/doc",
-		c.DocubotPreviewAPIURLBase,
-	)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonStr))
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.DocubotAPIKey, c.DocubotAPISecret)
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	var response PreviewMessageResponse
+	err = c.doJSON(req, variables, &response)
+	c.localizeVariables(response.Data.Variables)
+	return &response, err
+}
+
+// GetPreviewDoc gets a preview document that isn't stored permanently
+func (c *Client) GetPreviewDoc(variables map[string]interface{}, document *Document, callOpts ...CallOption) (io.ReadCloser, error) {
+	return c.getPreviewDoc(withCallOptions(context.Background(), callOpts), variables, document)
+}
+
+func (c *Client) getPreviewDoc(ctx context.Context, variables map[string]interface{}, document *Document) (io.ReadCloser, error) {
+	variables, err := c.transformVariables(ctx, variables)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		defer resp.Body.Close()
-		var error MessageResponseError
-		json.NewDecoder(resp.Body).Decode(&error)
-		e := unknownErrorMessage
-		if len(error.Errors) > 0 {
-			e = error.Errors[0]
-		}
-		return nil, errors.New(e)
-	}
-	return resp.Body, nil
+	return c.requestPreviewDoc(ctx, variables, document)
 }
 
-// GetDocubotDoc gets the docubot document
-func (c *Client) GetDocubotDoc(thread string, user string) (io.ReadCloser, error) {
-	params := url.Values{}
-	params.Set("user", user)
+// requestPreviewDoc gets a preview document generated from variables that have already been transformed
+func (c *Client) requestPreviewDoc(ctx context.Context, variables map[string]interface{}, document *Document) (io.ReadCloser, error) {
 	url := fmt.Sprintf(
-		"%v/api/v1/docubot/%v/doc/download?%v",
-		c.DocubotAPIURLBase,
-		thread,
-		params.Encode(),
+		"%v/api/v1/preview/doc",
+		c.DocubotPreviewAPIURLBase,
+	)
+	req, err := c.newRequest(
+		ctx,
+		"POST",
+		url,
+		map[string]interface{}{
+			"document":  TranslateFilters(document),
+			"variables": variables,
+		},
 	)
-	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.DocubotAPIKey, c.DocubotAPISecret)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	return c.doStream(req, variables)
+}
+
+// GetDocubotDoc gets the docubot document
+func (c *Client) GetDocubotDoc(thread string, user string, callOpts ...CallOption) (io.ReadCloser, error) {
+	return c.getDocubotDoc(withCallOptions(context.Background(), callOpts), thread, user)
+}
+
+func (c *Client) getDocubotDoc(ctx context.Context, thread string, user string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, "GET", c.docubotDocDownloadURL(thread, user), nil)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		defer resp.Body.Close()
-		var error MessageResponseError
-		json.NewDecoder(resp.Body).Decode(&error)
-		e := unknownErrorMessage
-		if len(error.Errors) > 0 {
-			e = error.Errors[0]
-		}
-		return nil, errors.New(e)
-	}
-	return resp.Body, nil
+	return c.doStream(req, nil)
 }
 
-// GetDocubotDocURL gets the docubot document url
-func (c *Client) GetDocubotDocURL(thread string, user string, exp time.Duration) (*DocumentURLResponse, error) {
+func (c *Client) docubotDocDownloadURL(thread string, user string) string {
 	params := url.Values{}
 	params.Set("user", user)
-	params.Set("duration", fmt.Sprintf("%v", int(exp.Seconds())))
-	url := fmt.Sprintf(
-		"%v/api/v1/docubot/%v/doc/url?%v",
-		c.DocubotAPIURLBase,
+	return fmt.Sprintf(
+		"%v/api/v1/docubot/%v/doc/download?%v",
+		c.documentsURLBase(),
 		thread,
 		params.Encode(),
 	)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.SetBasicAuth(c.DocubotAPIKey, c.DocubotAPISecret)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	client := &http.Client{}
-	resp, err := client.Do(req)
+}
+
+// GetDocubotDocURL gets the docubot document url, valid for exp rounded up to whole seconds and at most
+// MaxDocumentURLDuration. ExpiresAt on the response is when docubot says the url actually expires.
+func (c *Client) GetDocubotDocURL(thread string, user string, exp time.Duration, callOpts ...CallOption) (*DocumentURLResponse, error) {
+	return c.getDocubotDocURL(withCallOptions(context.Background(), callOpts), thread, user, exp)
+}
+
+func (c *Client) getDocubotDocURL(ctx context.Context, thread string, user string, exp time.Duration) (*DocumentURLResponse, error) {
+	seconds, err := urlDurationSeconds(exp)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		defer resp.Body.Close()
-		var error MessageResponseError
-		json.NewDecoder(resp.Body).Decode(&error)
-		e := unknownErrorMessage
-		if len(error.Errors) > 0 {
-			e = error.Errors[0]
-		}
-		return nil, errors.New(e)
-	}
-	var response DocumentURLResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	return &response, err
-}
-
-// GetDocubotVariables gets the docubot variables for the provided user in the provided thread
-func (c *Client) GetDocubotVariables(thread string, user string) (*DocumentVariablesResponse, error) {
 	params := url.Values{}
 	params.Set("user", user)
+	params.Set("duration", fmt.Sprintf("%v", seconds))
 	url := fmt.Sprintf(
-		"%v/api/v1/docubot/%v/variables?%v",
-		c.DocubotAPIURLBase,
+		"%v/api/v1/docubot/%v/doc/url?%v",
+		c.documentsURLBase(),
 		thread,
 		params.Encode(),
 	)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := c.newRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.DocubotAPIKey, c.DocubotAPISecret)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
+	requested := time.Now()
+	var response DocumentURLResponse
+	if err := c.doJSON(req, nil, &response); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		defer resp.Body.Close()
-		var error MessageResponseError
-		json.NewDecoder(resp.Body).Decode(&error)
-		e := unknownErrorMessage
-		if len(error.Errors) > 0 {
-			e = error.Errors[0]
+	response.setExpiry(requested, time.Duration(seconds)*time.Second)
+	return &response, nil
+}
+
+// GetDocubotVariables gets the docubot variables for the provided user in the provided thread. Reads can lag
+// writes for a moment, WithReadYourWrites and WithMinVariablesVersion wait for the variables to catch up.
+func (c *Client) GetDocubotVariables(thread string, user string, callOpts ...CallOption) (*DocumentVariablesResponse, error) {
+	return c.getDocubotVariables(withCallOptions(context.Background(), callOpts), thread, user)
+}
+
+func (c *Client) getDocubotVariables(ctx context.Context, thread string, user string) (*DocumentVariablesResponse, error) {
+	response, err := c.readVariablesVersion(ctx, thread, user, c.minVariablesVersion(ctx, thread))
+	if _, stale := err.(*StaleReadError); err != nil && !stale {
+		return response, err
+	}
+	if c.VariableEncryption != nil {
+		if err := c.VariableEncryption.decryptVariables(ctx, thread, response.Data.Variables); err != nil {
+			return response, err
 		}
-		return nil, errors.New(e)
 	}
-	var response DocumentVariablesResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	return &response, err
+	c.localizeVariables(response.Data.Variables)
+	return response, err
 }
@@ -0,0 +1,212 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// ThreadDocument is a data model for a document generated in a thread
+type ThreadDocument struct {
+	ID string `json:"id"`
+	// DocumentID is the Document template the document was generated from
+	DocumentID  string `json:"documentId"`
+	Name        string `json:"name"`
+	ContentType string `json:"contentType,omitempty"`
+	// PDFUA reports whether docubot produced a file that conforms to PDF/UA
+	PDFUA bool `json:"pdfUa,omitempty"`
+	// SHA256 is the hex sha-256 of the document's content, as reported by docubot
+	SHA256    string    `json:"sha256,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ThreadDocumentListResponse is the response received from listing a thread's documents
+type ThreadDocumentListResponse struct {
+	Data ThreadDocumentListData `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// ThreadDocumentListData is the response data received from listing a thread's documents
+type ThreadDocumentListData struct {
+	Documents []ThreadDocument `json:"documents"`
+}
+
+// ListThreadDocuments lists the documents generated for the provided user in the provided thread
+func (c *Client) ListThreadDocuments(ctx context.Context, thread string, user string, callOpts ...CallOption) (*ThreadDocumentListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/docs?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadDocumentListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetThreadDocument downloads one of the documents generated in a thread, the caller must close it
+func (c *Client) GetThreadDocument(ctx context.Context, thread string, user string, documentID string, callOpts ...CallOption) (io.ReadCloser, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	req, err := c.newRequest(ctx, "GET", c.threadDocumentDownloadURL(thread, user, documentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.doStream(req, nil)
+}
+
+func (c *Client) threadDocumentDownloadURL(thread string, user string, documentID string) string {
+	params := url.Values{}
+	params.Set("user", user)
+	return fmt.Sprintf(
+		"%v/api/v1/docubot/%v/docs/%v/download?%v",
+		c.documentsURLBase(),
+		thread,
+		documentID,
+		params.Encode(),
+	)
+}
+
+// GetThreadDocumentURL gets a url for one of the documents generated in a thread
+func (c *Client) GetThreadDocumentURL(ctx context.Context, thread string, user string, documentID string, exp time.Duration, callOpts ...CallOption) (*DocumentURLResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	seconds, err := urlDurationSeconds(exp)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("user", user)
+	params.Set("duration", fmt.Sprintf("%v", seconds))
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/docs/%v/url?%v",
+		c.documentsURLBase(),
+		thread,
+		documentID,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	requested := time.Now()
+	var response DocumentURLResponse
+	if err := c.doJSON(req, nil, &response); err != nil {
+		return nil, err
+	}
+	response.setExpiry(requested, time.Duration(seconds)*time.Second)
+	return &response, nil
+}
+
+// DocumentResponse is the response received from getting or changing a Document
+type DocumentResponse struct {
+	Data DocumentData           `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// DocumentData is the response data received from getting or changing a Document
+type DocumentData struct {
+	Document Document `json:"document"`
+}
+
+// DocumentListResponse is the response received from listing a tree's Documents
+type DocumentListResponse struct {
+	Data DocumentListData       `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// DocumentListData is the response data received from listing a tree's Documents
+type DocumentListData struct {
+	Documents []Document `json:"documents"`
+}
+
+// ListDocuments lists the Document templates filled in by a tree
+func (c *Client) ListDocuments(ctx context.Context, docTreeID string, callOpts ...CallOption) (*DocumentListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees/%v/documents", c.DocubotAPIURLBase, docTreeID)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetDocument gets a Document template
+func (c *Client) GetDocument(ctx context.Context, documentID string, callOpts ...CallOption) (*DocumentResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/documents/%v", c.DocubotAPIURLBase, documentID)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// CreateDocument creates a Document template for document.DocumentTreeID, docubot assigns its ID
+func (c *Client) CreateDocument(ctx context.Context, document *Document, callOpts ...CallOption) (*DocumentResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/documents", c.DocubotAPIURLBase)
+	return c.saveDocument(ctx, "POST", url, document)
+}
+
+// UpdateDocument replaces the Document template with document.ID
+func (c *Client) UpdateDocument(ctx context.Context, document *Document, callOpts ...CallOption) (*DocumentResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/documents/%v", c.DocubotAPIURLBase, document.ID)
+	return c.saveDocument(ctx, "PUT", url, document)
+}
+
+func (c *Client) saveDocument(ctx context.Context, method string, url string, document *Document) (*DocumentResponse, error) {
+	if document.Accessibility != nil {
+		if err := document.Accessibility.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := ValidateSignatureFields(document.SignatureFields); err != nil {
+		return nil, err
+	}
+	if c.Sanitizer != nil {
+		document = c.Sanitizer.SanitizeDocument(document)
+	}
+	document = TranslateFilters(document)
+	req, err := c.newRequest(
+		ctx,
+		method,
+		url,
+		map[string]interface{}{
+			"document": document,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteDocument deletes a Document template, or moves it to the trash when SoftDelete is set
+func (c *Client) DeleteDocument(ctx context.Context, documentID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	if c.SoftDelete {
+		_, err := c.TrashDocument(ctx, documentID)
+		return err
+	}
+	url := fmt.Sprintf("%v/api/v1/documents/%v", c.DocubotAPIURLBase, documentID)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
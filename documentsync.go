@@ -0,0 +1,127 @@
+package docubotlib
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DocumentHasher is implemented by DocumentStores that can report the hash of what they store,
+// SyncThreadDocuments uses it to skip documents that haven't changed
+type DocumentHasher interface {
+	// Hash returns the hex sha-256 of the document stored under key, empty when nothing is stored
+	Hash(ctx context.Context, key string) (string, error)
+}
+
+// Hash returns the hex sha-256 of the file named key inside the store's directory
+func (s *FileStore) Hash(ctx context.Context, key string) (string, error) {
+	f, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, contextReader{ctx: ctx, r: f}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SyncOptions configures SyncThreadDocuments
+type SyncOptions struct {
+	// Threads selects the threads whose documents are synced
+	Threads ListThreadsOptions
+	// Key is the key a document is stored under, the thread's ID, the document's ID
+	// and its extension, such as "thread/document.pdf", when nil
+	Key func(thread Thread, document ThreadDocument) string
+	// Concurrency is the number of threads synced at once, a small default is used when zero
+	Concurrency int
+}
+
+// SyncReport describes what SyncThreadDocuments did
+type SyncReport struct {
+	// Downloaded and Skipped are the keys of the documents stored and of the ones already stored unchanged
+	Downloaded []string
+	Skipped    []string
+	// Failed maps the keys of the documents that couldn't be synced to why
+	Failed map[string]error
+}
+
+// SyncThreadDocuments downloads the documents generated in the selected threads to store. When the store
+// implements DocumentHasher, documents whose stored hash matches the one docubot reports aren't downloaded again.
+// A failed document doesn't stop the others, it is reported in SyncReport.Failed and to OnError.
+func (c *Client) SyncThreadDocuments(ctx context.Context, store DocumentStore, opts SyncOptions, callOpts ...CallOption) (*SyncReport, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	key := opts.Key
+	if key == nil {
+		key = func(thread Thread, document ThreadDocument) string {
+			return thread.ID + "/" + document.ID + ExtensionForContentType(document.ContentType)
+		}
+	}
+	var threads []Thread
+	if err := c.walkThreads(ctx, opts.Threads, func(thread Thread) error {
+		threads = append(threads, thread)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	report := &SyncReport{Failed: map[string]error{}}
+	var mu sync.Mutex
+	record := func(key string, skipped bool, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err != nil:
+			report.Failed[key] = err
+		case skipped:
+			report.Skipped = append(report.Skipped, key)
+		default:
+			report.Downloaded = append(report.Downloaded, key)
+		}
+		if err != nil && c.OnError != nil {
+			c.OnError(err)
+		}
+	}
+	c.runPool(ctx, opts.Concurrency, len(threads), func(i int) {
+		thread := threads[i]
+		documents, err := c.ListThreadDocuments(ctx, thread.ID, thread.UserID)
+		if err != nil {
+			record(thread.ID, false, err)
+			return
+		}
+		for _, document := range documents.Data.Documents {
+			k := key(thread, document)
+			skipped, err := c.syncThreadDocument(ctx, store, thread, document, k)
+			record(k, skipped, err)
+		}
+	}, func(i int, err error) {
+		record(threads[i].ID, false, err)
+	})
+	return report, ctx.Err()
+}
+
+// syncThreadDocument stores the document under key unless the store already holds it unchanged
+func (c *Client) syncThreadDocument(ctx context.Context, store DocumentStore, thread Thread, document ThreadDocument, key string) (bool, error) {
+	if hasher, ok := store.(DocumentHasher); ok && document.SHA256 != "" {
+		stored, err := hasher.Hash(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		if strings.EqualFold(stored, document.SHA256) {
+			return true, nil
+		}
+	}
+	doc, err := c.GetThreadDocument(ctx, thread.ID, thread.UserID, document.ID)
+	if err != nil {
+		return false, err
+	}
+	defer doc.Close()
+	return false, store.Put(ctx, key, doc)
+}
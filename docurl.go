@@ -0,0 +1,111 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultURLRefreshMargin is how long before expiry a DocumentURLProvider replaces its url
+const defaultURLRefreshMargin time.Duration = time.Minute
+
+// MaxDocumentURLDuration is the longest a document url can be requested for, docubot may cap urls sooner
+const MaxDocumentURLDuration time.Duration = 7 * 24 * time.Hour
+
+// ErrInvalidURLDuration is returned when a document url is requested for a zero, negative or too long duration
+var ErrInvalidURLDuration = errors.New("docubot: invalid document url duration")
+
+// urlDurationSeconds validates how long a document url is requested for and returns it in the whole seconds
+// docubot takes, rounding up so a url never expires before the requested duration
+func urlDurationSeconds(exp time.Duration) (int64, error) {
+	if exp <= 0 || exp > MaxDocumentURLDuration {
+		return 0, fmt.Errorf("%w: %v is not between 1s and %v", ErrInvalidURLDuration, exp, MaxDocumentURLDuration)
+	}
+	return int64((exp + time.Second - 1) / time.Second), nil
+}
+
+func (r *DocumentURLResponse) setExpiry(requested time.Time, exp time.Duration) {
+	if r.Meta.ExpiresAt != nil {
+		r.ExpiresAt = *r.Meta.ExpiresAt
+		r.Capped = r.ExpiresAt.Before(requested.Add(exp - time.Second))
+		return
+	}
+	r.ExpiresAt = requested.Add(exp)
+}
+
+// ExpiresIn returns how long the url keeps working
+func (r *DocumentURLResponse) ExpiresIn() time.Duration {
+	return time.Until(r.ExpiresAt)
+}
+
+// ExpiresWithin reports whether the url stops working within d
+func (r *DocumentURLResponse) ExpiresWithin(d time.Duration) bool {
+	return !time.Now().Add(d).Before(r.ExpiresAt)
+}
+
+// RefreshDocumentURL mints a new url for the docubot document that is valid for exp
+func (c *Client) RefreshDocumentURL(ctx context.Context, thread string, user string, exp time.Duration, callOpts ...CallOption) (*DocumentURLResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.getDocubotDocURL(ctx, thread, user, exp)
+}
+
+// DocumentURLProvider hands out a url for a docubot document, minting a new one whenever the last
+// is about to expire. It is safe for concurrent use, UIs that cache links can hold one per document.
+type DocumentURLProvider struct {
+	// Margin is how long before expiry the url is replaced
+	Margin time.Duration
+
+	client   *Client
+	thread   string
+	user     string
+	exp      time.Duration
+	callOpts []CallOption
+	mu       sync.Mutex
+	response *DocumentURLResponse
+}
+
+// NewDocumentURLProvider initializes a provider of urls for the docubot document that are each valid for exp,
+// callOpts are applied to every request the provider makes
+func (c *Client) NewDocumentURLProvider(thread string, user string, exp time.Duration, callOpts ...CallOption) *DocumentURLProvider {
+	return &DocumentURLProvider{
+		Margin:   defaultURLRefreshMargin,
+		client:   c,
+		thread:   thread,
+		user:     user,
+		exp:      exp,
+		callOpts: callOpts,
+	}
+}
+
+// URL returns a url for the document that is valid for at least Margin
+func (p *DocumentURLProvider) URL(ctx context.Context) (string, error) {
+	response, err := p.Response(ctx)
+	if err != nil {
+		return "", err
+	}
+	return response.Data.URL, nil
+}
+
+// Response returns the response for a url that is valid for at least Margin
+func (p *DocumentURLProvider) Response(ctx context.Context) (*DocumentURLResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.response != nil && !p.response.ExpiresWithin(p.Margin) {
+		return p.response, nil
+	}
+	response, err := p.client.RefreshDocumentURL(ctx, p.thread, p.user, p.exp, p.callOpts...)
+	if err != nil {
+		return nil, err
+	}
+	p.response = response
+	return response, nil
+}
+
+// Invalidate discards the cached url so the next call mints a new one
+func (p *DocumentURLProvider) Invalidate() {
+	p.mu.Lock()
+	p.response = nil
+	p.mu.Unlock()
+}
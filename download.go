@@ -0,0 +1,96 @@
+package docubotlib
+
+import (
+	"context"
+	"io"
+)
+
+// GetDocubotDocTo downloads the docubot document and writes it to w, returning the number of bytes written.
+// The download stops when ctx is done, and the connection is always released.
+func (c *Client) GetDocubotDocTo(ctx context.Context, thread string, user string, w io.Writer, callOpts ...CallOption) (int64, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	doc, err := c.getDocubotDoc(ctx, thread, user)
+	if err != nil {
+		return 0, err
+	}
+	defer doc.Close()
+	return io.Copy(w, contextReader{ctx: ctx, r: doc})
+}
+
+// GetThreadDocumentTo downloads one of the documents generated in a thread and writes it to w,
+// returning the number of bytes written
+func (c *Client) GetThreadDocumentTo(ctx context.Context, thread string, user string, documentID string, w io.Writer, callOpts ...CallOption) (int64, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	doc, err := c.GetThreadDocument(ctx, thread, user, documentID)
+	if err != nil {
+		return 0, err
+	}
+	defer doc.Close()
+	return io.Copy(w, contextReader{ctx: ctx, r: doc})
+}
+
+// SavedDocument describes a document written to a DocumentStore
+type SavedDocument struct {
+	// Key is the key the document was stored under
+	Key         string
+	ContentType string
+	Size        int64
+}
+
+// DownloadDocubotDoc starts downloading the docubot document, detecting its content type and suggested extension
+func (c *Client) DownloadDocubotDoc(ctx context.Context, thread string, user string, callOpts ...CallOption) (*DocumentDownload, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.download(ctx, c.docubotDocDownloadURL(thread, user))
+}
+
+// DownloadThreadDocument starts downloading one of the documents generated in a thread, detecting its
+// content type and suggested extension
+func (c *Client) DownloadThreadDocument(ctx context.Context, thread string, user string, documentID string, callOpts ...CallOption) (*DocumentDownload, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.download(ctx, c.threadDocumentDownloadURL(thread, user, documentID))
+}
+
+func (c *Client) download(ctx context.Context, url string) (*DocumentDownload, error) {
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, c.responseError(resp, nil)
+	}
+	return newDocumentDownload(resp), nil
+}
+
+// SaveDocubotDocWithExtension downloads the docubot document and writes it to store under baseKey
+// followed by the extension matching the document's content type, such as ".pdf"
+func (c *Client) SaveDocubotDocWithExtension(ctx context.Context, thread string, user string, store DocumentStore, baseKey string, callOpts ...CallOption) (*SavedDocument, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	download, err := c.DownloadDocubotDoc(ctx, thread, user)
+	if err != nil {
+		return nil, err
+	}
+	defer download.Body.Close()
+	saved := &SavedDocument{Key: baseKey + download.Extension, ContentType: download.ContentType}
+	counter := &countingReader{r: download.Body}
+	if err := store.Put(ctx, saved.Key, counter); err != nil {
+		return nil, err
+	}
+	saved.Size = counter.n
+	return saved, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,82 @@
+package docubotlib
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// dryRunHeader asks docubot to validate a request without persisting anything
+const dryRunHeader string = "X-Docubot-Dry-Run"
+
+// CapabilityDryRun is reported by docubot instances that validate dry run requests
+const CapabilityDryRun string = "dryRun"
+
+// dryRunResponseBody is the body of the response made up for dry runs docubot can't validate
+const dryRunResponseBody string = `{"data":{},"meta":{"dryRun":true}}`
+
+// DryRunRequest is a mutating request that was made in dry run mode
+type DryRunRequest struct {
+	Method string
+	URL    string
+	// Header has credentials masked
+	Header http.Header
+	Body   []byte
+	// Validated reports whether the request was sent to docubot to be validated,
+	// when false nothing was sent and the response was made up
+	Validated bool
+}
+
+// WithDryRun makes a call in dry run mode, see Client.DryRun
+func WithDryRun() CallOption {
+	return func(o *callOptions) {
+		o.dryRun = true
+	}
+}
+
+// mutates reports whether the request changes anything stored by docubot, previews never do
+func mutates(req *http.Request) bool {
+	if req.Method == "GET" || req.Method == "HEAD" || req.Method == "OPTIONS" {
+		return false
+	}
+	return !strings.Contains(req.URL.Path, "/api/v1/preview")
+}
+
+// doDryRun sends a dry run request to be validated when docubot supports it, otherwise it makes up
+// an empty successful response. Either way the request is passed to OnDryRun.
+func (c *Client) doDryRun(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		setReplayableBody(req, body)
+	}
+	capabilities, err := c.cachedCapabilities(req.Context())
+	validated := err == nil && capabilities.Supports(CapabilityDryRun)
+	if c.OnDryRun != nil {
+		c.OnDryRun(DryRunRequest{
+			Method:    req.Method,
+			URL:       req.URL.String(),
+			Header:    redactHeader(req.Header),
+			Body:      body,
+			Validated: validated,
+		})
+	}
+	if validated {
+		return c.send(req)
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(dryRunResponseBody)),
+		Request:    req,
+	}, nil
+}
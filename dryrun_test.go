@@ -0,0 +1,63 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDryRunMasksCredentials(t *testing.T) {
+	_, c := newFakeDocubot(t)
+	c.DryRun = true
+	var got []DryRunRequest
+	c.OnDryRun = func(req DryRunRequest) { got = append(got, req) }
+	if err := c.DeleteDocubotVariables(context.Background(), "thread_1", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %v dry run requests, want 1", len(got))
+	}
+	if got[0].Header.Get("Authorization") != redactedValue {
+		t.Errorf("OnDryRun got Authorization %q, want it masked", got[0].Header.Get("Authorization"))
+	}
+}
+
+func TestDeleteUserDataDryRunPages(t *testing.T) {
+	var threads []Thread
+	for i := 0; i < 2*deleteUserDataBatchSize+10; i++ {
+		threads = append(threads, Thread{ID: fmt.Sprintf("thread_%v", i), UserID: "user"})
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/v1/docubot" {
+			http.Error(w, `{"errors":["not found"]}`, http.StatusNotFound)
+			return
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end, next := start+limit, ""
+		if end < len(threads) {
+			next = strconv.Itoa(end)
+		} else {
+			end = len(threads)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ThreadListResponse{Data: ThreadListData{Threads: threads[start:end]}, Meta: Page{NextCursor: next}})
+	}))
+	defer server.Close()
+	c := NewClient(server.URL, "key", "secret")
+	c.DryRun = true
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := c.DeleteUserData(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Threads) != len(threads) {
+		t.Errorf("the dry run reported %v threads, want %v", len(report.Threads), len(threads))
+	}
+}
@@ -0,0 +1,80 @@
+package docubotlib
+
+import (
+	"context"
+	"time"
+)
+
+// defaultEmailLinkMargin is how much longer than promised an EmailLink's url is requested for when Margin isn't set
+const defaultEmailLinkMargin time.Duration = time.Hour
+
+// EmailLinkOptions configures Client.EmailLink
+type EmailLinkOptions struct {
+	// Validity is how long the email promises the link works
+	Validity time.Duration
+	// Margin is how much longer than Validity the url is requested for, an hour when zero, so a link opened
+	// just before its promised expiry, or by a reader whose clock is off, still works
+	Margin time.Duration
+	// RegenerateURL, when set, returns a link to your own application minting a new url for the thread's document,
+	// it is shown in the email for readers who open the email after the link expired
+	RegenerateURL func(thread string, user string) string
+}
+
+// EmailLink is a document url for an email, its ExpiresAt is safe to print in the email
+type EmailLink struct {
+	URL string
+	// ExpiresAt is when the email can say the link stops working, Margin before the url actually expires
+	ExpiresAt time.Time
+	// Shortened is set when the link expires before the promised Validity, such as when docubot capped the url
+	// or Validity is close to MaxDocumentURLDuration
+	Shortened bool
+	// RegenerateURL is the link to get a new url once this one expired, empty without EmailLinkOptions.RegenerateURL
+	RegenerateURL string
+
+	client   *Client
+	thread   string
+	user     string
+	opts     EmailLinkOptions
+	callOpts []CallOption
+}
+
+// EmailLink requests a url for the docubot document to send by email, valid for opts.Validity plus a safety margin
+func (c *Client) EmailLink(ctx context.Context, thread string, user string, opts EmailLinkOptions, callOpts ...CallOption) (*EmailLink, error) {
+	margin := opts.Margin
+	if margin <= 0 {
+		margin = defaultEmailLinkMargin
+	}
+	exp := opts.Validity + margin
+	if exp > MaxDocumentURLDuration && opts.Validity <= MaxDocumentURLDuration {
+		exp = MaxDocumentURLDuration
+	}
+	requested := time.Now()
+	response, err := c.getDocubotDocURL(withCallOptions(ctx, callOpts), thread, user, exp)
+	if err != nil {
+		return nil, err
+	}
+	link := &EmailLink{
+		URL:       response.Data.URL,
+		ExpiresAt: response.ExpiresAt.Add(-margin),
+		client:    c,
+		thread:    thread,
+		user:      user,
+		opts:      opts,
+		callOpts:  callOpts,
+	}
+	link.Shortened = link.ExpiresAt.Before(requested.Add(opts.Validity))
+	if opts.RegenerateURL != nil {
+		link.RegenerateURL = opts.RegenerateURL(thread, user)
+	}
+	return link, nil
+}
+
+// Expired reports whether the link is past the expiry the email promised
+func (l *EmailLink) Expired() bool {
+	return !time.Now().Before(l.ExpiresAt)
+}
+
+// Regenerate requests a new link with the same options, for the handler behind RegenerateURL or for resending the email
+func (l *EmailLink) Regenerate(ctx context.Context) (*EmailLink, error) {
+	return l.client.EmailLink(ctx, l.thread, l.user, l.opts, l.callOpts...)
+}
@@ -0,0 +1,83 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EmbedBranding customizes the look of docubot's hosted chat widget
+type EmbedBranding struct {
+	Title        string `json:"title,omitempty"`
+	Greeting     string `json:"greeting,omitempty"`
+	LogoURL      string `json:"logoUrl,omitempty"`
+	PrimaryColor string `json:"primaryColor,omitempty"`
+	AccentColor  string `json:"accentColor,omitempty"`
+}
+
+// EmbedOptions configures the widget created by GenerateEmbedURL
+type EmbedOptions struct {
+	// Thread resumes an existing thread, a new thread is started when empty
+	Thread string
+	// ExpiresIn is how long the url can be opened for, rounded up to whole seconds. The server default is used
+	// when zero.
+	ExpiresIn time.Duration
+	// ReturnURL is where the widget sends the user once the interview is complete
+	ReturnURL string
+	// Locale is the locale the interview is conducted in
+	Locale   string
+	Branding *EmbedBranding
+}
+
+// EmbedResponse is the response received from generating an embed url
+type EmbedResponse struct {
+	Data EmbedData              `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// EmbedData is the response data received from generating an embed url
+type EmbedData struct {
+	// URL is the signed url to load in an iframe
+	URL string `json:"url"`
+	// Config is the signed configuration for the javascript embed snippet
+	Config    map[string]interface{} `json:"config"`
+	ThreadID  string                 `json:"threadId"`
+	ExpiresAt time.Time              `json:"expiresAt"`
+}
+
+// GenerateEmbedURL generates a signed url for embedding docubot's hosted chat widget for a user
+func (c *Client) GenerateEmbedURL(ctx context.Context, docTreeID string, user string, opts EmbedOptions, callOpts ...CallOption) (*EmbedResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	if opts.ExpiresIn < 0 {
+		return nil, errors.New("docubot: embed expiry must not be negative")
+	}
+	body := map[string]interface{}{
+		"docTreeId": docTreeID,
+		"user":      user,
+	}
+	if opts.Thread != "" {
+		body["thread"] = opts.Thread
+	}
+	if opts.ExpiresIn > 0 {
+		// rounded up, a url for less than a second would otherwise ask for the server default
+		body["duration"] = int((opts.ExpiresIn + time.Second - 1) / time.Second)
+	}
+	if opts.ReturnURL != "" {
+		body["returnUrl"] = opts.ReturnURL
+	}
+	if opts.Locale != "" {
+		body["locale"] = opts.Locale
+	}
+	if opts.Branding != nil {
+		body["branding"] = opts.Branding
+	}
+	url := fmt.Sprintf("%v/api/v1/embed", c.DocubotAPIURLBase)
+	req, err := c.newRequest(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	var response EmbedResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
@@ -0,0 +1,36 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEmbedExpiryRoundsUp(t *testing.T) {
+	var duration interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		duration = body["duration"]
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"url":"https://docubot.test/embed"}}`))
+	}))
+	defer server.Close()
+	c := NewClient(server.URL, "key", "secret")
+	for expiresIn, want := range map[time.Duration]float64{
+		500 * time.Millisecond:  1,
+		time.Second:             1,
+		1500 * time.Millisecond: 2,
+		time.Hour:               3600,
+	} {
+		if _, err := c.GenerateEmbedURL(context.Background(), "tree", "user", EmbedOptions{ExpiresIn: expiresIn}); err != nil {
+			t.Fatal(err)
+		}
+		if duration != want {
+			t.Errorf("ExpiresIn %v asked for %v seconds, want %v", expiresIn, duration, want)
+		}
+	}
+}
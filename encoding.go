@@ -0,0 +1,201 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// Encoder serializes request payloads, the Content-Type it returns tells docubot how to read them
+type Encoder interface {
+	ContentType() string
+	Encode(v interface{}) ([]byte, error)
+}
+
+// JSONEncoder sends payloads as json, the default
+type JSONEncoder struct{}
+
+// ContentType returns application/json
+func (JSONEncoder) ContentType() string {
+	return "application/json"
+}
+
+// Encode encodes v as json
+func (JSONEncoder) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// MessagePackEncoder sends payloads as MessagePack, which is smaller than json for variable heavy payloads.
+// Values are encoded the way they are encoded as json, so json struct tags apply.
+// Requests docubot answers with 415 Unsupported Media Type are sent again as json,
+// and the client keeps using json from then on.
+type MessagePackEncoder struct{}
+
+// ContentType returns application/msgpack
+func (MessagePackEncoder) ContentType() string {
+	return "application/msgpack"
+}
+
+// Encode encodes v as MessagePack
+func (MessagePackEncoder) Encode(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := writeMessagePack(&b, value); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// jsonFallbackKey holds the json payload of a request encoded another way, to resend it if docubot can't read it
+type jsonFallbackKey struct{}
+
+// encodeBody encodes a request payload with the client's Encoder, returning the payload and its content type
+func (c *Client) encodeBody(ctx context.Context, body interface{}) (context.Context, []byte, string, error) {
+	jsonStr, err := json.Marshal(body)
+	if err != nil {
+		return ctx, nil, "", err
+	}
+	c.mu.Lock()
+	unsupported := c.encoderUnsupported
+	c.mu.Unlock()
+	if c.Encoder == nil || unsupported || c.Encoder.ContentType() == (JSONEncoder{}).ContentType() {
+		return ctx, jsonStr, JSONEncoder{}.ContentType(), nil
+	}
+	payload, err := c.Encoder.Encode(body)
+	if err != nil {
+		return ctx, nil, "", err
+	}
+	return context.WithValue(ctx, jsonFallbackKey{}, jsonStr), payload, c.Encoder.ContentType(), nil
+}
+
+// resendAsJSON sends the request again as json when docubot couldn't read its payload, returning resp otherwise
+func (c *Client) resendAsJSON(req *http.Request, resp *http.Response) (*http.Response, error) {
+	jsonStr, ok := req.Context().Value(jsonFallbackKey{}).([]byte)
+	if !ok || resp.StatusCode != http.StatusUnsupportedMediaType {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	c.mu.Lock()
+	c.encoderUnsupported = true
+	c.mu.Unlock()
+	setReplayableBody(req, jsonStr)
+	req.Header.Set("Content-Type", JSONEncoder{}.ContentType())
+	return c.sendWithRetries(req)
+}
+
+// writeMessagePack writes a value decoded from json with UseNumber
+func writeMessagePack(b *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteByte(0xc0)
+	case bool:
+		if v {
+			b.WriteByte(0xc3)
+		} else {
+			b.WriteByte(0xc2)
+		}
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			writeMessagePackInt(b, i)
+			return nil
+		}
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return err
+		}
+		b.WriteByte(0xcb)
+		binary.Write(b, binary.BigEndian, math.Float64bits(f))
+	case string:
+		n := len(v)
+		switch {
+		case n < 32:
+			b.WriteByte(0xa0 | byte(n))
+		case n <= math.MaxUint8:
+			b.WriteByte(0xd9)
+			b.WriteByte(byte(n))
+		case n <= math.MaxUint16:
+			b.WriteByte(0xda)
+			binary.Write(b, binary.BigEndian, uint16(n))
+		default:
+			b.WriteByte(0xdb)
+			binary.Write(b, binary.BigEndian, uint32(n))
+		}
+		b.WriteString(v)
+	case []interface{}:
+		writeMessagePackLength(b, len(v), 0x90, 0xdc)
+		for _, item := range v {
+			if err := writeMessagePack(b, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		writeMessagePackLength(b, len(v), 0x80, 0xde)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			writeMessagePack(b, key)
+			if err := writeMessagePack(b, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("docubot: can't encode %T as MessagePack", value)
+	}
+	return nil
+}
+
+func writeMessagePackInt(b *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i < 128:
+		b.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		b.WriteByte(byte(int8(i)))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		b.WriteByte(0xd0)
+		b.WriteByte(byte(int8(i)))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		b.WriteByte(0xd1)
+		binary.Write(b, binary.BigEndian, int16(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		b.WriteByte(0xd2)
+		binary.Write(b, binary.BigEndian, int32(i))
+	default:
+		b.WriteByte(0xd3)
+		binary.Write(b, binary.BigEndian, i)
+	}
+}
+
+// writeMessagePackLength writes the header of an array or map, fix is the header of short ones
+// and long is the header of ones with a 16 bit length, the next header has a 32 bit length
+func writeMessagePackLength(b *bytes.Buffer, n int, fix byte, long byte) {
+	switch {
+	case n < 16:
+		b.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(long)
+		binary.Write(b, binary.BigEndian, uint16(n))
+	default:
+		b.WriteByte(long + 1)
+		binary.Write(b, binary.BigEndian, uint32(n))
+	}
+}
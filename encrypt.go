@@ -0,0 +1,147 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// encryptedDocumentMagic prefixes every document written by EncryptDocument
+var encryptedDocumentMagic = []byte("DBE1")
+
+// ErrNotEncrypted is returned when decrypting data that was not produced by EncryptDocument
+var ErrNotEncrypted = errors.New("docubot: document is not encrypted")
+
+// KeyProvider supplies AES keys for client side encryption, typically backed by a KMS
+type KeyProvider interface {
+	// DataKey returns a key to encrypt with and a wrapped form of it that is stored beside the ciphertext
+	DataKey(ctx context.Context) (key []byte, wrapped []byte, err error)
+	// UnwrapKey returns the key for a wrapped form previously returned by DataKey
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// StaticKey is a KeyProvider that always uses the same 16, 24, or 32 byte AES key
+type StaticKey []byte
+
+// DataKey returns the static key, nothing needs to be stored beside the ciphertext
+func (k StaticKey) DataKey(ctx context.Context) ([]byte, []byte, error) {
+	return k, nil, nil
+}
+
+// UnwrapKey returns the static key
+func (k StaticKey) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	return k, nil
+}
+
+// EncryptDocument encrypts a document with AES-GCM using a key from keys
+func EncryptDocument(ctx context.Context, keys KeyProvider, plaintext []byte) ([]byte, error) {
+	key, wrapped, err := keys.DataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) > 0xffff {
+		return nil, errors.New("docubot: wrapped key is too large")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	out.Write(encryptedDocumentMagic)
+	var size [2]byte
+	binary.BigEndian.PutUint16(size[:], uint16(len(wrapped)))
+	out.Write(size[:])
+	out.Write(wrapped)
+	out.Write(nonce)
+	// the header is authenticated so a tampered wrapped key is detected
+	header := append([]byte(nil), out.Bytes()...)
+	return gcm.Seal(header, nonce, plaintext, header), nil
+}
+
+// DecryptDocument decrypts a document produced by EncryptDocument
+func DecryptDocument(ctx context.Context, keys KeyProvider, ciphertext []byte) ([]byte, error) {
+	magic := len(encryptedDocumentMagic)
+	if len(ciphertext) < magic+2 || !bytes.Equal(ciphertext[:magic], encryptedDocumentMagic) {
+		return nil, ErrNotEncrypted
+	}
+	wrappedLen := int(binary.BigEndian.Uint16(ciphertext[magic:]))
+	offset := magic + 2
+	if len(ciphertext) < offset+wrappedLen {
+		return nil, ErrNotEncrypted
+	}
+	wrapped := ciphertext[offset : offset+wrappedLen]
+	offset += wrappedLen
+	key, err := keys.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < offset+gcm.NonceSize() {
+		return nil, ErrNotEncrypted
+	}
+	nonce := ciphertext[offset : offset+gcm.NonceSize()]
+	offset += gcm.NonceSize()
+	return gcm.Open(nil, nonce, ciphertext[offset:], ciphertext[:offset])
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptedStore is a DocumentStore that encrypts documents before handing them to Store
+type EncryptedStore struct {
+	Store DocumentStore
+	Keys  KeyProvider
+}
+
+// NewEncryptedStore wraps store so documents are encrypted at rest with keys from keys
+func NewEncryptedStore(store DocumentStore, keys KeyProvider) *EncryptedStore {
+	return &EncryptedStore{Store: store, Keys: keys}
+}
+
+// Put encrypts the document and writes it to the underlying store
+func (s *EncryptedStore) Put(ctx context.Context, key string, r io.Reader) error {
+	plaintext, err := io.ReadAll(contextReader{ctx: ctx, r: r})
+	if err != nil {
+		return err
+	}
+	ciphertext, err := EncryptDocument(ctx, s.Keys, plaintext)
+	if err != nil {
+		return err
+	}
+	return s.Store.Put(ctx, key, bytes.NewReader(ciphertext))
+}
+
+// Get reads the document from the underlying store and decrypts it
+func (s *EncryptedStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := s.Store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	ciphertext, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := DecryptDocument(ctx, s.Keys, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(plaintext)), nil
+}
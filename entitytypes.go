@@ -0,0 +1,59 @@
+package docubotlib
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// EntityDecoder decodes the metadata of a question of an entity type the module has no fields for,
+// such as one docubot introduced after this version of the module
+type EntityDecoder func(question *QuestionNode, metaData map[string]interface{}) (interface{}, error)
+
+var (
+	entityDecodersMu sync.RWMutex
+	entityDecoders   = map[string]EntityDecoder{}
+)
+
+// RegisterEntityDecoder registers the decoder DecodeMetaData uses for questions of the entity type,
+// replacing any registered before. Register decoders during initialization.
+func RegisterEntityDecoder(entityType string, decoder EntityDecoder) {
+	entityDecodersMu.Lock()
+	defer entityDecodersMu.Unlock()
+	entityDecoders[strings.ToLower(entityType)] = decoder
+}
+
+// DecodeMetaData decodes the question's metadata with the decoder registered for its entity type,
+// it returns the raw metadata when no decoder is registered and nil when the question has none
+func (n *QuestionNode) DecodeMetaData() (interface{}, error) {
+	var raw map[string]interface{}
+	if n.MetaData != nil {
+		raw = n.MetaData.RawMetaData
+	}
+	entityDecodersMu.RLock()
+	decoder, ok := entityDecoders[strings.ToLower(n.EntityType)]
+	entityDecodersMu.RUnlock()
+	if !ok {
+		if raw == nil {
+			return nil, nil
+		}
+		return raw, nil
+	}
+	return decoder(n, raw)
+}
+
+// UnmarshalJSON decodes the typed fields and keeps every field in RawMetaData
+func (m *QuestionNodeMetaData) UnmarshalJSON(data []byte) error {
+	type typed QuestionNodeMetaData
+	if err := json.Unmarshal(data, (*typed)(m)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &m.RawMetaData)
+}
+
+// MarshalJSON encodes RawMetaData with the typed fields taking precedence, so metadata of unknown
+// entity types survives reading a tree and saving it back
+func (m QuestionNodeMetaData) MarshalJSON() ([]byte, error) {
+	type typed QuestionNodeMetaData
+	return mergeRawMeta(m.RawMetaData, typed(m))
+}
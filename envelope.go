@@ -0,0 +1,30 @@
+package docubotlib
+
+import (
+	"encoding/json"
+)
+
+// Envelope is the {data, meta} body every docubot endpoint responds with, its payload left undecoded.
+// It decodes responses of endpoints that have no response type yet, such as ones called with Client.Do.
+type Envelope struct {
+	Data json.RawMessage `json:"data"`
+	Meta json.RawMessage `json:"meta,omitempty"`
+}
+
+// Decode decodes the envelope's data into v, such as a *ThreadData
+func (e *Envelope) Decode(v interface{}) error {
+	return decodeRaw(e.Data, v)
+}
+
+// DecodeMeta decodes the envelope's meta into v, such as a *Page for list endpoints
+func (e *Envelope) DecodeMeta(v interface{}) error {
+	return decodeRaw(e.Meta, v)
+}
+
+// decodeRaw decodes raw into v, leaving v untouched when the field was missing or null
+func decodeRaw(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,39 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	f, c := newFakeDocubot(t)
+	f.addThread(Thread{ID: "thread_1", UserID: "user"}, nil)
+	var envelope Envelope
+	if err := c.Do(context.Background(), "GET", "/api/v1/docubot/thread_1", nil, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	var data ThreadData
+	if err := envelope.Decode(&data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Thread.ID != "thread_1" {
+		t.Errorf("decoded thread %q, want thread_1", data.Thread.ID)
+	}
+
+	if err := json.Unmarshal([]byte(`{"data":{"threads":[]},"meta":{"nextCursor":"next","total":3}}`), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	var page Page
+	if err := envelope.DecodeMeta(&page); err != nil {
+		t.Fatal(err)
+	}
+	if page.NextCursor != "next" || page.Total != 3 {
+		t.Errorf("decoded meta %+v", page)
+	}
+	envelope = Envelope{}
+	page = Page{Total: -1}
+	if err := envelope.DecodeMeta(&page); err != nil || page.Total != -1 {
+		t.Errorf("decoding a missing meta got %+v, %v", page, err)
+	}
+}
@@ -0,0 +1,161 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// resetEpochThreshold separates X-RateLimit-Reset values sent as unix timestamps from ones sent as seconds to wait
+const resetEpochThreshold int64 = 1000000000
+
+// APIError is the error returned when docubot responds with a non 2xx status
+type APIError struct {
+	StatusCode int
+	// Code is docubot's machine readable code for the error, such as ErrorCodeThreadNotFound,
+	// empty when docubot didn't send one. Unlike Message it doesn't change between releases.
+	Code string
+	// Message is the first error reported by docubot
+	Message string
+	// Errors are all of the errors reported by docubot
+	Errors []string
+	// RateLimit is the rate limit status reported with the error, if any
+	RateLimit RateLimitStatus
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// RateLimited reports whether the request was rejected for exceeding the rate limit
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// Retryable reports whether the request may succeed if it is sent again
+func (e *APIError) Retryable() bool {
+	return e.RateLimited() || e.StatusCode >= 500
+}
+
+// Error codes reported in APIError.Code
+const (
+	ErrorCodeThreadNotFound       string = "THREAD_NOT_FOUND"
+	ErrorCodeTreeNotFound         string = "TREE_NOT_FOUND"
+	ErrorCodeDocumentNotFound     string = "DOC_NOT_FOUND"
+	ErrorCodeDocumentNotReady     string = "DOC_NOT_READY"
+	ErrorCodeInvalidAnswer        string = "INVALID_ANSWER"
+	ErrorCodeInvalidRequest       string = "INVALID_REQUEST"
+	ErrorCodeUnauthorized         string = "UNAUTHORIZED"
+	ErrorCodeForbidden            string = "FORBIDDEN"
+	ErrorCodeRateLimited          string = "RATE_LIMITED"
+	ErrorCodeConflict             string = "CONFLICT"
+	ErrorCodeThreadComplete       string = "THREAD_COMPLETE"
+	ErrorCodeThreadPaused         string = "THREAD_PAUSED"
+	ErrorCodeIdempotencyKeyReused string = "IDEMPOTENCY_KEY_REUSED"
+	ErrorCodeInternal             string = "INTERNAL"
+)
+
+// ErrorCode returns the docubot error code of err, empty if err isn't an APIError or has no code
+func ErrorCode(err error) string {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.Code
+	}
+	return ""
+}
+
+// IsRetryable reports whether a request that failed with err may succeed if it is sent again.
+// Network errors and docubot server errors are retryable, rejected requests and cancellations aren't.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.Retryable()
+	}
+	return true
+}
+
+// Constraints reported by ValidationError
+const (
+	ConstraintRequired      string = "required"
+	ConstraintInvalidDate   string = "invalidDate"
+	ConstraintInvalidNumber string = "invalidNumber"
+	ConstraintInvalidChoice string = "invalidChoice"
+	ConstraintInvalidFormat string = "invalidFormat"
+)
+
+// ValidationError is returned when docubot rejects an answer
+type ValidationError struct {
+	APIError
+	// VariableName is the variable the rejected answer was for
+	VariableName string
+	// Constraint is the constraint the answer violated, such as ConstraintInvalidDate
+	Constraint string
+	// Reprompt is the question docubot suggests asking the user again
+	Reprompt string
+}
+
+// Unwrap allows errors.As to match the ValidationError as an APIError
+func (e *ValidationError) Unwrap() error {
+	return &e.APIError
+}
+
+// ValidationErrorDetail is the detail of a rejected answer in an error response
+type ValidationErrorDetail struct {
+	VariableName string `json:"variableName"`
+	Constraint   string `json:"constraint"`
+	Reprompt     string `json:"reprompt"`
+}
+
+// RateLimitStatus is the rate limit state last reported by docubot
+type RateLimitStatus struct {
+	// Limit is the number of requests allowed in the current window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends
+	Reset time.Time
+	// ObservedAt is when the status was received, zero if docubot hasn't reported a status yet
+	ObservedAt time.Time
+}
+
+// Known reports whether docubot has reported a rate limit status
+func (s RateLimitStatus) Known() bool {
+	return !s.ObservedAt.IsZero()
+}
+
+// Exhausted reports whether no requests remain before Reset
+func (s RateLimitStatus) Exhausted() bool {
+	return s.Known() && s.Remaining <= 0 && time.Now().Before(s.Reset)
+}
+
+// RateLimitStatus returns the rate limit status from the most recent docubot response
+func (c *Client) RateLimitStatus() RateLimitStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit
+}
+
+func parseRateLimitStatus(header http.Header) (RateLimitStatus, bool) {
+	limit := header.Get("X-RateLimit-Limit")
+	remaining := header.Get("X-RateLimit-Remaining")
+	if limit == "" && remaining == "" {
+		return RateLimitStatus{}, false
+	}
+	now := time.Now()
+	status := RateLimitStatus{ObservedAt: now}
+	status.Limit, _ = strconv.Atoi(limit)
+	status.Remaining, _ = strconv.Atoi(remaining)
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if reset >= resetEpochThreshold {
+			status.Reset = time.Unix(reset, 0)
+		} else {
+			status.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+	return status, true
+}
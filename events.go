@@ -0,0 +1,262 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultPollWait is how long a long poll waits for events when SubscribeOptions.PollWait is zero
+const defaultPollWait time.Duration = 30 * time.Second
+
+// SubscribeOptions filters the events SubscribeEvents delivers
+type SubscribeOptions struct {
+	// Types are the event types to deliver, every type when empty
+	Types []string
+	// Threads are the threads to deliver events of, every thread when empty
+	Threads []string
+	// After resumes the subscription after the event with the id
+	After string
+	// IncludeTranscript and IncludeVariables add the thread's transcript and final variables to completion events,
+	// so they can be handled without fetching the thread
+	IncludeTranscript bool
+	IncludeVariables  bool
+	// StuckAfter is how many answers in a row a question rejects before EventThreadStuck is delivered,
+	// docubot's default when zero
+	StuckAfter int
+	// Variables limit EventVariableAnswered events to answers of these variables, every variable when empty
+	Variables []string
+	// LongPoll polls for events instead of streaming them, subscriptions switch to it by themselves
+	// when streaming fails, as it does behind proxies that block server sent events
+	LongPoll bool
+	// PollWait is how long each long poll waits for an event before returning empty, 30 seconds when zero
+	PollWait time.Duration
+}
+
+// EventsResponse is a data model for a page of events returned by a long poll
+type EventsResponse struct {
+	Data EventsResponseData `json:"data"`
+	Meta Page               `json:"meta"`
+}
+
+// EventsResponseData is a data model for the data of EventsResponse
+type EventsResponseData struct {
+	Events []WebhookEvent `json:"events"`
+}
+
+// SubscribeEvents delivers thread and document events as they happen, the same events webhooks receive.
+// Events are streamed, falling back to long polling when the stream can't be opened.
+// The error channel receives the error that ended the subscription, the events channel is closed first.
+// Client.Shutdown ends the subscription with ErrClosed.
+func (c *Client) SubscribeEvents(ctx context.Context, opts SubscribeOptions, callOpts ...CallOption) (<-chan WebhookEvent, <-chan error) {
+	ctx = withCallOptions(ctx, callOpts)
+	events := make(chan WebhookEvent)
+	errs := make(chan error, 1)
+	started := c.background(ctx, true, func(ctx context.Context) {
+		s := eventSubscription{client: c, opts: opts, after: opts.After, longPoll: opts.LongPoll, events: events}
+		err := s.run(ctx)
+		close(events)
+		if err != nil {
+			errs <- c.shutdownError(err)
+		}
+		close(errs)
+	})
+	if !started {
+		close(events)
+		errs <- ErrClosed
+		close(errs)
+	}
+	return events, errs
+}
+
+type eventSubscription struct {
+	client   *Client
+	opts     SubscribeOptions
+	after    string
+	longPoll bool
+	events   chan<- WebhookEvent
+}
+
+// run streams or polls until an error ends the subscription. A panic, such as on a malformed event,
+// restarts the subscription after the last delivered event, waiting longer after each panic in a row.
+func (s *eventSubscription) run(ctx context.Context) error {
+	backoff := panicRestartBackoff
+	for {
+		err := s.next(ctx)
+		var panicked *PanicError
+		if !errors.As(err, &panicked) {
+			if err != nil {
+				return err
+			}
+			backoff = panicRestartBackoff
+			continue
+		}
+		if err := sleep(ctx, backoff); err != nil {
+			return err
+		}
+		if backoff *= 2; backoff > maxPanicRestartBackoff {
+			backoff = maxPanicRestartBackoff
+		}
+	}
+}
+
+// next streams or polls once, recovering from a panic
+func (s *eventSubscription) next(ctx context.Context) error {
+	return s.client.recoverCall("event subscription", func() error {
+		if s.longPoll {
+			return s.poll(ctx)
+		}
+		return s.stream(ctx)
+	})
+}
+
+func (s *eventSubscription) params() url.Values {
+	params := url.Values{}
+	for _, t := range s.opts.Types {
+		params.Add("type", t)
+	}
+	for _, thread := range s.opts.Threads {
+		params.Add("thread", thread)
+	}
+	if s.after != "" {
+		params.Set("after", s.after)
+	}
+	if s.opts.IncludeTranscript {
+		params.Set("includeTranscript", "true")
+	}
+	if s.opts.IncludeVariables {
+		params.Set("includeVariables", "true")
+	}
+	if s.opts.StuckAfter > 0 {
+		params.Set("stuckAfter", strconv.Itoa(s.opts.StuckAfter))
+	}
+	for _, variable := range s.opts.Variables {
+		params.Add("variable", variable)
+	}
+	return params
+}
+
+// stream reads events from the event stream until it ends, switching to long polling when it can't be opened
+func (s *eventSubscription) stream(ctx context.Context) error {
+	c := s.client
+	url := fmt.Sprintf("%v/api/v1/events/stream?%v", c.DocubotAPIURLBase, s.params().Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if s.after != "" {
+		req.Header.Set("Last-Event-ID", s.after)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.longPoll = true
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if !streamBlocked(resp.StatusCode) {
+			return c.responseError(resp, nil)
+		}
+		s.longPoll = true
+		return nil
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		// a proxy answered in docubot's place
+		s.longPoll = true
+		return nil
+	}
+	started := time.Now()
+	events := newSSEReader(resp.Body)
+	for {
+		sse, err := events.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the stream dropped, reconnect after the last event received
+			if time.Since(started) < time.Second {
+				return sleep(ctx, time.Second)
+			}
+			return nil
+		}
+		if sse.Data == "" {
+			continue
+		}
+		var event WebhookEvent
+		if err := json.Unmarshal([]byte(sse.Data), &event); err != nil {
+			return err
+		}
+		if event.ID == "" {
+			event.ID = sse.ID
+		}
+		if err := s.deliver(ctx, event); err != nil {
+			return err
+		}
+	}
+}
+
+// poll waits for the next events with a long poll
+func (s *eventSubscription) poll(ctx context.Context) error {
+	c := s.client
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return err
+	}
+	wait := s.opts.PollWait
+	if wait <= 0 {
+		wait = defaultPollWait
+	}
+	params := s.params()
+	params.Set("wait", strconv.Itoa(int(wait/time.Second)))
+	url := fmt.Sprintf("%v/api/v1/events?%v", c.DocubotAPIURLBase, params.Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	started := time.Now()
+	var response EventsResponse
+	if err := c.doJSON(req, nil, &response); err != nil {
+		return err
+	}
+	for _, event := range response.Data.Events {
+		if err := s.deliver(ctx, event); err != nil {
+			return err
+		}
+	}
+	if len(response.Data.Events) == 0 && time.Since(started) < time.Second {
+		// the poll returned without waiting, don't poll in a tight loop
+		return sleep(ctx, time.Second)
+	}
+	return nil
+}
+
+func (s *eventSubscription) deliver(ctx context.Context, event WebhookEvent) error {
+	select {
+	case s.events <- event:
+		if event.ID != "" {
+			s.after = event.ID
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// streamBlocked reports whether a failed event stream response means streams can't be used and polling may work
+func streamBlocked(status int) bool {
+	switch status {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusNotImplemented,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
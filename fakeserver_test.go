@@ -0,0 +1,111 @@
+package docubotlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDocubot is a docubot keeping threads and their variables in memory, it records every request body
+type fakeDocubot struct {
+	mu        sync.Mutex
+	threads   map[string]Thread
+	variables map[string]map[string]interface{}
+	bodies    []string
+	created   int
+}
+
+func newFakeDocubot(t testing.TB) (*fakeDocubot, *Client) {
+	f := &fakeDocubot{threads: map[string]Thread{}, variables: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, NewClient(server.URL, "key", "secret")
+}
+
+// addThread stores a thread with its variables as docubot would keep them
+func (f *fakeDocubot) addThread(thread Thread, variables map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.threads[thread.ID] = thread
+	f.variables[thread.ID] = variables
+}
+
+// sent reports whether any request body carried s
+func (f *fakeDocubot) sent(s string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, body := range f.bodies {
+		if strings.Contains(body, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeDocubot) stored(thread string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.variables[thread]
+}
+
+func (f *fakeDocubot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	raw, _ := io.ReadAll(r.Body)
+	var body struct {
+		DocTreeID  string                 `json:"docTreeId"`
+		User       string                 `json:"user"`
+		Variables  map[string]interface{} `json:"variables"`
+		Labels     []string               `json:"labels"`
+		Attributes map[string]string      `json:"attributes"`
+	}
+	json.Unmarshal(raw, &body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bodies = append(f.bodies, string(raw))
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+	respond := func(v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/api/v1/docubot/threads":
+		f.created++
+		thread := Thread{ID: fmt.Sprintf("thread_new_%v", f.created), UserID: body.User, DocumentTreeID: body.DocTreeID, Labels: body.Labels, Attributes: body.Attributes}
+		f.threads[thread.ID] = thread
+		f.variables[thread.ID] = copyVariables(body.Variables)
+		respond(map[string]interface{}{"data": map[string]interface{}{"thread": thread, "messages": []string{"Hello"}}})
+	case r.Method == "POST" && r.URL.Path == "/api/v1/batch":
+		var batch struct {
+			Operations []interface{} `json:"operations"`
+		}
+		json.Unmarshal(raw, &batch)
+		results := make([]map[string]interface{}, len(batch.Operations))
+		for i := range results {
+			results[i] = map[string]interface{}{"status": http.StatusOK, "body": map[string]interface{}{}}
+		}
+		respond(map[string]interface{}{"data": map[string]interface{}{"results": results}})
+	case r.Method == "POST" && r.URL.Path == "/api/v1/generated":
+		respond(map[string]interface{}{"data": map[string]interface{}{"documents": []interface{}{}}})
+	case len(path) == 2 && path[0] == "docubot" && r.Method == "GET":
+		respond(map[string]interface{}{"data": map[string]interface{}{"thread": f.threads[path[1]]}})
+	case len(path) == 3 && path[2] == "variables" && r.Method == "GET":
+		respond(map[string]interface{}{"data": map[string]interface{}{"variables": f.variables[path[1]]}})
+	case len(path) == 3 && path[2] == "variables" && r.Method == "PATCH":
+		if f.variables[path[1]] == nil {
+			f.variables[path[1]] = map[string]interface{}{}
+		}
+		for name, v := range body.Variables {
+			f.variables[path[1]][name] = v
+		}
+		respond(map[string]interface{}{"data": map[string]interface{}{"variables": f.variables[path[1]]}})
+	case len(path) == 3 && path[2] == "merge" && r.Method == "POST":
+		f.variables[path[1]] = body.Variables
+		respond(map[string]interface{}{"data": map[string]interface{}{"thread": f.threads[path[1]]}})
+	default:
+		http.Error(w, `{"errors":["not found"]}`, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,74 @@
+package docubotlib
+
+import (
+	"context"
+)
+
+// StartInterviewsOptions configures StartInterviews
+type StartInterviewsOptions struct {
+	// Thread configures every thread created, such as labels for the campaign
+	Thread StartThreadOptions
+	// InitialMessage, when set, is sent as each user's first message once their thread is started
+	InitialMessage string
+	// Concurrency is the number of interviews started at once, a small default is used when zero
+	Concurrency int
+}
+
+// InterviewStart is the outcome of starting one user's interview
+type InterviewStart struct {
+	User     string
+	ThreadID string
+	// Messages are docubot's latest messages to the user
+	Messages []string
+	Err      error
+}
+
+// StartInterviews starts the same interview for every user, such as for a bulk intake campaign.
+// The results are in the order of users, a failure for one user doesn't stop the others.
+func (c *Client) StartInterviews(ctx context.Context, docTreeID string, users []string, opts StartInterviewsOptions, callOpts ...CallOption) []InterviewStart {
+	ctx = withCallOptions(ctx, callOpts)
+	results := make([]InterviewStart, len(users))
+	for i, user := range users {
+		results[i] = InterviewStart{User: user, Err: context.Canceled}
+	}
+	c.runPool(ctx, opts.Concurrency, len(users), func(i int) {
+		results[i] = c.startInterview(ctx, docTreeID, users[i], opts)
+	}, func(i int, err error) {
+		c.reportError(err)
+		results[i].Err = err
+	})
+	if err := ctx.Err(); err != nil {
+		for i := range results {
+			if results[i].Err == context.Canceled {
+				results[i].Err = err
+			}
+		}
+	}
+	return results
+}
+
+func (c *Client) startInterview(ctx context.Context, docTreeID string, user string, opts StartInterviewsOptions) InterviewStart {
+	result := InterviewStart{User: user}
+	started, err := c.StartThread(ctx, docTreeID, user, opts.Thread)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.ThreadID = started.Data.Thread.ID
+	result.Messages = started.Data.Messages
+	if opts.InitialMessage == "" {
+		return result
+	}
+	key, err := newIdempotencyKey()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	response, err := c.sendMessage(ctx, opts.InitialMessage, result.ThreadID, user, docTreeID, "", key)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Messages = response.Data.Messages
+	return result
+}
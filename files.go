@@ -0,0 +1,120 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"time"
+)
+
+// EntityTypeFile is the entity type of questions answered by uploading a file
+const EntityTypeFile string = "file"
+
+// filePlaceholderPrefix starts placeholders that render the url of an uploaded file
+const filePlaceholderPrefix string = "file:"
+
+// FileVariable is the value stored in a variable of EntityTypeFile
+type FileVariable struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// FileUploadResponse is the response received from uploading a file to a variable
+type FileUploadResponse struct {
+	Data FileUploadData         `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// FileUploadData is the response data received from uploading a file to a variable
+type FileUploadData struct {
+	File FileVariable `json:"file"`
+}
+
+// FilePlaceholder returns the placeholder that renders the url of the file uploaded to the variable,
+// for example <img src="{{file:signature}}"> embeds an uploaded signature image
+func FilePlaceholder(variable string) string {
+	return "{{" + filePlaceholderPrefix + variable + "}}"
+}
+
+// UploadFile uploads a file and stores it in the variable for the provided user in the provided thread
+func (c *Client) UploadFile(ctx context.Context, thread string, user string, variable string, filename string, contentType string, file io.Reader, callOpts ...CallOption) (*FileUploadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/variables/%v/file?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		variable,
+		params.Encode(),
+	)
+	var response FileUploadResponse
+	err := c.uploadMultipart(ctx, url, filename, contentType, file, &response)
+	return &response, err
+}
+
+// uploadMultipart posts the file to url as the "file" part of a multipart form, streaming it as it is read
+func (c *Client) uploadMultipart(ctx context.Context, url string, filename string, contentType string, file io.Reader, response interface{}) error {
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filename))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+		part, err := form.CreatePart(header)
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	req, err := c.newRequest(ctx, "POST", url, nil)
+	if err != nil {
+		body.Close()
+		return err
+	}
+	req.Body = body
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	err = c.doJSON(req, nil, response)
+	body.Close()
+	return err
+}
+
+// File returns the file stored in a variable of EntityTypeFile
+func (d DocumentVariablesData) File(variable string) (*FileVariable, bool) {
+	return FileVariableValue(d.Variables, variable)
+}
+
+// FileVariableValue returns the file stored in a variable of EntityTypeFile
+func FileVariableValue(variables map[string]interface{}, variable string) (*FileVariable, bool) {
+	switch v := variables[variable].(type) {
+	case *FileVariable:
+		return v, v != nil
+	case FileVariable:
+		return &v, true
+	case map[string]interface{}:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		var file FileVariable
+		if err := json.Unmarshal(raw, &file); err != nil || file.URL == "" && file.ID == "" {
+			return nil, false
+		}
+		return &file, true
+	}
+	return nil, false
+}
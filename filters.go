@@ -0,0 +1,282 @@
+package docubotlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Placeholder filters, written after a variable's name as in {{closing_date | date:long}} and applied in order
+const (
+	// FilterUpper, FilterLower, FilterTitle, and FilterCapitalize change the casing of the value
+	FilterUpper      string = "upper"
+	FilterLower      string = "lower"
+	FilterTitle      string = "title"
+	FilterCapitalize string = "capitalize"
+	// FilterDate writes a date in a style: "long" as in January 2, 2006, the default, "short" the way the
+	// locale does, or "iso" as in 2006-01-02
+	FilterDate string = "date"
+	// FilterCurrency writes an amount of money the way the locale does, as in $1,234.56
+	FilterCurrency string = "currency"
+	// FilterNumber writes a number with the locale's separators, as in 1,234.5
+	FilterNumber string = "number"
+	// FilterOrdinal writes a whole number as an ordinal, as in 21st
+	FilterOrdinal string = "ordinal"
+)
+
+// Styles of FilterDate
+const (
+	DateStyleLong  string = "long"
+	DateStyleShort string = "short"
+	DateStyleISO   string = "iso"
+)
+
+// serverFilters are the names docubot knows the filters by
+var serverFilters = map[string]string{
+	FilterUpper:      "uppercase",
+	FilterLower:      "lowercase",
+	FilterTitle:      "titlecase",
+	FilterCapitalize: "capitalize",
+	FilterDate:       "formatDate",
+	FilterCurrency:   "formatCurrency",
+	FilterNumber:     "formatNumber",
+	FilterOrdinal:    "ordinal",
+}
+
+// serverDateStyles are the formats docubot's formatDate filter takes for each date style
+var serverDateStyles = map[string]string{
+	DateStyleLong:  "MMMM D, YYYY",
+	DateStyleShort: "L",
+	DateStyleISO:   "YYYY-MM-DD",
+}
+
+// placeholderFilter is a filter of a placeholder with its argument, as in date:long
+type placeholderFilter struct {
+	name string
+	arg  string
+}
+
+// parseFilter splits a filter into its name and argument, filters written in docubot's syntax are read as the
+// filters they translate
+func parseFilter(s string) placeholderFilter {
+	f := placeholderFilter{name: strings.TrimSpace(s)}
+	if i := strings.Index(s, ":"); i >= 0 {
+		f.name, f.arg = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+		if unquoted, err := strconv.Unquote(f.arg); err == nil {
+			f.arg = unquoted
+		}
+	}
+	for name, server := range serverFilters {
+		if f.name == server && name != server {
+			f.name = name
+		}
+	}
+	if f.name == FilterDate {
+		for style, server := range serverDateStyles {
+			if f.arg == server {
+				f.arg = style
+			}
+		}
+	}
+	return f
+}
+
+// check returns why the filter can't be applied, nil when it can
+func (f placeholderFilter) check() error {
+	if _, ok := serverFilters[f.name]; !ok {
+		return fmt.Errorf("docubot: unknown filter %q", f.name)
+	}
+	if f.name == FilterDate {
+		if _, ok := serverDateStyles[f.dateStyle()]; !ok {
+			return fmt.Errorf("docubot: unknown date style %q", f.arg)
+		}
+	} else if f.arg != "" {
+		return fmt.Errorf("docubot: filter %q takes no argument", f.name)
+	}
+	return nil
+}
+
+func (f placeholderFilter) dateStyle() string {
+	if f.arg == "" {
+		return DateStyleLong
+	}
+	return f.arg
+}
+
+// server writes the filter in docubot's syntax
+func (f placeholderFilter) server() string {
+	name, ok := serverFilters[f.name]
+	if !ok {
+		return f.name
+	}
+	if f.name == FilterDate {
+		return name + ":" + strconv.Quote(serverDateStyles[f.dateStyle()])
+	}
+	return name
+}
+
+// TranslateFilters returns a copy of the document with the filters of its placeholders written in docubot's
+// syntax. CreateDocument, UpdateDocument, SavePartial, and preview documents translate them by themselves.
+func TranslateFilters(document *Document) *Document {
+	translated := *document
+	translated.HeaderHTML = translateFilters(document.HeaderHTML)
+	translated.BodyHTML = translateFilters(document.BodyHTML)
+	translated.FooterHTML = translateFilters(document.FooterHTML)
+	if document.Sections != nil {
+		translated.Sections = make([]DocumentSection, len(document.Sections))
+		for i, section := range document.Sections {
+			section.HTML = translateFilters(section.HTML)
+			translated.Sections[i] = section
+		}
+	}
+	return &translated
+}
+
+// translateFilters rewrites the filters of the variable placeholders in s into docubot's syntax
+func translateFilters(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, placeholder := range extractPlaceholders("", s) {
+		if placeholder.Kind != PlaceholderVariable || len(placeholder.Filters) == 0 {
+			continue
+		}
+		filters := make([]string, 0, len(placeholder.Filters))
+		for _, filter := range placeholder.Filters {
+			filters = append(filters, parseFilter(filter).server())
+		}
+		b.WriteString(s[last:placeholder.Offset])
+		b.WriteString("{{" + placeholder.Name + " " + filterSeparator + " " + strings.Join(filters, " "+filterSeparator+" ") + "}}")
+		last = placeholder.Offset + len(placeholder.Raw)
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// filter applies the filter to a variable's value, values the filter doesn't apply to are returned as they are
+func (r renderer) filter(v interface{}, f placeholderFilter) interface{} {
+	locale := LocaleEnUS
+	if r.opts.Locale != nil {
+		locale = *r.opts.Locale
+	}
+	switch f.name {
+	case FilterUpper:
+		return strings.ToUpper(r.format(v))
+	case FilterLower:
+		return strings.ToLower(r.format(v))
+	case FilterTitle:
+		return titleCase(r.format(v))
+	case FilterCapitalize:
+		s := []rune(r.format(v))
+		if len(s) > 0 {
+			s[0] = unicode.ToUpper(s[0])
+		}
+		return string(s)
+	case FilterDate:
+		t, ok := filterDate(v)
+		if !ok {
+			return v
+		}
+		switch f.dateStyle() {
+		case DateStyleShort:
+			return locale.FormatDate(t)
+		case DateStyleISO:
+			return t.Format(canonicalDateLayout)
+		}
+		return t.Format("January 2, 2006")
+	case FilterCurrency:
+		m, ok := v.(Money)
+		if !ok {
+			var err error
+			m, err = ParseMoney(filterNumber(v), locale.CurrencyCode)
+			ok = err == nil
+		}
+		if ok {
+			return locale.FormatMoney(m)
+		}
+	case FilterNumber:
+		if n, err := locale.FormatNumber(filterNumber(v)); err == nil {
+			return n
+		}
+	case FilterOrdinal:
+		if n, err := strconv.Atoi(filterNumber(v)); err == nil {
+			return ordinal(n)
+		}
+	}
+	return v
+}
+
+// format writes a value the way the renderer writes values without filters
+func (r renderer) format(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if r.opts.Locale != nil {
+		return r.opts.Locale.FormatValue(v)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// filterDate reads the dates and instants docubot stores
+func filterDate(v interface{}) (time.Time, bool) {
+	if t, ok := variableTime(v); ok {
+		return t, true
+	}
+	if s, ok := v.(string); ok {
+		t, err := time.Parse(canonicalDateLayout, s)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// filterNumber writes a number value in canonical form, other values as text
+func filterNumber(v interface{}) string {
+	switch n := v.(type) {
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(n)
+	case json.Number:
+		return n.String()
+	case Money:
+		return n.String()
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v", v))
+}
+
+// titleCase capitalizes the first letter of every word
+func titleCase(s string) string {
+	runes := []rune(s)
+	start := true
+	for i, c := range runes {
+		if start && unicode.IsLetter(c) {
+			runes[i] = unicode.ToUpper(c)
+		}
+		start = unicode.IsSpace(c) || c == '-'
+	}
+	return string(runes)
+}
+
+// ordinal writes n with its English ordinal suffix
+func ordinal(n int) string {
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	suffix := "th"
+	switch {
+	case abs%100 >= 11 && abs%100 <= 13:
+	case abs%10 == 1:
+		suffix = "st"
+	case abs%10 == 2:
+		suffix = "nd"
+	case abs%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
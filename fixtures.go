@@ -0,0 +1,256 @@
+package docubotlib
+
+import "fmt"
+
+// Fixture is a sample DocumentTree with a matching Document and a conversation that completes it,
+// for examples, benchmarks, and tests of tools built on this package
+type Fixture struct {
+	Name     string
+	Tree     *DocumentTree
+	Document *Document
+	// Answers complete an interview of Tree in order, the first starts the interview as PreviewRun expects
+	Answers []string
+	// Variables are the variables the answers leave on the thread
+	Variables map[string]interface{}
+}
+
+// Fixtures returns every sample fixture, each call returns new values that are safe to change
+func Fixtures() []Fixture {
+	return []Fixture{
+		LinearFixture(),
+		BranchingFixture(),
+		MultipleChoiceFixture(),
+		RepeatingGroupFixture(),
+	}
+}
+
+// LinearFixture is a simple will that asks the same questions in the same order every time
+func LinearFixture() Fixture {
+	return Fixture{
+		Name: "linear",
+		Tree: &DocumentTree{
+			ID:           "fixture-linear",
+			DocumentName: "Last Will and Testament",
+			EntryQuestion: fixtureQuestion("fullName", "What is your full name?", EntityTypeText,
+				fixtureQuestion("dateOfBirth", "When were you born?", EntityTypeDate),
+				fixtureQuestion("city", "Which city do you live in?", EntityTypeText),
+				fixtureQuestion("executorName", "Who should be the executor of your will?", EntityTypeText),
+				fixtureQuestion("signingDate", "On what date will you sign the will?", EntityTypeDate),
+			),
+		},
+		Document: &Document{
+			ID:             "fixture-linear-document",
+			DocumentTreeID: "fixture-linear",
+			HeaderHTML:     "<p>Last Will and Testament of {{fullName}}</p>",
+			BodyHTML: "<h1>Last Will and Testament</h1>" +
+				"<p>I, {{fullName}}, born on {{dateOfBirth}}, of {{city}}, declare this to be my last will.</p>" +
+				"<p>I appoint {{executorName}} as the executor of this will.</p>" +
+				"<p>Signed on {{signingDate}}.</p>",
+			FooterHTML: "<p>Signature: ________________</p>",
+		},
+		Answers: []string{"Hi", "Jane Doe", "1970-04-12", "Springfield", "John Doe", "2024-01-15"},
+		Variables: map[string]interface{}{
+			"fullName":     "Jane Doe",
+			"dateOfBirth":  "1970-04-12",
+			"city":         "Springfield",
+			"executorName": "John Doe",
+			"signingDate":  "2024-01-15",
+		},
+	}
+}
+
+// BranchingFixture is a residential lease whose questions and sections depend on earlier answers, several levels deep
+func BranchingFixture() Fixture {
+	tenantIsCompany := fixtureQuestion("tenantIsCompany", "Is the tenant a company? (yes or no)", EntityTypeText,
+		fixtureQuestion("companyName", "What is the company's registered name?", EntityTypeText).when("tenantIsCompany", ComparatorEqual, "yes"),
+		fixtureQuestion("companyNumber", "What is the company's registration number?", EntityTypeText).when("tenantIsCompany", ComparatorEqual, "yes"),
+		fixtureQuestion("tenantName", "What is the tenant's full name?", EntityTypeText).when("tenantIsCompany", ComparatorEqual, "no"),
+	)
+	termMonths := fixtureQuestion("termMonths", "How many months is the lease for?", EntityTypeNumber,
+		fixtureQuestion("renewalNoticeDays", "How many days notice are needed to renew?", EntityTypeNumber).when("termMonths", ComparatorGreaterThan, "12"),
+	)
+	petDeposit := fixtureQuestion("petDeposit", "What is the pet deposit?", EntityTypeNumber,
+		fixtureQuestion("petDepositRefundable", "Is the pet deposit refundable? (yes or no)", EntityTypeText).when("petDeposit", ComparatorGreaterThan, "0"),
+	).when("hasPets", ComparatorEqual, "yes")
+	hasPets := fixtureQuestion("hasPets", "Will the tenant keep pets? (yes or no)", EntityTypeText,
+		fixtureQuestion("petDescription", "Which pets will the tenant keep?", EntityTypeText).when("hasPets", ComparatorEqual, "yes"),
+		petDeposit,
+	)
+	return Fixture{
+		Name: "branching",
+		Tree: &DocumentTree{
+			ID:           "fixture-branching",
+			DocumentName: "Residential Lease",
+			EntryQuestion: fixtureQuestion("landlordName", "What is the landlord's full name?", EntityTypeText,
+				tenantIsCompany,
+				termMonths,
+				hasPets,
+				fixtureQuestion("monthlyRent", "What is the monthly rent?", EntityTypeNumber),
+			),
+		},
+		Document: &Document{
+			ID:             "fixture-branching-document",
+			DocumentTreeID: "fixture-branching",
+			BodyHTML: "<h1>Residential Lease</h1>" +
+				"<p>This lease is between {{landlordName}}, the landlord, and the tenant described below.</p>" +
+				"{{section:companyTenant}}{{section:individualTenant}}" +
+				"<p>The lease runs for {{termMonths}} months at a monthly rent of {{monthlyRent}}.</p>" +
+				"{{section:renewal}}{{section:pets}}",
+			Sections: []DocumentSection{
+				fixtureSection("companyTenant", "<p>The tenant is {{companyName}}, registration number {{companyNumber}}.</p>",
+					fixtureCondition("tenantIsCompany", ComparatorEqual, "yes")),
+				fixtureSection("individualTenant", "<p>The tenant is {{tenantName}}.</p>",
+					fixtureCondition("tenantIsCompany", ComparatorEqual, "no")),
+				fixtureSection("renewal", "<p>The lease may be renewed with {{renewalNoticeDays}} days notice.</p>",
+					fixtureCondition("termMonths", ComparatorGreaterThan, "12")),
+				fixtureSection("pets", "<p>The tenant may keep {{petDescription}} for a deposit of {{petDeposit}}.</p>{{section:petRefund}}",
+					fixtureCondition("hasPets", ComparatorEqual, "yes")),
+				fixtureSection("petRefund", "<p>The pet deposit is refundable: {{petDepositRefundable}}.</p>",
+					fixtureCondition("petDeposit", ComparatorGreaterThan, "0")),
+			},
+		},
+		Answers: []string{"Hello", "Alex Landlord", "yes", "Acme Holdings LLC", "12345678", "24", "60", "no", "1850"},
+		Variables: map[string]interface{}{
+			"landlordName":      "Alex Landlord",
+			"tenantIsCompany":   "yes",
+			"companyName":       "Acme Holdings LLC",
+			"companyNumber":     "12345678",
+			"termMonths":        float64(24),
+			"renewalNoticeDays": float64(60),
+			"hasPets":           "no",
+			"monthlyRent":       float64(1850),
+		},
+	}
+}
+
+// MultipleChoiceFixture is a non-disclosure agreement asked mostly with multiple choice questions
+func MultipleChoiceFixture() Fixture {
+	ndaType := fixtureQuestion("ndaType", "Is the agreement mutual or one way?", EntityTypeMultipleChoice,
+		fixtureQuestion("disclosingParty", "Which party discloses information?", EntityTypeText).when("ndaType", ComparatorEqual, "oneWay"),
+		fixtureQuestion("partyA", "What is the first party's name?", EntityTypeText),
+		fixtureQuestion("partyB", "What is the second party's name?", EntityTypeText),
+		fixtureQuestion("jurisdiction", "Which state's law governs the agreement?", EntityTypeMultipleChoice).
+			choices("ny", "New York", "ca", "California", "de", "Delaware"),
+		fixtureQuestion("termYears", "How many years does confidentiality last?", EntityTypeMultipleChoice).
+			choices("1", "One year", "2", "Two years", "5", "Five years"),
+	).choices("mutual", "Mutual", "oneWay", "One way")
+	return Fixture{
+		Name: "multipleChoice",
+		Tree: &DocumentTree{
+			ID:            "fixture-multiple-choice",
+			DocumentName:  "Non-Disclosure Agreement",
+			EntryQuestion: ndaType,
+		},
+		Document: &Document{
+			ID:             "fixture-multiple-choice-document",
+			DocumentTreeID: "fixture-multiple-choice",
+			BodyHTML: "<h1>Non-Disclosure Agreement</h1>" +
+				"<p>This agreement is between {{partyA}} and {{partyB}}.</p>" +
+				"{{section:mutual}}{{section:oneWay}}" +
+				"<p>Confidentiality lasts {{termYears}} years and the agreement is governed by the law of {{jurisdiction}}.</p>",
+			Sections: []DocumentSection{
+				fixtureSection("mutual", "<p>Each party will keep the other's confidential information secret.</p>",
+					fixtureCondition("ndaType", ComparatorEqual, "mutual")),
+				fixtureSection("oneWay", "<p>The receiving party will keep the confidential information of {{disclosingParty}} secret.</p>",
+					fixtureCondition("ndaType", ComparatorEqual, "oneWay")),
+			},
+		},
+		Answers: []string{"Hi", "mutual", "Initech Inc.", "Globex Corp.", "de", "2"},
+		Variables: map[string]interface{}{
+			"ndaType":      "mutual",
+			"partyA":       "Initech Inc.",
+			"partyB":       "Globex Corp.",
+			"jurisdiction": "de",
+			"termYears":    "2",
+		},
+	}
+}
+
+// repeatingGroupSize is how many children RepeatingGroupFixture can ask about
+const repeatingGroupSize int = 3
+
+// RepeatingGroupFixture is a guardianship nomination that asks the same questions for each child,
+// the group is repeated with numbered variables that are asked when the child count reaches them
+func RepeatingGroupFixture() Fixture {
+	entry := fixtureQuestion("parentName", "What is your full name?", EntityTypeText,
+		fixtureQuestion("childCount", fmt.Sprintf("How many children do you have? (up to %v)", repeatingGroupSize), EntityTypeNumber),
+	)
+	document := &Document{
+		ID:             "fixture-repeating-group-document",
+		DocumentTreeID: "fixture-repeating-group",
+	}
+	body := "<h1>Nomination of Guardian</h1><p>I, {{parentName}}, nominate {{guardianName}} as guardian of my children:</p><ul>"
+	for i := 1; i <= repeatingGroupSize; i++ {
+		name := fmt.Sprintf("child%vName", i)
+		dateOfBirth := fmt.Sprintf("child%vDateOfBirth", i)
+		count := fmt.Sprint(i)
+		entry.ChildQuestions = append(entry.ChildQuestions,
+			*fixtureQuestion(name, fmt.Sprintf("What is child %v's full name?", i), EntityTypeText).when("childCount", ComparatorGreaterThanOrEqual, count),
+			*fixtureQuestion(dateOfBirth, fmt.Sprintf("When was child %v born?", i), EntityTypeDate).when("childCount", ComparatorGreaterThanOrEqual, count),
+		)
+		section := fmt.Sprintf("child%v", i)
+		body += SectionMarker(section)
+		document.Sections = append(document.Sections, fixtureSection(section,
+			"<li>"+Variable(name)+", born on "+Variable(dateOfBirth)+"</li>",
+			fixtureCondition("childCount", ComparatorGreaterThanOrEqual, count)))
+	}
+	entry.ChildQuestions = append(entry.ChildQuestions, *fixtureQuestion("guardianName", "Who should be the guardian of your children?", EntityTypeText))
+	document.BodyHTML = body + "</ul>"
+	return Fixture{
+		Name: "repeatingGroup",
+		Tree: &DocumentTree{
+			ID:            "fixture-repeating-group",
+			DocumentName:  "Nomination of Guardian",
+			EntryQuestion: entry,
+		},
+		Document: document,
+		Answers:  []string{"Hello", "Sam Parent", "2", "Ava Parent", "2015-06-01", "Ben Parent", "2018-09-30", "Pat Guardian"},
+		Variables: map[string]interface{}{
+			"parentName":        "Sam Parent",
+			"childCount":        float64(2),
+			"child1Name":        "Ava Parent",
+			"child1DateOfBirth": "2015-06-01",
+			"child2Name":        "Ben Parent",
+			"child2DateOfBirth": "2018-09-30",
+			"guardianName":      "Pat Guardian",
+		},
+	}
+}
+
+func fixtureQuestion(variable string, question string, entityType string, children ...*QuestionNode) *QuestionNode {
+	node := &QuestionNode{
+		VariableName:    variable,
+		Question:        question,
+		LogicalOperator: LogicalOperatorAnd,
+		Conditions:      []QuestionCondition{},
+		EntityType:      entityType,
+		ChildQuestions:  []QuestionNode{},
+	}
+	for _, child := range children {
+		node.ChildQuestions = append(node.ChildQuestions, *child)
+	}
+	return node
+}
+
+// when adds a condition the question is only asked when it holds
+func (n *QuestionNode) when(variable string, comparator string, value string) *QuestionNode {
+	n.Conditions = append(n.Conditions, fixtureCondition(variable, comparator, value))
+	return n
+}
+
+// choices sets the choices of a multiple choice question from key, text pairs
+func (n *QuestionNode) choices(pairs ...string) *QuestionNode {
+	n.MetaData = &QuestionNodeMetaData{Choices: map[string]string{}}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		n.MetaData.Choices[pairs[i]] = pairs[i+1]
+	}
+	return n
+}
+
+func fixtureCondition(variable string, comparator string, value string) QuestionCondition {
+	return QuestionCondition{VariableName: variable, Comparator: comparator, Value: value}
+}
+
+func fixtureSection(name string, html string, conditions ...QuestionCondition) DocumentSection {
+	return DocumentSection{Name: name, LogicalOperator: LogicalOperatorAnd, Conditions: conditions, HTML: html}
+}
@@ -0,0 +1,175 @@
+package docubotlib
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultFuzzRuns is how many interviews FuzzTree simulates when FuzzOptions.Runs is zero
+const defaultFuzzRuns int = 100
+
+// FuzzOptions configures FuzzTree
+type FuzzOptions struct {
+	// Runs is how many random interviews to simulate, 100 when zero
+	Runs int
+	// Seed seeds the random answers so a failure can be reproduced, seeded from the time when zero
+	Seed int64
+}
+
+// FuzzFailure is a simulated interview that broke an invariant
+type FuzzFailure struct {
+	// Seed reproduces the interview's answers as FuzzOptions.Seed with one run
+	Seed int64
+	// Asked are the variables the interview asked for, in order
+	Asked     []string
+	Variables map[string]interface{}
+	Problem   string
+}
+
+// FuzzReport is the outcome of FuzzTree
+type FuzzReport struct {
+	Runs     int
+	Failures []FuzzFailure
+	// Coverage is the branch coverage of the tree by the random interviews
+	Coverage *TreeCoverage
+}
+
+// Err returns an error describing the first failure, nil if every interview kept to the invariants
+func (r *FuzzReport) Err() error {
+	if len(r.Failures) == 0 {
+		return nil
+	}
+	f := r.Failures[0]
+	return fmt.Errorf("docubot: %v of %v fuzzed interviews failed, first (seed %v): %v", len(r.Failures), r.Runs, f.Seed, f.Problem)
+}
+
+// FuzzTree simulates interviews of the tree with random answers, biased towards the values its conditions
+// compare against, and checks that every interview ends, never asks for a variable twice, only asks a question
+// after the variables its conditions compare have been answered, and leaves no answered question that its final
+// answers would skip. Call it from a test of a tree before releasing it, failing the test on Err.
+func FuzzTree(tree *DocumentTree, opts FuzzOptions) *FuzzReport {
+	runs := opts.Runs
+	if runs < 1 {
+		runs = defaultFuzzRuns
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	nodes := 0
+	tree.Walk(func(*QuestionNode) { nodes++ })
+	candidates := conditionValues(tree)
+	report := &FuzzReport{Runs: runs}
+	scenarios := make([]Scenario, runs)
+	for run := 0; run < runs; run++ {
+		runSeed := seed + int64(run)
+		answers := fuzzAnswers(tree, candidates, rand.New(rand.NewSource(runSeed)))
+		scenarios[run] = Scenario{Name: strconv.FormatInt(runSeed, 10), Answers: answers}
+		simulation := NewSimulation(tree, nil)
+		if err := fuzzInterview(simulation, answers, nodes); err != nil {
+			report.Failures = append(report.Failures, FuzzFailure{
+				Seed:      runSeed,
+				Asked:     simulation.Asked(),
+				Variables: simulation.Variables(),
+				Problem:   err.Error(),
+			})
+		}
+	}
+	report.Coverage = SimulateScenarios(tree, scenarios)
+	return report
+}
+
+// fuzzInterview runs a simulated interview to its end, checking the invariants as it goes
+func fuzzInterview(simulation *Simulation, answers map[string]interface{}, nodes int) error {
+	asked := map[string]bool{}
+	var questions []*QuestionNode
+	for question := simulation.Next(); question != nil; question = simulation.Next() {
+		if len(questions) >= nodes {
+			return fmt.Errorf("interview didn't end after %v questions", len(questions))
+		}
+		if asked[question.VariableName] {
+			return fmt.Errorf("%v was asked twice", question.VariableName)
+		}
+		for _, condition := range question.Conditions {
+			if !asked[condition.VariableName] {
+				return fmt.Errorf("%v was asked before %v, which its conditions compare", question.VariableName, condition.VariableName)
+			}
+		}
+		asked[question.VariableName] = true
+		questions = append(questions, question)
+		if err := simulation.Answer(answers[question.VariableName]); err != nil {
+			return err
+		}
+	}
+	variables := simulation.Variables()
+	for _, question := range questions {
+		if !EvaluateConditions(question.LogicalOperator, question.Conditions, variables) {
+			return fmt.Errorf("%v was answered but the final answers skip it", question.VariableName)
+		}
+	}
+	if len(variables) != len(questions) {
+		return errors.New("the interview's variables don't match the questions it asked")
+	}
+	return nil
+}
+
+// conditionValues returns the values the tree's conditions compare each variable against
+func conditionValues(tree *DocumentTree) map[string][]string {
+	values := map[string][]string{}
+	tree.Walk(func(node *QuestionNode) {
+		for _, condition := range node.Conditions {
+			if !containsString(values[condition.VariableName], condition.Value) {
+				values[condition.VariableName] = append(values[condition.VariableName], condition.Value)
+			}
+		}
+	})
+	return values
+}
+
+// fuzzAnswers returns a random answer for each of the tree's questions, half the time one that lands on
+// or beside a value a condition compares the question's variable against
+func fuzzAnswers(tree *DocumentTree, candidates map[string][]string, r *rand.Rand) map[string]interface{} {
+	answers := map[string]interface{}{}
+	tree.Walk(func(node *QuestionNode) {
+		if node.VariableName == "" {
+			return
+		}
+		if _, ok := answers[node.VariableName]; ok {
+			return
+		}
+		answers[node.VariableName] = fuzzAnswer(node, candidates[node.VariableName], r)
+	})
+	return answers
+}
+
+func fuzzAnswer(node *QuestionNode, candidates []string, r *rand.Rand) string {
+	if strings.EqualFold(node.EntityType, EntityTypeMultipleChoice) && node.MetaData != nil && len(node.MetaData.Choices) > 0 {
+		var keys []string
+		for key := range node.MetaData.Choices {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys[r.Intn(len(keys))]
+	}
+	if len(candidates) > 0 && r.Intn(2) == 0 {
+		candidate := candidates[r.Intn(len(candidates))]
+		if n, err := strconv.ParseFloat(candidate, 64); err == nil {
+			return strconv.FormatFloat(n+float64(r.Intn(3)-1), 'f', -1, 64)
+		}
+		return candidate
+	}
+	switch strings.ToLower(node.EntityType) {
+	case EntityTypeNumber:
+		return strconv.Itoa(r.Intn(100))
+	case EntityTypeCurrency:
+		return Money{Cents: int64(r.Intn(1000000))}.String()
+	case EntityTypeDate:
+		return DateAnswer(time.Date(1950+r.Intn(80), time.Month(1+r.Intn(12)), 1+r.Intn(28), 0, 0, 0, 0, time.UTC))
+	}
+	return fmt.Sprintf("answer %v", r.Intn(1000))
+}
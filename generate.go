@@ -0,0 +1,102 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// GenerateDocumentOptions selects what GenerateDocument generates, set exactly one of the IDs
+type GenerateDocumentOptions struct {
+	// DocumentTreeID generates every document of the tree
+	DocumentTreeID string
+	// DocumentID generates a single Document template
+	DocumentID string
+	// IdempotencyKey lets docubot discard retried duplicates, one is generated when empty and retries are on
+	IdempotencyKey string
+}
+
+// GeneratedDocumentListResponse is the response received from generating documents from variables
+type GeneratedDocumentListResponse struct {
+	Data GeneratedDocumentListData `json:"data"`
+	Meta map[string]interface{}    `json:"meta"`
+}
+
+// GeneratedDocumentListData is the response data received from generating documents from variables
+type GeneratedDocumentListData struct {
+	// Documents are stored by docubot, they belong to no thread and are downloaded with GetGeneratedDocument
+	Documents []ThreadDocument `json:"documents"`
+}
+
+// GenerateDocument generates and stores documents from a complete variables payload without an interview,
+// for server to server flows where the answers come from elsewhere, such as our own forms. Variables the client's
+// VariableEncryption designates are encrypted to no thread, the documents show their ciphertext.
+func (c *Client) GenerateDocument(ctx context.Context, variables map[string]interface{}, opts GenerateDocumentOptions, callOpts ...CallOption) (*GeneratedDocumentListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	if (opts.DocumentTreeID == "") == (opts.DocumentID == "") {
+		return nil, errors.New("docubot: set exactly one of DocumentTreeID and DocumentID")
+	}
+	variables, err := c.VariableEncryption.encryptVariables(ctx, "", variables)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%v/api/v1/generated", c.DocubotAPIURLBase)
+	body := map[string]interface{}{"variables": variables}
+	if opts.DocumentTreeID != "" {
+		body["docTreeId"] = opts.DocumentTreeID
+	}
+	if opts.DocumentID != "" {
+		body["documentId"] = opts.DocumentID
+	}
+	req, err := c.newRequest(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	key := opts.IdempotencyKey
+	if key == "" && c.retries(ctx) && req.Header.Get("Idempotency-Key") == "" {
+		// retried generations need a key so docubot doesn't store the documents twice
+		if key, err = newIdempotencyKey(); err != nil {
+			return nil, err
+		}
+	}
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	var response GeneratedDocumentListResponse
+	err = c.doJSON(req, variables, &response)
+	return &response, err
+}
+
+// GetGeneratedDocument downloads a document stored by GenerateDocument, the caller must close it
+func (c *Client) GetGeneratedDocument(ctx context.Context, id string, callOpts ...CallOption) (io.ReadCloser, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/generated/%v/download", c.documentsURLBase(), id)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.doStream(req, nil)
+}
+
+// GetGeneratedDocumentURL gets a url for a document stored by GenerateDocument
+func (c *Client) GetGeneratedDocumentURL(ctx context.Context, id string, exp time.Duration, callOpts ...CallOption) (*DocumentURLResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	seconds, err := urlDurationSeconds(exp)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%v/api/v1/generated/%v/url?duration=%v", c.documentsURLBase(), id, seconds)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	requested := time.Now()
+	var response DocumentURLResponse
+	if err := c.doJSON(req, nil, &response); err != nil {
+		return nil, err
+	}
+	response.setExpiry(requested, time.Duration(seconds)*time.Second)
+	return &response, nil
+}
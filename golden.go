@@ -0,0 +1,134 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// goldenDiffContext is how many unchanged lines a GoldenMismatchError shows around each change
+const goldenDiffContext int = 3
+
+// htmlTagPattern matches the tags of document html, for putting each on its own line
+var htmlTagPattern = regexp.MustCompile(`<[^<>]*>`)
+
+// GoldenOptions configures CompareGoldenHTML and Client.CompareGoldenPDF
+type GoldenOptions struct {
+	// Render configures the local render of CompareGoldenHTML
+	Render RenderOptions
+	// Update writes the golden file from the document instead of comparing them, such as when a test runs with
+	// an -update flag after an intended template change
+	Update bool
+}
+
+// GoldenMismatchError is returned when a document doesn't match its golden file, its message is a readable diff
+type GoldenMismatchError struct {
+	Path string
+	// Lines is a line diff of the golden file against the document
+	Lines []DiffLine
+}
+
+func (e *GoldenMismatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "docubot: document doesn't match golden file %v (- golden, + document):\n", e.Path)
+	last := -1
+	for i, line := range e.Lines {
+		if line.Op == DiffEqual && !nearChange(e.Lines, i) {
+			continue
+		}
+		if last >= 0 && i > last+1 {
+			b.WriteString("  ...\n")
+		}
+		b.WriteString(line.Op + " " + line.Text + "\n")
+		last = i
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// nearChange reports whether a changed line is within goldenDiffContext lines of lines[i]
+func nearChange(lines []DiffLine, i int) bool {
+	for j := i - goldenDiffContext; j <= i+goldenDiffContext; j++ {
+		if j >= 0 && j < len(lines) && lines[j].Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareGoldenHTML renders the document locally with the variables and compares its html with the golden file at
+// path, so a template regression fails a test rather than reaching clients. The html is normalized to a tag or
+// text per line with whitespace collapsed, formatting changes that don't change the document aren't reported.
+// A mismatch is a *GoldenMismatchError.
+func CompareGoldenHTML(path string, document *Document, variables map[string]interface{}, opts GoldenOptions) error {
+	rendered, err := RenderDocumentWithOptions(document, variables, opts.Render)
+	if err != nil {
+		return err
+	}
+	var lines []string
+	for _, part := range []struct{ name, html string }{
+		{"header", rendered.HeaderHTML},
+		{"body", rendered.BodyHTML},
+		{"footer", rendered.FooterHTML},
+	} {
+		lines = append(lines, "== "+part.name+" ==")
+		lines = append(lines, normalizeGoldenHTML(part.html)...)
+	}
+	return compareGolden(path, lines, opts.Update)
+}
+
+// CompareGoldenPDF previews the document with docubot for the variables and compares the text of the pdf with the
+// golden file at path, line by line. Only text written with simple fonts is compared, as with FindPDFAnchors.
+// A mismatch is a *GoldenMismatchError.
+func (c *Client) CompareGoldenPDF(ctx context.Context, path string, document *Document, variables map[string]interface{}, opts GoldenOptions, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	pdf, err := readBody(c.getPreviewDoc(ctx, variables, document))
+	if err != nil {
+		return err
+	}
+	lines, err := pdfTextLines(pdf)
+	if err != nil {
+		return err
+	}
+	return compareGolden(path, lines, opts.Update)
+}
+
+// normalizeGoldenHTML splits html into a line per tag or text, with whitespace collapsed and blank lines dropped
+func normalizeGoldenHTML(s string) []string {
+	s = htmlTagPattern.ReplaceAllString(s, "\n$0\n")
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// compareGolden diffs lines against the golden file at path, or writes them to it when update is set
+func compareGolden(path string, lines []string, update bool) error {
+	if update {
+		return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("docubot: golden file %v doesn't exist, create it with GoldenOptions.Update", path)
+	}
+	if err != nil {
+		return err
+	}
+	var golden []string
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			golden = append(golden, line)
+		}
+	}
+	diff := diffLines(golden, lines)
+	for _, line := range diff {
+		if line.Op != DiffEqual {
+			return &GoldenMismatchError{Path: path, Lines: diff}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Group runs tasks concurrently with a cap on how many run at once. The first task to fail cancels the
+// context of the others, and Wait returns every failure. Tasks not yet started when the group's context
+// is done are skipped.
+type Group struct {
+	client *Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	slots  chan struct{}
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	tasks  int
+	errs   []error
+}
+
+// WithClientGroup returns a Group running at most limit tasks at once, a small default when limit is zero,
+// and the context its tasks should use. The context carries ctx's call options and is canceled once a task fails.
+func (c *Client) WithClientGroup(ctx context.Context, limit int, callOpts ...CallOption) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(withCallOptions(ctx, callOpts))
+	if limit < 1 {
+		limit = defaultConcurrency
+	}
+	return &Group{client: c, ctx: ctx, cancel: cancel, slots: make(chan struct{}, limit)}, ctx
+}
+
+// Go runs task once a slot is free, blocking until then. Once the client is shut down task isn't run, Wait
+// reports it failed with ErrClosed.
+func (g *Group) Go(task func(ctx context.Context) error) {
+	select {
+	case g.slots <- struct{}{}:
+	case <-g.ctx.Done():
+		return
+	}
+	g.mu.Lock()
+	g.tasks++
+	g.mu.Unlock()
+	g.wg.Add(1)
+	started := g.client.background(g.ctx, false, func(ctx context.Context) {
+		defer func() {
+			<-g.slots
+			g.wg.Done()
+		}()
+		err := g.client.recoverCall("group task", func() error {
+			return task(ctx)
+		})
+		if err != nil {
+			g.fail(err)
+		}
+	})
+	if !started {
+		<-g.slots
+		g.wg.Done()
+		g.fail(ErrClosed)
+	}
+}
+
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) > 0 && errors.Is(err, context.Canceled) {
+		// canceled by an earlier failure
+		return
+	}
+	g.errs = append(g.errs, err)
+	g.cancel()
+}
+
+// Wait waits for every started task and returns a *GroupError of their failures, nil if none failed
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return &GroupError{Errors: g.errs, Tasks: g.tasks}
+}
+
+// GroupError is the error returned by Group.Wait when tasks failed
+type GroupError struct {
+	// Errors are the failures in the order they happened, the first one canceled the other tasks
+	Errors []error
+	// Tasks is the number of tasks that were started
+	Tasks int
+}
+
+func (e *GroupError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("docubot: %v of %v tasks failed, first: %v", len(e.Errors), e.Tasks, e.Errors[0])
+}
+
+// Unwrap returns the first failure, so errors.Is and errors.As match it
+func (e *GroupError) Unwrap() error {
+	return e.Errors[0]
+}
+
+// APIErrors returns the failures that were docubot API errors
+func (e *GroupError) APIErrors() []*APIError {
+	var apiErrors []*APIError
+	for _, err := range e.Errors {
+		var apiError *APIError
+		if errors.As(err, &apiError) {
+			apiErrors = append(apiErrors, apiError)
+		}
+	}
+	return apiErrors
+}
@@ -0,0 +1,130 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// QuestionGroup is a titled section of a tree's questions, such as "Employment history". Questions join one with
+// QuestionNodeMetaData.Group.
+type QuestionGroup struct {
+	// Name identifies the group in question metadata, it is unique in the tree
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+// GroupPosition is where a question's group falls among the tree's groups
+type GroupPosition struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	// Index is the group's place in DocumentTree.Groups starting at 1, Count is how many groups the tree has
+	Index int `json:"index"`
+	Count int `json:"count"`
+}
+
+// GroupProgress is how far a thread got through one of its tree's groups
+type GroupProgress struct {
+	QuestionGroup
+	// Answered and Total count the group's questions the thread answered and will ask, questions skipped by
+	// their conditions aren't counted
+	Answered int  `json:"answered"`
+	Total    int  `json:"total"`
+	Complete bool `json:"complete"`
+}
+
+// ThreadProgress is how far a thread got through its tree
+type ThreadProgress struct {
+	Answered int `json:"answered"`
+	Total    int `json:"total"`
+	// Group is the group of the question the thread is waiting on, nil when it isn't in one or the thread is complete
+	Group *GroupPosition `json:"group,omitempty"`
+	// Groups are the tree's groups in order
+	Groups []GroupProgress `json:"groups,omitempty"`
+}
+
+// ThreadProgressResponse is the response received from getting a thread's progress
+type ThreadProgressResponse struct {
+	Data ThreadProgressData     `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// ThreadProgressData is the response data received from getting a thread's progress
+type ThreadProgressData struct {
+	Progress ThreadProgress `json:"progress"`
+}
+
+// String labels the position for an interview's header, such as "Part 2 of 5: Employment history"
+func (p GroupPosition) String() string {
+	if p.Title == "" {
+		return fmt.Sprintf("Part %v of %v", p.Index, p.Count)
+	}
+	return fmt.Sprintf("Part %v of %v: %v", p.Index, p.Count, p.Title)
+}
+
+// GetThreadProgress gets how far the provided user's thread got through its tree and its groups
+func (c *Client) GetThreadProgress(ctx context.Context, thread string, user string, callOpts ...CallOption) (*ThreadProgressResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/progress?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadProgressResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// Group returns the tree's group with the name, nil if there isn't one
+func (t *DocumentTree) Group(name string) *QuestionGroup {
+	if t == nil {
+		return nil
+	}
+	for i := range t.Groups {
+		if t.Groups[i].Name == name {
+			return &t.Groups[i]
+		}
+	}
+	return nil
+}
+
+// GroupOf returns the position of the group of the first question asking for the variable, nil when the question
+// isn't in a group or there isn't one. A question without a group of its own is in its parent's.
+func (t *DocumentTree) GroupOf(variable string) *GroupPosition {
+	if t == nil || t.EntryQuestion == nil {
+		return nil
+	}
+	name, ok := t.EntryQuestion.groupOf(variable, "")
+	if !ok || name == "" {
+		return nil
+	}
+	for i, group := range t.Groups {
+		if group.Name == name {
+			return &GroupPosition{Name: group.Name, Title: group.Title, Index: i + 1, Count: len(t.Groups)}
+		}
+	}
+	return nil
+}
+
+// groupOf finds the group of the question asking for the variable below n, inherited is the group of n's parent
+func (n *QuestionNode) groupOf(variable string, inherited string) (string, bool) {
+	if n.MetaData != nil && n.MetaData.Group != "" {
+		inherited = n.MetaData.Group
+	}
+	if n.VariableName == variable {
+		return inherited, true
+	}
+	for i := range n.ChildQuestions {
+		if group, ok := n.ChildQuestions[i].groupOf(variable, inherited); ok {
+			return group, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,67 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ThreadHandoff is a data model for a thread handed off from the bot to a human agent
+type ThreadHandoff struct {
+	// Agent is the id of the agent the thread was handed to
+	Agent  string    `json:"agent,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Paused reports whether the bot is paused on the thread for a human agent
+func (t *Thread) Paused() bool {
+	return t.Handoff != nil
+}
+
+// PauseThreadOptions describes a handoff to a human agent
+type PauseThreadOptions struct {
+	// Agent is the id of the agent taking over, they send messages with SendMessageAs and SenderAgent
+	Agent  string
+	Reason string
+}
+
+// PauseThread pauses the bot on the provided user's thread so a human agent can converse.
+// The user's messages are recorded without bot replies until ResumeThread is called.
+func (c *Client) PauseThread(ctx context.Context, thread string, user string, opts PauseThreadOptions, callOpts ...CallOption) (*ThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	body := map[string]interface{}{}
+	if opts.Agent != "" {
+		body["agent"] = opts.Agent
+	}
+	if opts.Reason != "" {
+		body["reason"] = opts.Reason
+	}
+	return c.handoffThread(ctx, thread, user, "pause", body)
+}
+
+// ResumeThread hands the provided user's thread back to the bot, which continues the interview where it was paused
+func (c *Client) ResumeThread(ctx context.Context, thread string, user string, callOpts ...CallOption) (*ThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.handoffThread(ctx, thread, user, "resume", map[string]interface{}{})
+}
+
+func (c *Client) handoffThread(ctx context.Context, thread string, user string, action string, body map[string]interface{}) (*ThreadResponse, error) {
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/%v?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		action,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
@@ -0,0 +1,117 @@
+package docubotlib
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithHedging sends a second copy of the request if it hasn't been answered after delay and uses whichever
+// response arrives first, trading an extra request for lower tail latency on reads such as GetDocubotVariables.
+// Only GET requests without a body are hedged, other requests ignore it. A zero delay turns off the client's HedgeDelay.
+func WithHedging(delay time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.hedgeDelay = delay
+	}
+}
+
+// hedgeable reports whether sending the request twice is safe
+func hedgeable(req *http.Request) bool {
+	return req.Method == http.MethodGet && (req.Body == nil || req.Body == http.NoBody)
+}
+
+type hedgeResult struct {
+	attempt int
+	resp    *http.Response
+	err     error
+}
+
+// sendHedged sends the request, and a copy of it once delay passes without a response, returning the first
+// response that doesn't need a retry and canceling the other request. When both fail the last failure is returned.
+func (c *Client) sendHedged(req *http.Request, delay time.Duration) (*http.Response, error) {
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+	launch := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		attempt := len(cancels)
+		cancels = append(cancels, cancel)
+		clone := req.Clone(ctx)
+		go func() {
+			result := hedgeResult{attempt: attempt}
+			result.err = c.recoverCall("hedged request", func() error {
+				var err error
+				result.resp, err = c.sendWithRetries(clone)
+				return err
+			})
+			results <- result
+		}()
+	}
+	launch()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	var failed []hedgeResult
+	for len(failed) < len(cancels) {
+		select {
+		case <-timer.C:
+			if len(cancels) == 1 {
+				launch()
+			}
+		case result := <-results:
+			if result.err == nil && !retryableResponse(result.resp, nil) {
+				for i, cancel := range cancels {
+					if i != result.attempt {
+						cancel()
+					}
+				}
+				go discardHedges(results, len(cancels)-len(failed)-1)
+				for _, f := range failed {
+					if f.resp != nil {
+						f.resp.Body.Close()
+					}
+				}
+				result.resp.Body = &cancelOnClose{ReadCloser: result.resp.Body, cancel: cancels[result.attempt]}
+				return result.resp, nil
+			}
+			failed = append(failed, result)
+			if len(cancels) == 1 {
+				// the first request failed before the delay, hedge right away
+				launch()
+			}
+		}
+	}
+	last := failed[len(failed)-1]
+	for _, f := range failed[:len(failed)-1] {
+		if f.resp != nil {
+			f.resp.Body.Close()
+		}
+		cancels[f.attempt]()
+	}
+	if last.err != nil {
+		cancels[last.attempt]()
+		return nil, last.err
+	}
+	last.resp.Body = &cancelOnClose{ReadCloser: last.resp.Body, cancel: cancels[last.attempt]}
+	return last.resp, nil
+}
+
+// discardHedges releases the responses of n canceled requests as they finish
+func discardHedges(results chan hedgeResult, n int) {
+	for ; n > 0; n-- {
+		if result := <-results; result.resp != nil {
+			result.resp.Body.Close()
+		}
+	}
+}
+
+// cancelOnClose cancels a hedged or timed request's context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
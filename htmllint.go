@@ -0,0 +1,177 @@
+package docubotlib
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxImageWidth is the widest an image can be, in css pixels, before it overflows a letter or A4 page
+const maxImageWidth float64 = 794
+
+// maxEmbeddedImageSize is the largest an image embedded as a data: url should be, in bytes of its url
+const maxEmbeddedImageSize int = 1 << 20
+
+// optionalCloseElements may be left open, the next sibling or their parent's end closes them
+var optionalCloseElements = map[string]bool{
+	"p": true, "li": true, "dt": true, "dd": true, "tr": true, "td": true, "th": true,
+	"thead": true, "tbody": true, "tfoot": true, "option": true, "colgroup": true,
+}
+
+var (
+	absoluteFontSizePattern = regexp.MustCompile(`(?i)font(-size)?\s*:[^;]*?\b\d*\.?\d+\s*(px|pt|pc|cm|mm|in)\b`)
+	cssWidthPattern         = regexp.MustCompile(`(?i)(?:^|[;\s])width\s*:\s*(\d*\.?\d+)\s*px`)
+)
+
+// LintRenderedDocument checks the html of a rendered document for problems that garble the generated pdf
+func LintRenderedDocument(rendered *RenderedDocument) []LintIssue {
+	var issues []LintIssue
+	issues = append(issues, LintRenderedHTML("headerHtml", rendered.HeaderHTML)...)
+	issues = append(issues, LintRenderedHTML("bodyHtml", rendered.BodyHTML)...)
+	issues = append(issues, LintRenderedHTML("footerHtml", rendered.FooterHTML)...)
+	return issues
+}
+
+// lintRendered renders the document locally and lints its html, a document that can't be rendered is an issue itself
+func lintRendered(document *Document, variables map[string]interface{}, opts RenderOptions) []LintIssue {
+	rendered, err := RenderDocumentWithOptions(document, variables, opts)
+	if err != nil {
+		return []LintIssue{{Severity: LintError, Location: "document", Message: err.Error()}}
+	}
+	return LintRenderedDocument(rendered)
+}
+
+// LintRenderedHTML checks html for unclosed and mismatched tags, images without alt text,
+// absolute font sizes, and images too large for the page, location names the html in the issues
+func LintRenderedHTML(location string, s string) []LintIssue {
+	l := linter{}
+	var open []string
+	for i := 0; i < len(s); {
+		lt := strings.IndexByte(s[i:], '<')
+		if lt < 0 {
+			break
+		}
+		i += lt
+		if strings.HasPrefix(s[i:], "<!--") {
+			end := strings.Index(s[i+4:], "-->")
+			if end < 0 {
+				l.add(LintError, location, "comment is never closed")
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+		if strings.HasPrefix(s[i:], "<!") {
+			// doctype
+			end := strings.IndexByte(s[i:], '>')
+			if end < 0 {
+				break
+			}
+			i += end + 1
+			continue
+		}
+		t, n, ok := parseTag(s[i:])
+		if !ok {
+			if i+1 < len(s) && (s[i+1] == '/' || isTagNameChar(s[i+1])) {
+				l.add(LintError, location, fmt.Sprintf("malformed tag at offset %v", i))
+			}
+			i++
+			continue
+		}
+		i += n
+		if t.closing {
+			open = l.closeTag(location, open, t.name)
+			continue
+		}
+		l.tag(location, t)
+		if t.name == "style" && !t.selfClosing {
+			end := closingTagIndex(s, i, "style")
+			l.css(location, "style element", s[i:end])
+			i = skipElement(s, i, "style")
+			continue
+		}
+		if voidElements[t.name] || t.selfClosing {
+			continue
+		}
+		if optionalCloseElements[t.name] && len(open) > 0 && open[len(open)-1] == t.name {
+			// a sibling closes the previous element
+			open = open[:len(open)-1]
+		}
+		open = append(open, t.name)
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		if !optionalCloseElements[open[j]] {
+			l.add(LintError, location, fmt.Sprintf("<%v> is never closed", open[j]))
+		}
+	}
+	return l.issues
+}
+
+// closeTag closes the open element with the name, reporting elements it leaves unclosed
+func (l *linter) closeTag(location string, open []string, name string) []string {
+	if voidElements[name] {
+		return open
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		if open[j] != name {
+			continue
+		}
+		for k := len(open) - 1; k > j; k-- {
+			if !optionalCloseElements[open[k]] {
+				l.add(LintError, location, fmt.Sprintf("<%v> is never closed before </%v>", open[k], name))
+			}
+		}
+		return open[:j]
+	}
+	l.add(LintError, location, fmt.Sprintf("</%v> has no matching <%v>", name, name))
+	return open
+}
+
+func (l *linter) tag(location string, t htmlTag) {
+	var src, width, style string
+	hasAlt := false
+	for _, attr := range t.attrs {
+		switch attr.name {
+		case "alt":
+			// empty alt text is fine, it marks a decorative image
+			hasAlt = true
+		case "src":
+			src = attr.value
+		case "width":
+			width = attr.value
+		case "style":
+			style = attr.value
+		}
+	}
+	if style != "" {
+		l.css(location, "<"+t.name+"> style", style)
+	}
+	if t.name != "img" {
+		return
+	}
+	name := src
+	if strings.HasPrefix(name, "data:") || len(name) > 60 {
+		name = "image"
+	}
+	if !hasAlt {
+		l.add(LintWarning, location, fmt.Sprintf("%v has no alt text", name))
+	}
+	if w, err := strconv.ParseFloat(strings.TrimSuffix(width, "px"), 64); err == nil && w > maxImageWidth {
+		l.add(LintWarning, location, fmt.Sprintf("%v is %vpx wide and overflows the page", name, w))
+	}
+	if m := cssWidthPattern.FindStringSubmatch(style); m != nil {
+		if w, err := strconv.ParseFloat(m[1], 64); err == nil && w > maxImageWidth {
+			l.add(LintWarning, location, fmt.Sprintf("%v is %vpx wide and overflows the page", name, w))
+		}
+	}
+	if strings.HasPrefix(src, "data:") && len(src) > maxEmbeddedImageSize {
+		l.add(LintWarning, location, fmt.Sprintf("embedded image is %v bytes, large images slow generation", len(src)))
+	}
+}
+
+func (l *linter) css(location string, where string, css string) {
+	if m := absoluteFontSizePattern.FindString(css); m != "" {
+		l.add(LintWarning, location, fmt.Sprintf("%v uses an absolute font size %q, use em, rem, or %% so text scales", where, strings.TrimSpace(m)))
+	}
+}
@@ -0,0 +1,38 @@
+package docubotlib
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer traces the requests a client sends, implement it with a tracing library such as OpenTelemetry
+type Tracer interface {
+	// Start starts a span for the request, it may add propagation headers to the request. The returned context
+	// is sent with the request and end is called once the response or error arrives.
+	Start(ctx context.Context, req *http.Request) (spanCtx context.Context, end func(resp *http.Response, err error))
+}
+
+// TracingMiddleware traces every request sent through it with tracer, retries and hedged requests get a span each
+func TracingMiddleware(tracer Tracer) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx, end := tracer.Start(req.Context(), req)
+			resp, err := next.RoundTrip(req.WithContext(ctx))
+			end(resp, err)
+			return resp, err
+		})
+	}
+}
+
+// EventPublisher forwards webhook events to another system, implement it with a message broker client such as Kafka's
+type EventPublisher interface {
+	Publish(ctx context.Context, event *WebhookEvent) error
+}
+
+// PublishWebhookEvents returns a WebhookHandler.Handle forwarding every event to publisher.
+// The handler calls it at most once per event, an event whose publishing fails is reported to OnError and dropped.
+func PublishWebhookEvents(publisher EventPublisher) func(ctx context.Context, event *WebhookEvent) error {
+	return func(ctx context.Context, event *WebhookEvent) error {
+		return publisher.Publish(ctx, event)
+	}
+}
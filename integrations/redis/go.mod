@@ -0,0 +1,7 @@
+module github.com/auxai/docubot-go/integrations/redis
+
+go 1.16
+
+require github.com/auxai/docubot-go v0.0.0-00010101000000-000000000000
+
+replace github.com/auxai/docubot-go => ../..
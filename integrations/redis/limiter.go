@@ -0,0 +1,217 @@
+// Package redis shares a docubot account's rate limit between replicas by counting requests in redis, set a
+// Limiter as the docubotlib.Client's Limiter
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	docubotlib "github.com/auxai/docubot-go"
+)
+
+var _ docubotlib.Limiter = (*Limiter)(nil)
+
+// windowScript counts a request in a window, setting the window's expiry on its first request
+const windowScript string = `local n = redis.call('INCR', KEYS[1])
+if n == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return n`
+
+// Limiter is a docubotlib.Limiter that counts requests in fixed windows kept in redis, every process using the same
+// Key shares the same Limit. Window boundaries come from each process's clock, so clocks should be in sync.
+type Limiter struct {
+	// Addr is the host:port of the redis server
+	Addr string
+	// Password authenticates with the redis server when set
+	Password string
+	// DB is the redis database the counters are kept in
+	DB int
+	// Key prefixes the counter keys, use one per docubot account
+	Key string
+	// Limit is the number of requests allowed per Window
+	Limit int
+	// Window is the length of each counting window
+	Window time.Duration
+	// DialTimeout bounds connecting to redis, 5 seconds when zero
+	DialTimeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewLimiter initializes a limiter allowing limit requests per window across every process using key
+func NewLimiter(addr string, key string, limit int, window time.Duration) *Limiter {
+	return &Limiter{Addr: addr, Key: key, Limit: limit, Window: window}
+}
+
+// Wait blocks until the current window has room for the request
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l.Limit <= 0 || l.Window <= 0 {
+		return errors.New("docubot: redis limiter needs a positive limit and window")
+	}
+	for {
+		now := time.Now()
+		window := now.UnixNano() / int64(l.Window)
+		key := fmt.Sprintf("%v:%v", l.Key, window)
+		reply, err := l.do(ctx, "EVAL", windowScript, "1", key, strconv.FormatInt(int64(2*l.Window/time.Millisecond), 10))
+		if err != nil {
+			return err
+		}
+		count, ok := reply.(int64)
+		if !ok {
+			return fmt.Errorf("docubot: unexpected redis reply %v", reply)
+		}
+		if count <= int64(l.Limit) {
+			return nil
+		}
+		next := time.Unix(0, (window+1)*int64(l.Window))
+		if err := sleep(ctx, next.Sub(now)); err != nil {
+			return err
+		}
+	}
+}
+
+// Close closes the connection to redis
+func (l *Limiter) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	err := l.conn.Close()
+	l.conn, l.reader = nil, nil
+	return err
+}
+
+// do sends a command to redis and reads its reply, reconnecting when the connection was lost
+func (l *Limiter) do(ctx context.Context, args ...string) (interface{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		if err := l.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := l.command(ctx, args...)
+	var redisErr replyError
+	if err != nil && !errors.As(err, &redisErr) {
+		// the connection is in an unknown state, the next command reconnects
+		l.conn.Close()
+		l.conn, l.reader = nil, nil
+	}
+	return reply, err
+}
+
+// connect dials redis, authenticating and selecting the database, it is called with l.mu held
+func (l *Limiter) connect(ctx context.Context) error {
+	timeout := l.DialTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", l.Addr)
+	if err != nil {
+		return err
+	}
+	l.conn, l.reader = conn, bufio.NewReader(conn)
+	if l.Password != "" {
+		if _, err := l.command(ctx, "AUTH", l.Password); err != nil {
+			l.conn.Close()
+			l.conn, l.reader = nil, nil
+			return err
+		}
+	}
+	if l.DB != 0 {
+		if _, err := l.command(ctx, "SELECT", strconv.Itoa(l.DB)); err != nil {
+			l.conn.Close()
+			l.conn, l.reader = nil, nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Limiter) command(ctx context.Context, args ...string) (interface{}, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		l.conn.SetDeadline(deadline)
+	} else {
+		l.conn.SetDeadline(time.Time{})
+	}
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+	}
+	if _, err := l.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readReply(l.reader)
+}
+
+// replyError is an error reply from redis, the connection is still usable after one
+type replyError string
+
+func (e replyError) Error() string {
+	return "docubot: redis: " + string(e)
+}
+
+// readReply reads one reply in the redis protocol
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("docubot: malformed redis reply")
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, replyError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("docubot: unknown redis reply type %q", kind)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,121 @@
+package docubotlib
+
+import (
+	"context"
+	"strings"
+)
+
+// TreeChangeImpact is how replacing or deleting a DocumentTree affects the threads still interviewing with it
+type TreeChangeImpact struct {
+	DocumentTreeID string
+	// Deleted is set when the impact of deleting the tree was checked
+	Deleted bool
+	// RemovedVariables are asked for by the current tree but not the changed one
+	RemovedVariables []string
+	// RenamedVariables maps removed variables to the new variable whose question is asked the same way,
+	// they are likely renames that lose the answers already given
+	RenamedVariables map[string]string
+	// ActiveThreads are the tree's threads that aren't complete
+	ActiveThreads []ThreadImpact
+}
+
+// ThreadImpact is how a tree change affects one in-flight thread
+type ThreadImpact struct {
+	Thread Thread
+	// OrphanedVariables are answered variables the changed tree no longer asks for
+	OrphanedVariables []string
+}
+
+// Breaking reports whether the change would break an interview in progress,
+// either by deleting the tree under it or by orphaning answers it has given
+func (i *TreeChangeImpact) Breaking() bool {
+	for _, thread := range i.ActiveThreads {
+		if i.Deleted || len(thread.OrphanedVariables) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTreeUpdate reports the threads in progress with the tree and the answers they would lose
+// if the tree were replaced by updated, call it before UpdateDocumentTree
+func (c *Client) CheckTreeUpdate(ctx context.Context, updated *DocumentTree, callOpts ...CallOption) (*TreeChangeImpact, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.checkTreeChange(ctx, updated.ID, updated)
+}
+
+// CheckTreeDelete reports the threads in progress with the tree, which deleting it would break,
+// call it before DeleteDocumentTree
+func (c *Client) CheckTreeDelete(ctx context.Context, docTreeID string, callOpts ...CallOption) (*TreeChangeImpact, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.checkTreeChange(ctx, docTreeID, nil)
+}
+
+func (c *Client) checkTreeChange(ctx context.Context, docTreeID string, updated *DocumentTree) (*TreeChangeImpact, error) {
+	current, err := c.GetDocumentTree(ctx, docTreeID)
+	if err != nil {
+		return nil, err
+	}
+	impact := &TreeChangeImpact{DocumentTreeID: docTreeID, Deleted: updated == nil}
+	impact.RemovedVariables, impact.RenamedVariables = removedVariables(&current.Data.DocumentTree, updated)
+	err = c.walkThreads(ctx, ListThreadsOptions{DocumentTreeID: docTreeID}, func(thread Thread) error {
+		if !thread.Complete {
+			impact.ActiveThreads = append(impact.ActiveThreads, ThreadImpact{Thread: thread})
+		}
+		return nil
+	})
+	if err != nil || len(impact.RemovedVariables) == 0 || impact.Deleted {
+		return impact, err
+	}
+	group, _ := c.WithClientGroup(ctx, defaultConcurrency)
+	for i := range impact.ActiveThreads {
+		thread := &impact.ActiveThreads[i]
+		group.Go(func(ctx context.Context) error {
+			variables, err := c.getDocubotVariables(ctx, thread.Thread.ID, thread.Thread.UserID)
+			if err != nil {
+				return err
+			}
+			for _, v := range impact.RemovedVariables {
+				if value, ok := variables.Data.Variables[v]; ok && value != nil {
+					thread.OrphanedVariables = append(thread.OrphanedVariables, v)
+				}
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return impact, err
+	}
+	return impact, ctx.Err()
+}
+
+// removedVariables returns the variables of current that updated doesn't ask for, and the ones among them
+// whose question updated asks for under another variable
+func removedVariables(current *DocumentTree, updated *DocumentTree) ([]string, map[string]string) {
+	kept := map[string]bool{}
+	byQuestion := map[string]string{}
+	for _, v := range updated.Variables() {
+		kept[v] = true
+		if question := updated.Question(v); question != nil && question.Question != "" {
+			byQuestion[questionKey(question)] = v
+		}
+	}
+	var removed []string
+	renamed := map[string]string{}
+	for _, v := range current.Variables() {
+		if kept[v] {
+			continue
+		}
+		removed = append(removed, v)
+		if question := current.Question(v); question != nil {
+			if to, ok := byQuestion[questionKey(question)]; ok && current.Question(to) == nil {
+				renamed[v] = to
+			}
+		}
+	}
+	return removed, renamed
+}
+
+func questionKey(question *QuestionNode) string {
+	return strings.ToLower(question.EntityType) + "\x00" + strings.ToLower(strings.TrimSpace(question.Question))
+}
@@ -0,0 +1,20 @@
+package docubotlib
+
+import (
+	"context"
+)
+
+// Limiter paces requests across every process sharing an account, so that a fleet of replicas
+// stays within the account's rate limit together, such as the redis limiter in integrations/redis
+type Limiter interface {
+	// Wait blocks until a request may be sent or ctx is done
+	Wait(ctx context.Context) error
+}
+
+// LimiterFunc is a function that implements Limiter
+type LimiterFunc func(ctx context.Context) error
+
+// Wait calls f(ctx)
+func (f LimiterFunc) Wait(ctx context.Context) error {
+	return f(ctx)
+}
@@ -0,0 +1,171 @@
+package docubotlib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lint issue severities
+const (
+	LintError   string = "error"
+	LintWarning string = "warning"
+)
+
+// LintIssue is a problem found in a Document template
+type LintIssue struct {
+	Severity string
+	// Location is where the issue was found, such as "bodyHtml" or "section:spouse"
+	Location string
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%v: %v: %v", i.Severity, i.Location, i.Message)
+}
+
+// LintDocument checks a Document template against the DocumentTree that fills it in.
+// tree may be nil, in which case variable references aren't checked. Partials aren't checked, see
+// LintDocumentWithPartials.
+func LintDocument(tree *DocumentTree, document *Document) []LintIssue {
+	return LintDocumentWithPartials(tree, document, nil)
+}
+
+// LintDocumentWithPartials checks a Document template like LintDocument, and also that every partial it places
+// is in partials, such as from LoadPartials, and the html of the partials it places. partials may be nil,
+// in which case partials aren't checked.
+func LintDocumentWithPartials(tree *DocumentTree, document *Document, partials map[string]string) []LintIssue {
+	l := linter{tree: tree, document: document, partials: partials, used: map[string]bool{}, linted: map[string]bool{}}
+	if tree != nil {
+		l.variables = map[string]bool{}
+		for _, v := range tree.Variables() {
+			l.variables[v] = true
+		}
+	}
+	l.html("headerHtml", document.HeaderHTML)
+	l.html("bodyHtml", document.BodyHTML)
+	l.html("footerHtml", document.FooterHTML)
+	seen := map[string]bool{}
+	for _, section := range document.Sections {
+		location := sectionMarkerPrefix + section.Name
+		switch {
+		case section.Name == "":
+			l.add(LintError, "sections", "section has no name")
+		case seen[section.Name]:
+			l.add(LintError, location, "section name is used more than once")
+		}
+		seen[section.Name] = true
+		l.html(location, section.HTML)
+		if !ValidLogicalOperator(section.LogicalOperator) {
+			l.add(LintError, location, fmt.Sprintf("unknown logical operator %q", section.LogicalOperator))
+		}
+		if len(section.Conditions) == 0 {
+			l.add(LintWarning, location, "section has no conditions and always renders")
+		}
+		for _, condition := range section.Conditions {
+			if !ValidComparator(condition.Comparator) {
+				l.add(LintError, location, fmt.Sprintf("unknown comparator %q", condition.Comparator))
+			}
+			l.variable(location, condition.VariableName, "condition")
+		}
+	}
+	for _, section := range document.Sections {
+		if section.Name != "" && !l.used[section.Name] {
+			l.add(LintWarning, sectionMarkerPrefix+section.Name, "section is never placed in the document")
+		}
+	}
+	return l.issues
+}
+
+type linter struct {
+	tree      *DocumentTree
+	document  *Document
+	partials  map[string]string
+	variables map[string]bool
+	used      map[string]bool
+	// linted are the partials whose html has been checked, it is checked once however often it is placed
+	linted map[string]bool
+	issues []LintIssue
+}
+
+func (l *linter) add(severity string, location string, message string) {
+	l.issues = append(l.issues, LintIssue{Severity: severity, Location: location, Message: message})
+}
+
+func (l *linter) html(location string, s string) {
+	for _, placeholder := range extractPlaceholders(location, s) {
+		if placeholder.Name == "" {
+			l.add(LintError, location, "empty placeholder")
+			continue
+		}
+		if placeholder.Kind != PlaceholderVariable && len(placeholder.Filters) > 0 {
+			l.add(LintWarning, location, fmt.Sprintf("filters don't apply to %v placeholder %q", placeholder.Kind, placeholder.Name))
+		}
+		switch placeholder.Kind {
+		case PlaceholderSection:
+			l.used[placeholder.Name] = true
+			if l.document.Section(placeholder.Name) == nil {
+				l.add(LintError, location, fmt.Sprintf("placeholder references unknown section %q", placeholder.Name))
+			}
+		case PlaceholderPartial:
+			l.partial(location, placeholder.Name)
+		case PlaceholderFile:
+			l.variable(location, placeholder.Name, "file placeholder")
+			if question := l.tree.Question(placeholder.Name); question != nil && question.EntityType != EntityTypeFile {
+				l.add(LintError, location, fmt.Sprintf("file placeholder references variable %q that isn't a file", placeholder.Name))
+			}
+		default:
+			l.variable(location, placeholder.Name, "placeholder")
+			l.filters(location, placeholder)
+		}
+	}
+}
+
+// filterEntityTypes are the entity types of the questions whose answers each filter formats
+var filterEntityTypes = map[string][]string{
+	FilterDate:     {EntityTypeDate, EntityTypeDateTime},
+	FilterCurrency: {EntityTypeCurrency, EntityTypeNumber},
+	FilterNumber:   {EntityTypeNumber, EntityTypeCurrency},
+	FilterOrdinal:  {EntityTypeNumber},
+}
+
+// filters checks the placeholder's filters exist and suit the entity type of the question asking for the variable
+func (l *linter) filters(location string, placeholder Placeholder) {
+	question := l.tree.Question(placeholder.Name)
+	for _, filter := range placeholder.Filters {
+		f := parseFilter(filter)
+		if err := f.check(); err != nil {
+			l.add(LintError, location, fmt.Sprintf("placeholder %q: %v", placeholder.Name, strings.TrimPrefix(err.Error(), "docubot: ")))
+			continue
+		}
+		types, ok := filterEntityTypes[f.name]
+		if !ok || question == nil {
+			continue
+		}
+		suits := false
+		for _, entityType := range types {
+			suits = suits || strings.EqualFold(question.EntityType, entityType)
+		}
+		if !suits {
+			l.add(LintWarning, location, fmt.Sprintf("filter %q formats %v answers but %q is a %v question", f.name, types[0], placeholder.Name, question.EntityType))
+		}
+	}
+}
+
+func (l *linter) variable(location string, name string, kind string) {
+	if l.variables != nil && !l.variables[name] {
+		l.add(LintError, location, fmt.Sprintf("%v references variable %q that no question asks for", kind, name))
+	}
+}
+
+func (l *linter) partial(location string, name string) {
+	if l.partials == nil || l.linted[name] {
+		return
+	}
+	html, ok := l.partials[name]
+	if !ok {
+		l.add(LintError, location, fmt.Sprintf("placeholder references unknown partial %q", name))
+		return
+	}
+	l.linted[name] = true
+	l.html(partialPlaceholderPrefix+name, html)
+}
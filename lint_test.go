@@ -0,0 +1,61 @@
+package docubotlib
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLintedFiltersRender checks that placeholders with filters the linter accepts render their variables, and
+// that filters the linter rejects fail the rendering instead of rendering blank
+func TestLintedFiltersRender(t *testing.T) {
+	tree := &DocumentTree{
+		ID: "filters",
+		EntryQuestion: fixtureQuestion("name", "What is your name?", EntityTypeText,
+			fixtureQuestion("closingDate", "When does the sale close?", EntityTypeDate),
+			fixtureQuestion("price", "What is the price?", EntityTypeCurrency),
+			fixtureQuestion("floor", "Which floor is the unit on?", EntityTypeNumber),
+		),
+	}
+	variables := map[string]interface{}{"name": "jane doe", "closingDate": "2024-03-01", "price": 1234.5, "floor": 21}
+	rendered := map[string]string{
+		"{{name | upper}}":                            "JANE DOE",
+		"{{name|title}}":                              "Jane Doe",
+		"{{name | lower | capitalize}}":               "Jane doe",
+		"{{closingDate | date}}":                      "March 1, 2024",
+		"{{closingDate | date:iso}}":                  "2024-03-01",
+		`{{closingDate | formatDate:"MMMM D, YYYY"}}`: "March 1, 2024",
+		"{{price | currency}}":                        "$1,234.50",
+		"{{price | number}}":                          "1,234.5",
+		"{{floor | ordinal}}":                         "21st",
+		"{{name | uppercase}}":                        "JANE DOE",
+	}
+	for placeholder, want := range rendered {
+		document := &Document{BodyHTML: "<p>" + placeholder + "</p>"}
+		for _, issue := range LintDocument(tree, document) {
+			if issue.Severity == LintError {
+				t.Errorf("%v: lint error %q", placeholder, issue.Message)
+			}
+		}
+		out, err := RenderDocumentWithOptions(document, variables, RenderOptions{Locale: &LocaleEnUS})
+		if err != nil {
+			t.Errorf("%v: %v", placeholder, err)
+			continue
+		}
+		if got := strings.TrimSuffix(strings.TrimPrefix(out.BodyHTML, "<p>"), "</p>"); got != want {
+			t.Errorf("%v rendered %q, want %q", placeholder, got, want)
+		}
+	}
+	for _, placeholder := range []string{"{{name | shout}}", "{{closingDate | date:weekday}}", "{{name | upper:loud}}"} {
+		document := &Document{BodyHTML: "<p>" + placeholder + "</p>"}
+		linted := false
+		for _, issue := range LintDocument(tree, document) {
+			linted = linted || issue.Severity == LintError
+		}
+		if !linted {
+			t.Errorf("%v: no lint error", placeholder)
+		}
+		if _, err := RenderDocumentWithOptions(document, variables, RenderOptions{}); err == nil {
+			t.Errorf("%v rendered without an error", placeholder)
+		}
+	}
+}
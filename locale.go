@@ -0,0 +1,280 @@
+package docubotlib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// canonicalDateLayout is the layout docubot stores date variables in
+const canonicalDateLayout string = "2006-01-02"
+
+// Date orders understood by Locale
+const (
+	DateOrderMDY string = "MDY"
+	DateOrderDMY string = "DMY"
+	DateOrderYMD string = "YMD"
+)
+
+// canonicalNumberPattern matches numbers in the form docubot stores them, such as -1234.56
+var canonicalNumberPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Locale describes how dates, numbers, and currency are written in a region
+type Locale struct {
+	Tag              string
+	DateOrder        string
+	DateSeparator    string
+	DecimalSeparator string
+	GroupSeparator   string
+	CurrencyCode     string
+	CurrencySymbol   string
+	// CurrencySuffix places the currency symbol after the amount
+	CurrencySuffix bool
+}
+
+// Common locales
+var (
+	LocaleEnUS = Locale{Tag: "en-US", DateOrder: DateOrderMDY, DateSeparator: "/", DecimalSeparator: ".", GroupSeparator: ",", CurrencyCode: "USD", CurrencySymbol: "$"}
+	LocaleEnGB = Locale{Tag: "en-GB", DateOrder: DateOrderDMY, DateSeparator: "/", DecimalSeparator: ".", GroupSeparator: ",", CurrencyCode: "GBP", CurrencySymbol: "£"}
+	LocaleEnCA = Locale{Tag: "en-CA", DateOrder: DateOrderYMD, DateSeparator: "-", DecimalSeparator: ".", GroupSeparator: ",", CurrencyCode: "CAD", CurrencySymbol: "$"}
+	LocaleEsUS = Locale{Tag: "es-US", DateOrder: DateOrderMDY, DateSeparator: "/", DecimalSeparator: ".", GroupSeparator: ",", CurrencyCode: "USD", CurrencySymbol: "$"}
+	LocaleEsES = Locale{Tag: "es-ES", DateOrder: DateOrderDMY, DateSeparator: "/", DecimalSeparator: ",", GroupSeparator: ".", CurrencyCode: "EUR", CurrencySymbol: "€", CurrencySuffix: true}
+	LocaleDeDE = Locale{Tag: "de-DE", DateOrder: DateOrderDMY, DateSeparator: ".", DecimalSeparator: ",", GroupSeparator: ".", CurrencyCode: "EUR", CurrencySymbol: "€", CurrencySuffix: true}
+	LocaleFrFR = Locale{Tag: "fr-FR", DateOrder: DateOrderDMY, DateSeparator: "/", DecimalSeparator: ",", GroupSeparator: " ", CurrencyCode: "EUR", CurrencySymbol: "€", CurrencySuffix: true}
+)
+
+var locales = map[string]Locale{}
+
+func init() {
+	for _, l := range []Locale{LocaleEnUS, LocaleEnGB, LocaleEnCA, LocaleEsUS, LocaleEsES, LocaleDeDE, LocaleFrFR} {
+		locales[strings.ToLower(l.Tag)] = l
+	}
+}
+
+// LookupLocale returns the common locale with the tag, such as "en-US"
+func LookupLocale(tag string) (Locale, bool) {
+	l, ok := locales[strings.ToLower(strings.ReplaceAll(tag, "_", "-"))]
+	return l, ok
+}
+
+// ParseDate parses a date written in the locale, ISO 8601 dates are always accepted
+func (l Locale) ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(canonicalDateLayout, s); err == nil {
+		return t, nil
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '/' || r == '.' || r == '-' || r == ' '
+	})
+	if len(fields) != 3 {
+		return time.Time{}, fmt.Errorf("docubot: %q is not a date", s)
+	}
+	var parts [3]int
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("docubot: %q is not a date", s)
+		}
+		parts[i] = n
+	}
+	var year, month, day int
+	switch l.DateOrder {
+	case DateOrderDMY:
+		day, month, year = parts[0], parts[1], parts[2]
+	case DateOrderYMD:
+		year, month, day = parts[0], parts[1], parts[2]
+	default:
+		month, day, year = parts[0], parts[1], parts[2]
+	}
+	if year < 100 && len(fields[indexOfYear(l.DateOrder)]) <= 2 {
+		year += 2000
+		if year > time.Now().Year()+10 {
+			year -= 100
+		}
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Year() != year || int(t.Month()) != month || t.Day() != day {
+		return time.Time{}, fmt.Errorf("docubot: %q is not a valid date", s)
+	}
+	return t, nil
+}
+
+func indexOfYear(order string) int {
+	if order == DateOrderYMD {
+		return 0
+	}
+	return 2
+}
+
+// FormatDate writes a date the way the locale does
+func (l Locale) FormatDate(t time.Time) string {
+	sep := l.DateSeparator
+	if sep == "" {
+		sep = "/"
+	}
+	switch l.DateOrder {
+	case DateOrderDMY:
+		return fmt.Sprintf("%02d%v%02d%v%04d", t.Day(), sep, int(t.Month()), sep, t.Year())
+	case DateOrderYMD:
+		return fmt.Sprintf("%04d%v%02d%v%02d", t.Year(), sep, int(t.Month()), sep, t.Day())
+	}
+	return fmt.Sprintf("%02d%v%02d%v%04d", int(t.Month()), sep, t.Day(), sep, t.Year())
+}
+
+// ParseNumber parses a number written in the locale into docubot's canonical form, such as "1234.56".
+// The canonical form is returned as text so no precision is lost to floating point.
+func (l Locale) ParseNumber(s string) (string, error) {
+	n := strings.TrimSpace(s)
+	if l.GroupSeparator != "" {
+		n = strings.ReplaceAll(n, l.GroupSeparator, "")
+	}
+	// narrow and regular no-break spaces are common group separators in printed numbers
+	n = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(n)
+	if l.DecimalSeparator != "" && l.DecimalSeparator != "." {
+		n = strings.ReplaceAll(n, l.DecimalSeparator, ".")
+	}
+	n = strings.TrimPrefix(n, "+")
+	if !canonicalNumberPattern.MatchString(n) {
+		return "", fmt.Errorf("docubot: %q is not a number", s)
+	}
+	return n, nil
+}
+
+// FormatNumber writes a canonical number the way the locale does
+func (l Locale) FormatNumber(canonical string) (string, error) {
+	if !canonicalNumberPattern.MatchString(canonical) {
+		return "", fmt.Errorf("docubot: %q is not a canonical number", canonical)
+	}
+	negative := strings.HasPrefix(canonical, "-")
+	canonical = strings.TrimPrefix(canonical, "-")
+	integer, fraction := canonical, ""
+	if i := strings.Index(canonical, "."); i >= 0 {
+		integer, fraction = canonical[:i], canonical[i+1:]
+	}
+	var b strings.Builder
+	if negative {
+		b.WriteString("-")
+	}
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(l.GroupSeparator)
+		}
+		b.WriteRune(r)
+	}
+	if fraction != "" {
+		sep := l.DecimalSeparator
+		if sep == "" {
+			sep = "."
+		}
+		b.WriteString(sep)
+		b.WriteString(fraction)
+	}
+	return b.String(), nil
+}
+
+// ParseCurrency parses an amount of money written in the locale, such as "1.234,56 €",
+// into a canonical number with two decimal places, such as "1234.56"
+func (l Locale) ParseCurrency(s string) (string, error) {
+	n := strings.TrimSpace(s)
+	for _, symbol := range []string{l.CurrencySymbol, l.CurrencyCode} {
+		if symbol != "" {
+			n = strings.ReplaceAll(n, symbol, "")
+		}
+	}
+	canonical, err := l.ParseNumber(n)
+	if err != nil {
+		return "", fmt.Errorf("docubot: %q is not an amount of money", s)
+	}
+	return roundCanonical(canonical, 2)
+}
+
+// FormatCurrency writes a canonical amount of money the way the locale does, such as "$1,234.56"
+func (l Locale) FormatCurrency(canonical string) (string, error) {
+	rounded, err := roundCanonical(canonical, 2)
+	if err != nil {
+		return "", err
+	}
+	n, err := l.FormatNumber(rounded)
+	if err != nil {
+		return "", err
+	}
+	if l.CurrencySuffix {
+		return n + " " + l.CurrencySymbol, nil
+	}
+	if strings.HasPrefix(n, "-") {
+		return "-" + l.CurrencySymbol + n[1:], nil
+	}
+	return l.CurrencySymbol + n, nil
+}
+
+// FormatValue writes a variable's value for a document the way the locale does.
+// Dates, numbers, and amounts of money are localized, everything else is written as is.
+func (l Locale) FormatValue(v interface{}) string {
+	switch value := v.(type) {
+	case time.Time:
+		return l.FormatDate(value)
+	case Money:
+		return l.FormatMoney(value)
+	case float64:
+		if n, err := l.FormatNumber(strconv.FormatFloat(value, 'f', -1, 64)); err == nil {
+			return n
+		}
+	case int:
+		if n, err := l.FormatNumber(strconv.Itoa(value)); err == nil {
+			return n
+		}
+	case json.Number:
+		if n, err := l.FormatNumber(value.String()); err == nil {
+			return n
+		}
+	case string:
+		if t, err := time.Parse(canonicalDateLayout, value); err == nil {
+			return l.FormatDate(t)
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// roundCanonical rounds a canonical number half away from zero to the number of decimal places
+func roundCanonical(canonical string, places int) (string, error) {
+	if !canonicalNumberPattern.MatchString(canonical) {
+		return "", errors.New("docubot: not a canonical number")
+	}
+	negative := strings.HasPrefix(canonical, "-")
+	canonical = strings.TrimPrefix(canonical, "-")
+	integer, fraction := canonical, ""
+	if i := strings.Index(canonical, "."); i >= 0 {
+		integer, fraction = canonical[:i], canonical[i+1:]
+	}
+	roundUp := len(fraction) > places && fraction[places] >= '5'
+	for len(fraction) < places {
+		fraction += "0"
+	}
+	digits := []byte(integer + fraction[:places])
+	if roundUp {
+		i := len(digits) - 1
+		for ; i >= 0; i-- {
+			if digits[i] == '9' {
+				digits[i] = '0'
+				continue
+			}
+			digits[i]++
+			break
+		}
+		if i < 0 {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	s := string(digits[:len(digits)-places])
+	if places > 0 {
+		s += "." + string(digits[len(digits)-places:])
+	}
+	if negative && strings.Trim(s, "0.") != "" {
+		s = "-" + s
+	}
+	return s, nil
+}
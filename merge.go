@@ -0,0 +1,134 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+// MergeStrategy is how MergeThreads settles a variable answered differently in both threads
+type MergeStrategy string
+
+// Merge strategies
+const (
+	// MergePreferNewer keeps the value from the thread updated last
+	MergePreferNewer MergeStrategy = "preferNewer"
+	// MergePreferPrimary keeps the primary thread's value
+	MergePreferPrimary MergeStrategy = "preferPrimary"
+	// MergeConflictList doesn't merge when values conflict, MergeThreads returns the conflicts with
+	// ErrMergeConflicts so they can be settled and passed to MergeThreadVariables
+	MergeConflictList MergeStrategy = "conflictList"
+)
+
+// ErrMergeConflicts is returned by MergeThreads with MergeConflictList when the threads' variables conflict
+var ErrMergeConflicts = errors.New("docubot: threads have conflicting variables")
+
+// MergeConflict is a variable answered differently in the primary and duplicate threads
+type MergeConflict struct {
+	Variable  string
+	Primary   interface{}
+	Duplicate interface{}
+}
+
+// MergeResult describes a merge of a duplicate thread into its primary
+type MergeResult struct {
+	// Variables are the merged variables, variables in conflict hold the value the strategy kept
+	Variables map[string]interface{}
+	// Conflicts are the variables answered differently in both threads, sorted by variable
+	Conflicts []MergeConflict
+	// Thread is the primary thread after the merge, nil when nothing was merged
+	Thread *Thread
+}
+
+// MergeThreads reconciles the variables of a duplicate intake into the primary thread and closes the duplicate.
+// Variables answered in only one thread are kept, the strategy settles the ones answered in both.
+func (c *Client) MergeThreads(ctx context.Context, primary *Thread, duplicate *Thread, strategy MergeStrategy, callOpts ...CallOption) (*MergeResult, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	switch strategy {
+	case MergePreferNewer, MergePreferPrimary, MergeConflictList:
+	default:
+		return nil, fmt.Errorf("docubot: unknown merge strategy %q", strategy)
+	}
+	primaryVariables, err := c.getDocubotVariables(ctx, primary.ID, primary.UserID)
+	if err != nil {
+		return nil, err
+	}
+	duplicateVariables, err := c.getDocubotVariables(ctx, duplicate.ID, duplicate.UserID)
+	if err != nil {
+		return nil, err
+	}
+	preferDuplicate := strategy == MergePreferNewer && duplicate.UpdatedAt.After(primary.UpdatedAt)
+	result := mergeVariables(primaryVariables.Data.Variables, duplicateVariables.Data.Variables, preferDuplicate)
+	if strategy == MergeConflictList && len(result.Conflicts) > 0 {
+		return result, ErrMergeConflicts
+	}
+	thread, err := c.mergeThreadVariables(ctx, primary, duplicate, result.Variables)
+	if err != nil {
+		return result, err
+	}
+	result.Thread = thread
+	return result, nil
+}
+
+// MergeThreadVariables replaces the primary thread's variables with the provided ones and closes the duplicate,
+// such as with the variables of a MergeResult once its conflicts are settled. Designated variables are encrypted
+// for the primary thread, including values still encrypted for the duplicate.
+func (c *Client) MergeThreadVariables(ctx context.Context, primary *Thread, duplicate *Thread, variables map[string]interface{}, callOpts ...CallOption) (*Thread, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.mergeThreadVariables(ctx, primary, duplicate, variables)
+}
+
+func (c *Client) mergeThreadVariables(ctx context.Context, primary *Thread, duplicate *Thread, variables map[string]interface{}) (*Thread, error) {
+	params := url.Values{}
+	params.Set("user", primary.UserID)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/merge?%v",
+		c.DocubotAPIURLBase,
+		primary.ID,
+		params.Encode(),
+	)
+	// the merged answers are decrypted, and the duplicate's ciphertext is bound to the duplicate
+	encrypted, err := c.VariableEncryption.rebindVariables(ctx, primary.ID, []string{duplicate.ID}, variables)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{
+		"duplicate":     duplicate.ID,
+		"duplicateUser": duplicate.UserID,
+		"variables":     encrypted,
+	}
+	req, err := c.newRequest(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadResponse
+	if err := c.doJSON(req, variables, &response); err != nil {
+		return nil, err
+	}
+	return &response.Data.Thread, nil
+}
+
+func mergeVariables(primary map[string]interface{}, duplicate map[string]interface{}, preferDuplicate bool) *MergeResult {
+	result := &MergeResult{Variables: copyVariables(primary)}
+	for name, value := range duplicate {
+		existing, ok := primary[name]
+		if !ok || existing == nil || existing == "" {
+			result.Variables[name] = value
+			continue
+		}
+		if value == nil || value == "" || reflect.DeepEqual(existing, value) {
+			continue
+		}
+		result.Conflicts = append(result.Conflicts, MergeConflict{Variable: name, Primary: existing, Duplicate: value})
+		if preferDuplicate {
+			result.Variables[name] = value
+		}
+	}
+	sort.Slice(result.Conflicts, func(i, j int) bool {
+		return result.Conflicts[i].Variable < result.Conflicts[j].Variable
+	})
+	return result
+}
@@ -0,0 +1,43 @@
+package docubotlib
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMergeThreadsReencryptsForPrimary(t *testing.T) {
+	ctx := context.Background()
+	f, c := newFakeDocubot(t)
+	c.VariableEncryption = testEncryption()
+	duplicateSSN, err := c.VariableEncryption.Encrypt(ctx, "thread_dup", testSSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary := &Thread{ID: "thread_primary", UserID: "user"}
+	duplicate := &Thread{ID: "thread_dup", UserID: "user"}
+	f.addThread(*primary, map[string]interface{}{"name": "Jane Roe"})
+	f.addThread(*duplicate, map[string]interface{}{"ssn": duplicateSSN})
+
+	result, err := c.MergeThreads(ctx, primary, duplicate, MergePreferPrimary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Variables["ssn"] != testSSN {
+		t.Errorf("merged %v, want the duplicate's decrypted answer", result.Variables["ssn"])
+	}
+	// the variables of a raw read still hold the duplicate's ciphertext
+	if _, err := c.MergeThreadVariables(ctx, primary, duplicate, map[string]interface{}{"ssn": duplicateSSN}); err != nil {
+		t.Fatal(err)
+	}
+	if f.sent(testSSN) {
+		t.Fatal("an encrypted variable was sent in plaintext")
+	}
+	stored, _ := f.stored(primary.ID)["ssn"].(string)
+	if answer, err := c.VariableEncryption.Decrypt(ctx, primary.ID, stored); err != nil || answer != testSSN {
+		t.Errorf("primary stores %q, which decrypts to %q, %v", stored, answer, err)
+	}
+	if !strings.HasPrefix(stored, encryptedVariablePrefix) {
+		t.Errorf("primary stores %q in plaintext", stored)
+	}
+}
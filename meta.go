@@ -0,0 +1,73 @@
+package docubotlib
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DocumentURLMeta is the meta received from getting a document's URL from docubot
+type DocumentURLMeta struct {
+	ThreadID    string     `json:"threadId"`
+	UserID      string     `json:"userId"`
+	GeneratedAt *time.Time `json:"generatedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	// RawMeta holds every meta field, including ones without a field above
+	RawMeta map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the typed fields and keeps every field in RawMeta
+func (m *DocumentURLMeta) UnmarshalJSON(data []byte) error {
+	type typed DocumentURLMeta
+	if err := json.Unmarshal(data, (*typed)(m)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &m.RawMeta)
+}
+
+// MarshalJSON encodes RawMeta with the typed fields taking precedence
+func (m DocumentURLMeta) MarshalJSON() ([]byte, error) {
+	type typed DocumentURLMeta
+	return mergeRawMeta(m.RawMeta, typed(m))
+}
+
+// DocumentVariablesMeta is the meta received from getting a document's Variables from docubot
+type DocumentVariablesMeta struct {
+	ThreadID  string     `json:"threadId"`
+	UserID    string     `json:"userId"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	// Version increases with every write to the thread's variables, it is a token for WithMinVariablesVersion
+	Version int64 `json:"version,omitempty"`
+	// RawMeta holds every meta field, including ones without a field above
+	RawMeta map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the typed fields and keeps every field in RawMeta
+func (m *DocumentVariablesMeta) UnmarshalJSON(data []byte) error {
+	type typed DocumentVariablesMeta
+	if err := json.Unmarshal(data, (*typed)(m)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &m.RawMeta)
+}
+
+// MarshalJSON encodes RawMeta with the typed fields taking precedence
+func (m DocumentVariablesMeta) MarshalJSON() ([]byte, error) {
+	type typed DocumentVariablesMeta
+	return mergeRawMeta(m.RawMeta, typed(m))
+}
+
+// mergeRawMeta encodes raw with the fields of typed written over it
+func mergeRawMeta(raw map[string]interface{}, typed interface{}) ([]byte, error) {
+	data, err := json.Marshal(typed)
+	if err != nil || len(raw) == 0 {
+		return data, err
+	}
+	merged := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		merged[k] = v
+	}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
+}
@@ -0,0 +1,172 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VariableMigration maps a thread's variables from one version of its tree to the next, such as after the tree
+// renamed or retyped variables. Steps run in order, they are VariableTransforms so custom steps can be mixed
+// with MigrateRename, MigrateRetype, MigrateDrop, and MigrateMapValues.
+type VariableMigration struct {
+	FromVersion int
+	ToVersion   int
+	Steps       []VariableTransform
+}
+
+// MigrateRename returns a step moving the variable's value to a new name, it does nothing when the variable is unanswered
+func MigrateRename(oldName string, newName string) VariableTransform {
+	return func(ctx context.Context, variables map[string]interface{}) (map[string]interface{}, error) {
+		if v, ok := variables[oldName]; ok {
+			delete(variables, oldName)
+			variables[newName] = v
+		}
+		return variables, nil
+	}
+}
+
+// MigrateDrop returns a step removing the variable
+func MigrateDrop(name string) VariableTransform {
+	return func(ctx context.Context, variables map[string]interface{}) (map[string]interface{}, error) {
+		delete(variables, name)
+		return variables, nil
+	}
+}
+
+// MigrateMapValues returns a step replacing the variable's value using values, such as when a multiple choice
+// question's keys changed. Values without a mapping are kept.
+func MigrateMapValues(name string, values map[string]string) VariableTransform {
+	return func(ctx context.Context, variables map[string]interface{}) (map[string]interface{}, error) {
+		if v, ok := variables[name].(string); ok {
+			if mapped, ok := values[v]; ok {
+				variables[name] = mapped
+			}
+		}
+		return variables, nil
+	}
+}
+
+// MigrateRetype returns a step converting the variable's value to the canonical form of the entity type,
+// EntityTypeText, EntityTypeNumber, EntityTypeCurrency, or EntityTypeDate. The migration fails when the value
+// can't be converted.
+func MigrateRetype(name string, entityType string) VariableTransform {
+	return func(ctx context.Context, variables map[string]interface{}) (map[string]interface{}, error) {
+		v, ok := variables[name]
+		if !ok || v == nil {
+			return variables, nil
+		}
+		converted, err := retypeValue(v, entityType)
+		if err != nil {
+			return nil, fmt.Errorf("docubot: migrating %v: %w", name, err)
+		}
+		variables[name] = converted
+		return variables, nil
+	}
+}
+
+func retypeValue(v interface{}, entityType string) (interface{}, error) {
+	text := fmt.Sprintf("%v", v)
+	switch value := v.(type) {
+	case float64:
+		text = strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		text = value.String()
+	case time.Time:
+		text = value.Format(time.RFC3339)
+	}
+	switch strings.ToLower(entityType) {
+	case EntityTypeText:
+		return text, nil
+	case EntityTypeNumber:
+		return LocaleEnUS.ParseNumber(text)
+	case EntityTypeCurrency:
+		m, err := LocaleEnUS.ParseMoney(text)
+		if err != nil {
+			return nil, err
+		}
+		return m.String(), nil
+	case EntityTypeDate:
+		if t, err := time.Parse(canonicalDateLayout, text); err == nil {
+			return DateAnswer(t), nil
+		}
+		if t, err := time.Parse(time.RFC3339, text); err == nil {
+			return DateAnswer(t), nil
+		}
+		return nil, fmt.Errorf("%q is not a date", text)
+	}
+	return nil, fmt.Errorf("can't convert to entity type %q", entityType)
+}
+
+// MigrateVariables runs the migrations leading from one tree version to another over a copy of the variables.
+// Migrations are chained by version, it fails when no chain leads from fromVersion to toVersion.
+func MigrateVariables(ctx context.Context, variables map[string]interface{}, migrations []VariableMigration, fromVersion int, toVersion int) (map[string]interface{}, error) {
+	migrated := copyVariables(variables)
+	for version := fromVersion; version != toVersion; {
+		migration := findMigration(migrations, version)
+		if migration == nil || migration.ToVersion <= version || migration.ToVersion > toVersion {
+			return nil, fmt.Errorf("docubot: no migration of variables from tree version %v toward %v", version, toVersion)
+		}
+		for _, step := range migration.Steps {
+			var err error
+			if migrated, err = step(ctx, migrated); err != nil {
+				return nil, err
+			}
+			if migrated == nil {
+				migrated = map[string]interface{}{}
+			}
+		}
+		version = migration.ToVersion
+	}
+	return migrated, nil
+}
+
+func findMigration(migrations []VariableMigration, fromVersion int) *VariableMigration {
+	for i := range migrations {
+		if migrations[i].FromVersion == fromVersion {
+			return &migrations[i]
+		}
+	}
+	return nil
+}
+
+// MigrateThread migrates a thread pinned to an older version of its tree: its variables are run through the
+// migrations, the changes are written to the thread, and the thread is pinned to toVersion
+func (c *Client) MigrateThread(ctx context.Context, thread *Thread, migrations []VariableMigration, toVersion int, callOpts ...CallOption) (*ThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	variables, err := c.getDocubotVariables(ctx, thread.ID, thread.UserID)
+	if err != nil {
+		return nil, err
+	}
+	migrated, err := MigrateVariables(ctx, variables.Data.Variables, migrations, thread.TreeVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	changes := variableChanges(variables.Data.Variables, migrated)
+	if len(changes) > 0 {
+		if _, err := c.setDocubotVariables(ctx, thread.ID, thread.UserID, changes); err != nil {
+			return nil, err
+		}
+	}
+	return c.UpdateThread(ctx, thread.ID, thread.UserID, ThreadUpdate{TreeVersion: toVersion})
+}
+
+// variableChanges returns the variables to write to turn before into after, removed variables are nil
+func variableChanges(before map[string]interface{}, after map[string]interface{}) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for name, v := range after {
+		if old, ok := before[name]; !ok || !reflect.DeepEqual(old, v) {
+			changes[name] = v
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changes[name] = nil
+		}
+	}
+	return changes
+}
@@ -0,0 +1,103 @@
+package docubotlib
+
+import (
+	"strings"
+)
+
+// AnswerNormalizer rewrites an answer before it is sent to docubot, see Client.AnswerNormalizers
+type AnswerNormalizer func(answer string) string
+
+// DefaultAnswerNormalizers fix the text mobile keyboards produce that most often fails docubot's validation
+var DefaultAnswerNormalizers = []AnswerNormalizer{ComposeAccents, StraightenQuotes, CollapseNewlines, TrimAnswer}
+
+// TrimAnswer removes leading and trailing whitespace, including non-breaking spaces
+func TrimAnswer(answer string) string {
+	return strings.TrimSpace(answer)
+}
+
+// CollapseNewlines joins the lines of an answer with single spaces, dropping blank lines and the whitespace
+// around line breaks
+func CollapseNewlines(answer string) string {
+	if !strings.ContainsAny(answer, "\r\n\u2028\u2029") {
+		return answer
+	}
+	lines := strings.FieldsFunc(answer, func(r rune) bool {
+		return r == '\r' || r == '\n' || r == '\u2028' || r == '\u2029'
+	})
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// smartQuotes are the typographic quotes keyboards substitute for straight ones
+var smartQuotes = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`,
+)
+
+// StraightenQuotes replaces typographic quotes and apostrophes with straight ones
+func StraightenQuotes(answer string) string {
+	return smartQuotes.Replace(answer)
+}
+
+// accentCompositions maps combining accents to pairs of a letter and the letter composed with the accent
+var accentCompositions = map[rune]string{
+	'\u0300': "AÀEÈIÌOÒUÙaàeèiìoòuùNǸnǹ",                                               // combining grave accent
+	'\u0301': "AÁEÉIÍOÓUÚYÝaáeéiíoóuúyýCĆcćLĹlĺNŃnńRŔrŕSŚsśZŹzźGǴgǵÆǼæǽØǾøǿ",           // combining acute accent
+	'\u0302': "AÂEÊIÎOÔUÛaâeêiîoôuûCĈcĉGĜgĝHĤhĥJĴjĵSŜsŝWŴwŵYŶyŷ",                       // combining circumflex accent
+	'\u0303': "AÃNÑOÕaãnñoõIĨiĩUŨuũ",                                                   // combining tilde
+	'\u0304': "AĀaāEĒeēIĪiīOŌoōUŪuūÆǢæǣYȲyȳ",                                           // combining macron
+	'\u0306': "AĂaăEĔeĕGĞgğIĬiĭOŎoŏUŬuŭ",                                               // combining breve
+	'\u0307': "CĊcċEĖeėGĠgġIİZŻzżAȦaȧOȮoȯ",                                             // combining dot above
+	'\u0308': "AÄEËIÏOÖUÜaäeëiïoöuüyÿYŸ",                                               // combining diaeresis
+	'\u030a': "AÅaåUŮuů",                                                               // combining ring above
+	'\u030b': "OŐoőUŰuű",                                                               // combining double acute accent
+	'\u030c': "CČcčDĎdďEĚeěLĽlľNŇnňRŘrřSŠsšTŤtťZŽzžAǍaǎIǏiǐOǑoǒUǓuǔGǦgǧKǨkǩƷǮʒǯjǰHȞhȟ", // combining caron
+	'\u031b': "OƠoơUƯuư",                                                               // combining horn
+	'\u0326': "SȘsșTȚtț",                                                               // combining comma below
+	'\u0327': "CÇcçGĢgģKĶkķLĻlļNŅnņRŖrŗSŞsşTŢtţEȨeȩ",                                   // combining cedilla
+	'\u0328': "AĄaąEĘeęIĮiįUŲuųOǪoǫ",                                                   // combining ogonek
+}
+
+// ComposeAccents composes latin letters followed by a combining accent into the accented letter, the
+// decomposed form some keyboards and operating systems produce. It covers what unicode NFC changes in
+// latin text, it is not a full normalization.
+func ComposeAccents(answer string) string {
+	runes := []rune(answer)
+	composed := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		if pairs, ok := accentCompositions[r]; ok && len(composed) > 0 {
+			if letter, ok := composeAccent(pairs, composed[len(composed)-1]); ok {
+				composed[len(composed)-1] = letter
+				continue
+			}
+		}
+		composed = append(composed, r)
+	}
+	return string(composed)
+}
+
+func composeAccent(pairs string, base rune) (rune, bool) {
+	p := []rune(pairs)
+	for i := 0; i+1 < len(p); i += 2 {
+		if p[i] == base {
+			return p[i+1], true
+		}
+	}
+	return 0, false
+}
+
+// normalizeAnswer runs the client's normalizers over the answer, in order, encrypted answers are left alone
+func (c *Client) normalizeAnswer(answer string) string {
+	if strings.HasPrefix(answer, encryptedVariablePrefix) {
+		return answer
+	}
+	for _, normalize := range c.AnswerNormalizers {
+		answer = normalize(answer)
+	}
+	return answer
+}
@@ -0,0 +1,77 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// AccountOverview is a data model of the account's activity and health, for an operational dashboard
+type AccountOverview struct {
+	// ActiveThreads counts the threads that aren't complete, expired, or merged
+	ActiveThreads int `json:"activeThreads"`
+	// ThreadsStartedToday, ThreadsCompletedToday, and DocumentsGeneratedToday count since midnight in the
+	// client's Location, or UTC without one
+	ThreadsStartedToday     int `json:"threadsStartedToday"`
+	ThreadsCompletedToday   int `json:"threadsCompletedToday"`
+	DocumentsGeneratedToday int `json:"documentsGeneratedToday"`
+	// Requests and ErrorRate are the account's api requests over the last 24 hours and the fraction of them
+	// that failed with a 5xx
+	Requests  int     `json:"requests"`
+	ErrorRate float64 `json:"errorRate"`
+	// Webhooks is the delivery health of each webhook endpoint over the last 24 hours
+	Webhooks  []WebhookEndpointHealth `json:"webhooks"`
+	UpdatedAt time.Time               `json:"updatedAt"`
+}
+
+// WebhookEndpointHealth is a data model of how deliveries to a webhook endpoint went
+type WebhookEndpointHealth struct {
+	EndpointID string `json:"endpointId"`
+	URL        string `json:"url"`
+	Delivered  int    `json:"delivered"`
+	Failed     int    `json:"failed"`
+	// Pending counts the deliveries waiting for a retry
+	Pending         int        `json:"pending"`
+	LastDeliveredAt *time.Time `json:"lastDeliveredAt,omitempty"`
+	LastFailedAt    *time.Time `json:"lastFailedAt,omitempty"`
+	// LastError is why the last failed delivery failed, such as "connection refused" or "status 500"
+	LastError string `json:"lastError,omitempty"`
+}
+
+// SuccessRate is the fraction of the attempted deliveries that succeeded, 1 when none were attempted
+func (h WebhookEndpointHealth) SuccessRate() float64 {
+	if h.Delivered+h.Failed == 0 {
+		return 1
+	}
+	return float64(h.Delivered) / float64(h.Delivered+h.Failed)
+}
+
+// AccountOverviewResponse is the response received from getting the account's overview
+type AccountOverviewResponse struct {
+	Data AccountOverviewData    `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// AccountOverviewData is the response data received from getting the account's overview
+type AccountOverviewData struct {
+	Overview AccountOverview `json:"overview"`
+}
+
+// GetAccountOverview gets the account's thread and document counts, error rate, and webhook delivery health
+// in a single call
+func (c *Client) GetAccountOverview(ctx context.Context, callOpts ...CallOption) (*AccountOverviewResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	if c.Location != nil {
+		params.Set("timeZone", c.Location.String())
+	}
+	url := fmt.Sprintf("%v/api/v1/analytics/overview?%v", c.DocubotAPIURLBase, params.Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response AccountOverviewResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
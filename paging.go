@@ -0,0 +1,201 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Page is the pagination meta received from list endpoints
+type Page struct {
+	// NextCursor is empty on the last page
+	NextCursor string `json:"nextCursor"`
+	// Total is how many items match across all pages, -1 when docubot doesn't count them for the endpoint
+	Total int `json:"total"`
+	// PageSize is how many items docubot put on the page at most
+	PageSize int `json:"pageSize"`
+}
+
+// ListMeta is the former name of Page
+type ListMeta = Page
+
+// UnmarshalJSON decodes the page, Total is -1 when docubot didn't include it
+func (p *Page) UnmarshalJSON(b []byte) error {
+	type page Page
+	decoded := page{Total: -1}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	*p = Page(decoded)
+	return nil
+}
+
+// Last reports whether the page is the last one
+func (p Page) Last() bool {
+	return p.NextCursor == ""
+}
+
+// PageProgress is reported after every page a walk over a list endpoint lists
+type PageProgress struct {
+	// Page is the meta of the page just listed
+	Page Page
+	// Listed is how many items have been listed so far, including the page's
+	Listed int
+}
+
+// WithPageProgress calls onPage after every page listed by methods walking all pages, such as ListAllThreads,
+// so long walks can report their progress against Page.Total
+func WithPageProgress(onPage func(PageProgress)) CallOption {
+	return func(o *callOptions) {
+		o.onPage = onPage
+	}
+}
+
+// reportPage passes the progress to the WithPageProgress callback carried by ctx, if any
+func (c *Client) reportPage(ctx context.Context, page Page, listed int) {
+	if onPage := c.resolveCallOptions(ctx).onPage; onPage != nil {
+		onPage(PageProgress{Page: page, Listed: listed})
+	}
+}
+
+// waitForRateLimit waits for the rate limit window to reset when no requests remain in it
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	status := c.RateLimitStatus()
+	if !status.Exhausted() {
+		return ctx.Err()
+	}
+	return sleep(ctx, time.Until(status.Reset))
+}
+
+// ListAllThreads lists every thread matching opts by walking all pages, waiting out the rate limit between pages.
+// opts.Cursor is where the walk starts, the threads listed before an error are returned with it.
+func (c *Client) ListAllThreads(ctx context.Context, opts ListThreadsOptions, callOpts ...CallOption) ([]Thread, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	var threads []Thread
+	err := c.walkThreads(ctx, opts, func(thread Thread) error {
+		threads = append(threads, thread)
+		return nil
+	})
+	return threads, err
+}
+
+// StreamThreads sends every thread matching opts on the returned channel as pages are listed.
+// The error channel receives the error that stopped the walk, if any, and is closed after the thread channel.
+// Client.Shutdown stops the walk with ErrClosed.
+func (c *Client) StreamThreads(ctx context.Context, opts ListThreadsOptions, callOpts ...CallOption) (<-chan Thread, <-chan error) {
+	ctx = withCallOptions(ctx, callOpts)
+	threads := make(chan Thread)
+	errs := make(chan error, 1)
+	started := c.background(ctx, true, func(ctx context.Context) {
+		err := c.recoverCall("thread stream", func() error {
+			return c.walkThreads(ctx, opts, func(thread Thread) error {
+				select {
+				case threads <- thread:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		})
+		close(threads)
+		if err != nil {
+			errs <- c.shutdownError(err)
+		}
+		close(errs)
+	})
+	if !started {
+		close(threads)
+		errs <- ErrClosed
+		close(errs)
+	}
+	return threads, errs
+}
+
+func (c *Client) walkThreads(ctx context.Context, opts ListThreadsOptions, visit func(Thread) error) error {
+	listed := 0
+	for {
+		if err := c.waitForRateLimit(ctx); err != nil {
+			return err
+		}
+		page, err := c.ListThreads(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, thread := range page.Data.Threads {
+			if err := visit(thread); err != nil {
+				return err
+			}
+		}
+		listed += len(page.Data.Threads)
+		c.reportPage(ctx, page.Meta, listed)
+		if page.Meta.Last() {
+			return nil
+		}
+		opts.Cursor = page.Meta.NextCursor
+	}
+}
+
+// ListAllDocumentTrees lists every DocumentTree by walking all pages, waiting out the rate limit between pages
+func (c *Client) ListAllDocumentTrees(ctx context.Context, opts ListOptions, callOpts ...CallOption) ([]DocumentTree, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	var trees []DocumentTree
+	err := c.walkDocumentTrees(ctx, opts, func(tree DocumentTree) error {
+		trees = append(trees, tree)
+		return nil
+	})
+	return trees, err
+}
+
+// StreamDocumentTrees sends every DocumentTree on the returned channel as pages are listed, like StreamThreads
+func (c *Client) StreamDocumentTrees(ctx context.Context, opts ListOptions, callOpts ...CallOption) (<-chan DocumentTree, <-chan error) {
+	ctx = withCallOptions(ctx, callOpts)
+	trees := make(chan DocumentTree)
+	errs := make(chan error, 1)
+	started := c.background(ctx, true, func(ctx context.Context) {
+		err := c.recoverCall("tree stream", func() error {
+			return c.walkDocumentTrees(ctx, opts, func(tree DocumentTree) error {
+				select {
+				case trees <- tree:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		})
+		close(trees)
+		if err != nil {
+			errs <- c.shutdownError(err)
+		}
+		close(errs)
+	})
+	if !started {
+		close(trees)
+		errs <- ErrClosed
+		close(errs)
+	}
+	return trees, errs
+}
+
+func (c *Client) walkDocumentTrees(ctx context.Context, opts ListOptions, visit func(DocumentTree) error) error {
+	listed := 0
+	for {
+		if err := c.waitForRateLimit(ctx); err != nil {
+			return err
+		}
+		page, err := c.ListDocumentTrees(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, tree := range page.Data.DocumentTrees {
+			if err := visit(tree); err != nil {
+				return err
+			}
+		}
+		listed += len(page.Data.DocumentTrees)
+		c.reportPage(ctx, page.Meta, listed)
+		if page.Meta.Last() {
+			return nil
+		}
+		opts.Cursor = page.Meta.NextCursor
+	}
+}
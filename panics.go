@@ -0,0 +1,39 @@
+package docubotlib
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Backoff before a background worker that panicked is restarted
+const (
+	panicRestartBackoff    time.Duration = time.Second
+	maxPanicRestartBackoff time.Duration = 30 * time.Second
+)
+
+// PanicError is a panic recovered in work the client does in the background, such as an event subscription,
+// a message queue or a bulk operation. It is reported to OnError and fails the work that panicked.
+type PanicError struct {
+	// Worker names the background work that panicked
+	Worker string
+	// Value is the value passed to panic
+	Value interface{}
+	// Stack is the stack of the goroutine that panicked
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("docubot: panic in %v: %v", e.Worker, e.Value)
+}
+
+// recoverCall calls fn, turning a panic into a *PanicError that is reported to OnError and returned
+func (c *Client) recoverCall(worker string, fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Worker: worker, Value: v, Stack: debug.Stack()}
+			c.reportError(err)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,129 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// partialPlaceholderPrefix starts placeholders that place a shared Partial
+const partialPlaceholderPrefix string = "partial:"
+
+// Partial is a data model for html shared by the Documents of an account, such as a firm header or a signature
+// block. Documents place it with PartialMarker, where it renders with the document's variables.
+type Partial struct {
+	ID string `json:"id"`
+	// Name identifies the partial in placeholders, it is unique in the account
+	Name      string    `json:"name"`
+	HTML      string    `json:"html"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// PartialResponse is the response received from getting or saving a Partial
+type PartialResponse struct {
+	Data PartialData            `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// PartialData is the response data received from getting or saving a Partial
+type PartialData struct {
+	Partial Partial `json:"partial"`
+}
+
+// PartialListResponse is the response received from listing Partials
+type PartialListResponse struct {
+	Data PartialListData `json:"data"`
+	Meta Page            `json:"meta"`
+}
+
+// PartialListData is the response data received from listing Partials
+type PartialListData struct {
+	Partials []Partial `json:"partials"`
+}
+
+// PartialMarker returns the placeholder that renders the named partial
+func PartialMarker(name string) string {
+	return "{{" + partialPlaceholderPrefix + name + "}}"
+}
+
+// ListPartials lists a page of the account's partials
+func (c *Client) ListPartials(ctx context.Context, opts ListOptions, callOpts ...CallOption) (*PartialListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/partials?%v", c.DocubotAPIURLBase, opts.params().Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response PartialListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetPartial gets the partial with the name
+func (c *Client) GetPartial(ctx context.Context, name string, callOpts ...CallOption) (*PartialResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	req, err := c.newRequest(ctx, "GET", c.partialURL(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response PartialResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// SavePartial creates the partial with the name or replaces its html, documents placing it render the new html
+// from then on
+func (c *Client) SavePartial(ctx context.Context, name string, html string, callOpts ...CallOption) (*PartialResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	partial := Partial{Name: name, HTML: html}
+	if c.Sanitizer != nil {
+		partial.HTML = c.Sanitizer.SanitizeHTML(partial.HTML)
+	}
+	partial.HTML = translateFilters(partial.HTML)
+	req, err := c.newRequest(ctx, "PUT", c.partialURL(name), map[string]interface{}{
+		"partial": partial,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var response PartialResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeletePartial deletes the partial with the name, documents still placing it fail to generate
+func (c *Client) DeletePartial(ctx context.Context, name string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	req, err := c.newRequest(ctx, "DELETE", c.partialURL(name), nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// LoadPartials lists every partial of the account as a map of names to html, for RenderOptions.Partials
+// and LintDocumentWithPartials
+func (c *Client) LoadPartials(ctx context.Context, callOpts ...CallOption) (map[string]string, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	partials := map[string]string{}
+	opts := ListOptions{}
+	for {
+		page, err := c.ListPartials(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, partial := range page.Data.Partials {
+			partials[partial.Name] = partial.HTML
+		}
+		if page.Meta.Last() {
+			return partials, nil
+		}
+		opts.Cursor = page.Meta.NextCursor
+	}
+}
+
+func (c *Client) partialURL(name string) string {
+	return fmt.Sprintf("%v/api/v1/partials/%v", c.DocubotAPIURLBase, url.PathEscape(name))
+}
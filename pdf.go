@@ -0,0 +1,552 @@
+package docubotlib
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// pdf.go holds just enough of a pdf reader to find text in generated documents,
+// it follows the page tree and reads the text operators of page content streams
+
+var (
+	pdfObjectPattern   = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfRefPattern      = regexp.MustCompile(`(\d+)\s+\d+\s+R`)
+	pdfTypePattern     = regexp.MustCompile(`/Type\s*/(\w+)`)
+	pdfLengthPattern   = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfKidsPattern     = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+	pdfContentsPattern = regexp.MustCompile(`/Contents\s*(\d+\s+\d+\s+R|\[[^\]]*\])`)
+	pdfPagesPattern    = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R`)
+	pdfFilterPattern   = regexp.MustCompile(`/Filter\s*(\[\s*)?/(\w+)`)
+	pdfObjStmPattern   = regexp.MustCompile(`/N\s+(\d+)[\s\S]*?/First\s+(\d+)|/First\s+(\d+)[\s\S]*?/N\s+(\d+)`)
+)
+
+// errNotPDF is returned when a document has no pdf objects
+var errNotPDF = errors.New("docubot: document is not a pdf")
+
+type pdfDocument struct {
+	objects map[int]pdfObject
+	order   []int
+}
+
+type pdfObject struct {
+	dict   []byte
+	stream []byte
+}
+
+func parsePDF(data []byte) (*pdfDocument, error) {
+	doc := &pdfDocument{objects: map[int]pdfObject{}}
+	matches := pdfObjectPattern.FindAllSubmatchIndex(data, -1)
+	for i := 0; i < len(matches); i++ {
+		match := matches[i]
+		number, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+		body := data[match[1]:]
+		object, end, ok := parsePDFObject(body)
+		if !ok {
+			continue
+		}
+		doc.add(number, object)
+		// skip object headers that appeared inside this object's stream
+		for i+1 < len(matches) && matches[i+1][0] < match[1]+end {
+			i++
+		}
+	}
+	if len(doc.objects) == 0 {
+		return nil, errNotPDF
+	}
+	for _, number := range append([]int(nil), doc.order...) {
+		object := doc.objects[number]
+		if doc.objectType(object) == "ObjStm" {
+			doc.expandObjectStream(object)
+		}
+	}
+	return doc, nil
+}
+
+func (d *pdfDocument) add(number int, object pdfObject) {
+	if _, ok := d.objects[number]; !ok {
+		d.order = append(d.order, number)
+	}
+	d.objects[number] = object
+}
+
+// parsePDFObject parses the object body following "N G obj", returning it and the length consumed
+func parsePDFObject(body []byte) (pdfObject, int, bool) {
+	endobj := bytes.Index(body, []byte("endobj"))
+	streamStart := bytes.Index(body, []byte("stream"))
+	if streamStart < 0 || endobj >= 0 && endobj < streamStart {
+		if endobj < 0 {
+			return pdfObject{}, 0, false
+		}
+		return pdfObject{dict: body[:endobj]}, endobj + len("endobj"), true
+	}
+	object := pdfObject{dict: body[:streamStart]}
+	start := streamStart + len("stream")
+	if bytes.HasPrefix(body[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(body) && (body[start] == '\n' || body[start] == '\r') {
+		start++
+	}
+	end := -1
+	if m := pdfLengthPattern.FindSubmatch(object.dict); m != nil && len(m[2]) == 0 {
+		if n, err := strconv.Atoi(string(m[1])); err == nil && start+n <= len(body) &&
+			bytes.HasPrefix(bytes.TrimLeft(body[start+n:], "\r\n "), []byte("endstream")) {
+			end = start + n
+		}
+	}
+	if end < 0 {
+		i := bytes.Index(body[start:], []byte("endstream"))
+		if i < 0 {
+			return pdfObject{}, 0, false
+		}
+		end = start + i
+		for end > start && (body[end-1] == '\n' || body[end-1] == '\r') {
+			end--
+		}
+	}
+	object.stream = body[start:end]
+	consumed := end
+	if i := bytes.Index(body[end:], []byte("endobj")); i >= 0 {
+		consumed = end + i + len("endobj")
+	}
+	return object, consumed, true
+}
+
+// expandObjectStream adds the objects compressed into an object stream
+func (d *pdfDocument) expandObjectStream(object pdfObject) {
+	data := d.decode(object)
+	m := pdfObjStmPattern.FindSubmatch(object.dict)
+	if data == nil || m == nil {
+		return
+	}
+	n, first := atoi(m[1]), atoi(m[2])
+	if len(m[1]) == 0 {
+		first, n = atoi(m[3]), atoi(m[4])
+	}
+	if first > len(data) {
+		return
+	}
+	header := bytes.Fields(data[:first])
+	for i := 0; i+1 < len(header) && i/2 < n; i += 2 {
+		number, offset := atoi(header[i]), atoi(header[i+1])
+		start := first + offset
+		end := len(data)
+		if i+3 < len(header) {
+			end = first + atoi(header[i+3])
+		}
+		if start > end || end > len(data) {
+			continue
+		}
+		if _, ok := d.objects[number]; !ok {
+			d.add(number, pdfObject{dict: data[start:end]})
+		}
+	}
+}
+
+func (d *pdfDocument) objectType(object pdfObject) string {
+	if m := pdfTypePattern.FindSubmatch(object.dict); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// pages returns the page objects in page order
+func (d *pdfDocument) pages() []pdfObject {
+	for _, number := range d.order {
+		object := d.objects[number]
+		if d.objectType(object) != "Catalog" {
+			continue
+		}
+		if m := pdfPagesPattern.FindSubmatch(object.dict); m != nil {
+			var pages []pdfObject
+			d.walkPages(atoi(m[1]), map[int]bool{}, &pages)
+			if len(pages) > 0 {
+				return pages
+			}
+		}
+	}
+	// without a usable page tree pages are assumed to be in file order
+	var pages []pdfObject
+	for _, number := range d.order {
+		if object := d.objects[number]; d.objectType(object) == "Page" {
+			pages = append(pages, object)
+		}
+	}
+	return pages
+}
+
+func (d *pdfDocument) walkPages(number int, visited map[int]bool, pages *[]pdfObject) {
+	object, ok := d.objects[number]
+	if !ok || visited[number] {
+		return
+	}
+	visited[number] = true
+	if d.objectType(object) == "Page" {
+		*pages = append(*pages, object)
+		return
+	}
+	if m := pdfKidsPattern.FindSubmatch(object.dict); m != nil {
+		for _, ref := range pdfRefPattern.FindAllSubmatch(m[1], -1) {
+			d.walkPages(atoi(ref[1]), visited, pages)
+		}
+	}
+}
+
+// content returns the decoded content streams of a page joined together
+func (d *pdfDocument) content(page pdfObject) []byte {
+	m := pdfContentsPattern.FindSubmatch(page.dict)
+	if m == nil {
+		return nil
+	}
+	var content []byte
+	for _, ref := range pdfRefPattern.FindAllSubmatch(m[1], -1) {
+		if object, ok := d.objects[atoi(ref[1])]; ok {
+			content = append(content, d.decode(object)...)
+			content = append(content, '\n')
+		}
+	}
+	return content
+}
+
+// decode returns the decoded stream of an object, nil when it uses a filter other than FlateDecode
+func (d *pdfDocument) decode(object pdfObject) []byte {
+	m := pdfFilterPattern.FindSubmatch(object.dict)
+	if m == nil {
+		return object.stream
+	}
+	if string(m[2]) != "FlateDecode" {
+		return nil
+	}
+	r, err := zlib.NewReader(bytes.NewReader(object.stream))
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+	// truncated streams are common enough that whatever inflated is used
+	data, _ := io.ReadAll(r)
+	return data
+}
+
+func atoi(b []byte) int {
+	n, _ := strconv.Atoi(string(b))
+	return n
+}
+
+// pdfTextRun is text drawn by one text operator and where it starts on the page
+type pdfTextRun struct {
+	text string
+	x    float64
+	y    float64
+}
+
+type pdfMatrix [6]float64
+
+var pdfIdentity = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+func (m pdfMatrix) multiply(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// textRuns interprets a page's content and returns the text it draws
+func (d *pdfDocument) textRuns(page pdfObject) []pdfTextRun {
+	lexer := &pdfLexer{data: d.content(page)}
+	var runs []pdfTextRun
+	var operands []pdfToken
+	var stack []pdfMatrix
+	ctm, tm, tlm := pdfIdentity, pdfIdentity, pdfIdentity
+	leading := 0.0
+	number := func(i int) float64 {
+		if i < len(operands) && operands[i].kind == pdfNumber {
+			return operands[i].number
+		}
+		return 0
+	}
+	matrix := func() (pdfMatrix, bool) {
+		if len(operands) < 6 {
+			return pdfMatrix{}, false
+		}
+		operands = operands[len(operands)-6:]
+		return pdfMatrix{number(0), number(1), number(2), number(3), number(4), number(5)}, true
+	}
+	move := func(tx float64, ty float64) {
+		tlm = pdfMatrix{1, 0, 0, 1, tx, ty}.multiply(tlm)
+		tm = tlm
+	}
+	show := func(text string) {
+		if text == "" {
+			return
+		}
+		at := tm.multiply(ctm)
+		runs = append(runs, pdfTextRun{text: text, x: at[4], y: at[5]})
+	}
+	for {
+		token, ok := lexer.next()
+		if !ok {
+			return runs
+		}
+		if token.kind != pdfOperator {
+			operands = append(operands, token)
+			continue
+		}
+		switch token.text {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if len(stack) > 0 {
+				ctm = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "cm":
+			if m, ok := matrix(); ok {
+				ctm = m.multiply(ctm)
+			}
+		case "BT":
+			tm, tlm = pdfIdentity, pdfIdentity
+		case "Tm":
+			if m, ok := matrix(); ok {
+				tm, tlm = m, m
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				operands = operands[len(operands)-2:]
+				if token.text == "TD" {
+					leading = -number(1)
+				}
+				move(number(0), number(1))
+			}
+		case "TL":
+			if len(operands) >= 1 {
+				leading = operands[len(operands)-1].number
+			}
+		case "T*":
+			move(0, -leading)
+		case "Tj", "'", "\"":
+			if token.text != "Tj" {
+				move(0, -leading)
+			}
+			if len(operands) > 0 {
+				show(operands[len(operands)-1].text)
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				show(operands[len(operands)-1].text)
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// pdf content stream token kinds
+const (
+	pdfNumber = iota
+	pdfString
+	pdfName
+	pdfArray
+	pdfOperator
+)
+
+type pdfToken struct {
+	kind   int
+	number float64
+	// text is the string, name, operator, or the strings of an array joined together
+	text string
+}
+
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func (l *pdfLexer) next() (pdfToken, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return pdfToken{}, false
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		return pdfToken{kind: pdfString, text: l.literal()}, true
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return pdfToken{kind: pdfName, text: "<<"}, true
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfToken{kind: pdfName, text: ">>"}, true
+	case c == '<':
+		return pdfToken{kind: pdfString, text: l.hex()}, true
+	case c == '[':
+		l.pos++
+		var text []byte
+		for {
+			token, ok := l.next()
+			if !ok || token.kind == pdfOperator && token.text == "]" {
+				return pdfToken{kind: pdfArray, text: string(text)}, true
+			}
+			if token.kind == pdfString {
+				text = append(text, token.text...)
+			}
+		}
+	case c == ']':
+		l.pos++
+		return pdfToken{kind: pdfOperator, text: "]"}, true
+	case c == '/':
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.data) && isPDFRegular(l.data[l.pos]) {
+			l.pos++
+		}
+		return pdfToken{kind: pdfName, text: string(l.data[start:l.pos])}, true
+	}
+	start := l.pos
+	for l.pos < len(l.data) && isPDFRegular(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		l.pos++
+		return l.next()
+	}
+	word := string(l.data[start:l.pos])
+	if n, err := strconv.ParseFloat(word, 64); err == nil {
+		return pdfToken{kind: pdfNumber, number: n}, true
+	}
+	if word == "ID" {
+		l.skipInlineImage()
+	}
+	return pdfToken{kind: pdfOperator, text: word}, true
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// literal reads a (string), decoding its escapes
+func (l *pdfLexer) literal() string {
+	l.pos++
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return latin1(out)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				continue
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				if e == '\r' && l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return latin1(out)
+}
+
+// hex reads a <hex string>
+func (l *pdfLexer) hex() string {
+	l.pos++
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return ""
+		}
+		out = append(out, byte(v))
+	}
+	return latin1(out)
+}
+
+// skipInlineImage skips the binary data of an inline image up to its EI operator
+func (l *pdfLexer) skipInlineImage() {
+	for i := l.pos + 1; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && isPDFSpace(l.data[i-1]) &&
+			(i+2 == len(l.data) || isPDFSpace(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
+
+// latin1 decodes the bytes of a simple font string one character per byte
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isPDFRegular(c byte) bool {
+	return !isPDFSpace(c) && c != '(' && c != ')' && c != '<' && c != '>' && c != '[' && c != ']' &&
+		c != '{' && c != '}' && c != '/' && c != '%'
+}
@@ -0,0 +1,94 @@
+package docubotlib
+
+import (
+	"strings"
+)
+
+// Placeholder kinds
+const (
+	PlaceholderVariable string = "variable"
+	PlaceholderFile     string = "file"
+	PlaceholderSection  string = "section"
+	PlaceholderPartial  string = "partial"
+)
+
+// filterSeparator separates a placeholder's name from its filters, as in {{name | upper}}
+const filterSeparator string = "|"
+
+// Placeholder is a placeholder found in a Document's html
+type Placeholder struct {
+	// Kind is PlaceholderVariable, PlaceholderFile, PlaceholderSection, or PlaceholderPartial
+	Kind string
+	// Name is the variable, section, or partial the placeholder references
+	Name string
+	// Filters are the filters applied to the value in order, such as "upper" in {{name | upper}}
+	Filters []string
+	// Raw is the placeholder as written
+	Raw string
+	// Location is where the placeholder was found, such as "bodyHtml" or "section:spouse"
+	Location string
+	// Offset is the byte offset of the placeholder in the location's html
+	Offset int
+	// Line and Column are the 1-based position of the placeholder in the location's html
+	Line   int
+	Column int
+}
+
+// ExtractPlaceholders returns every placeholder in the document's header, body, footer, and section html,
+// in the order they appear
+func ExtractPlaceholders(document *Document) []Placeholder {
+	placeholders := extractPlaceholders("headerHtml", document.HeaderHTML)
+	placeholders = append(placeholders, extractPlaceholders("bodyHtml", document.BodyHTML)...)
+	placeholders = append(placeholders, extractPlaceholders("footerHtml", document.FooterHTML)...)
+	for _, section := range document.Sections {
+		placeholders = append(placeholders, extractPlaceholders(sectionMarkerPrefix+section.Name, section.HTML)...)
+	}
+	return placeholders
+}
+
+// extractPlaceholders returns the placeholders in s, found in location
+func extractPlaceholders(location string, s string) []Placeholder {
+	var placeholders []Placeholder
+	line, lineStart, scanned := 1, 0, 0
+	for _, match := range placeholderPattern.FindAllStringSubmatchIndex(s, -1) {
+		start, end := match[0], match[1]
+		for i := scanned; i < start; i++ {
+			if s[i] == '\n' {
+				line++
+				lineStart = i + 1
+			}
+		}
+		scanned = start
+		kind, name, filters := parsePlaceholder(s[match[2]:match[3]])
+		placeholders = append(placeholders, Placeholder{
+			Kind:     kind,
+			Name:     name,
+			Filters:  filters,
+			Raw:      s[start:end],
+			Location: location,
+			Offset:   start,
+			Line:     line,
+			Column:   len([]rune(s[lineStart:start])) + 1,
+		})
+	}
+	return placeholders
+}
+
+// parsePlaceholder splits the text between a placeholder's braces into its kind, name, and filters
+func parsePlaceholder(inner string) (string, string, []string) {
+	parts := strings.Split(inner, filterSeparator)
+	name := strings.TrimSpace(parts[0])
+	var filters []string
+	for _, filter := range parts[1:] {
+		filters = append(filters, strings.TrimSpace(filter))
+	}
+	switch {
+	case strings.HasPrefix(name, sectionMarkerPrefix):
+		return PlaceholderSection, strings.TrimPrefix(name, sectionMarkerPrefix), filters
+	case strings.HasPrefix(name, filePlaceholderPrefix):
+		return PlaceholderFile, strings.TrimPrefix(name, filePlaceholderPrefix), filters
+	case strings.HasPrefix(name, partialPlaceholderPrefix):
+		return PlaceholderPartial, strings.TrimPrefix(name, partialPlaceholderPrefix), filters
+	}
+	return PlaceholderVariable, name, filters
+}
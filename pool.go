@@ -0,0 +1,67 @@
+package docubotlib
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// defaultConcurrency is the number of workers used when a helper's concurrency isn't set
+const defaultConcurrency int = 4
+
+// runPool calls work for every index below n using at most workers goroutines and waits for them to finish.
+// The workers run in the client's background so Shutdown waits for them. Indexes not yet started when ctx is
+// done are skipped. When work panics for an index, the panic is recovered and passed to failed, and the worker
+// goes on with the next index. Once the client is shut down no workers start, and every index fails with ErrClosed.
+func (c *Client) runPool(ctx context.Context, workers int, n int, work func(i int), failed func(i int, err error)) {
+	if workers < 1 {
+		workers = defaultConcurrency
+	}
+	if workers > n {
+		workers = n
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	started := 0
+	for ; started < workers; started++ {
+		wg.Add(1)
+		ok := c.background(ctx, false, func(context.Context) {
+			defer wg.Done()
+			for i := range indexes {
+				if err := recoverWork(i, work); err != nil {
+					failed(i, err)
+				}
+			}
+		})
+		if !ok {
+			wg.Done()
+			break
+		}
+	}
+	if started == 0 {
+		for i := 0; i < n; i++ {
+			failed(i, ErrClosed)
+		}
+		return
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			i = n
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// recoverWork calls work for the index, returning an error holding the panic if it panics
+func recoverWork(i int, work func(i int)) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Worker: "bulk operation", Value: v, Stack: debug.Stack()}
+		}
+	}()
+	work(i)
+	return nil
+}
@@ -0,0 +1,40 @@
+package docubotlib
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdownWaitsForPool(t *testing.T) {
+	c := NewClient("http://docubot.test", "key", "secret")
+	started := make(chan struct{})
+	var finished int32
+	go c.runPool(context.Background(), 2, 4, func(i int) {
+		if i == 0 {
+			close(started)
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&finished, 1)
+	}, func(int, error) {})
+	<-started
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&finished); n != 4 {
+		t.Errorf("Shutdown returned with %v of 4 indexes done", n)
+	}
+
+	var failed int32
+	c.runPool(context.Background(), 2, 3, func(int) {
+		t.Error("worked after the client was shut down")
+	}, func(i int, err error) {
+		if err == ErrClosed {
+			atomic.AddInt32(&failed, 1)
+		}
+	})
+	if failed != 3 {
+		t.Errorf("%v indexes failed with ErrClosed after shutdown, want 3", failed)
+	}
+}
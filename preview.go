@@ -0,0 +1,203 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrPreviewIncomplete is returned by PreviewRun when the answers run out before the interview is complete
+var ErrPreviewIncomplete = errors.New("docubot: preview interview is not complete")
+
+// PreviewTurn is one scripted answer sent by PreviewRun and docubot's reply to it
+type PreviewTurn struct {
+	Answer   string
+	Messages []string
+}
+
+// PreviewRunResult is the outcome of a scripted preview interview
+type PreviewRunResult struct {
+	Turns     []PreviewTurn
+	Variables map[string]interface{}
+	Complete  bool
+	// Document is the preview document, it is nil unless the interview completed and
+	// the caller must close it
+	Document io.ReadCloser
+	// Lint lists problems in the document's html rendered with the interview's variables,
+	// they often show up as a garbled pdf
+	Lint []LintIssue
+	// Report is which variables and sections the document rendered with the interview's variables,
+	// nil when it couldn't be rendered
+	Report *RenderReport
+}
+
+// PreviewRun previews an interview of the tree with scripted answers, the first answer starts the interview.
+// Answers are sent until the interview is complete, extra answers are ignored, and the preview document is
+// then fetched. ErrPreviewIncomplete is returned along with the turns so far if the answers run out first.
+func (c *Client) PreviewRun(ctx context.Context, docTree *DocumentTree, document *Document, answers []string, callOpts ...CallOption) (*PreviewRunResult, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	result := &PreviewRunResult{Variables: map[string]interface{}{}}
+	for _, answer := range answers {
+		response, err := c.sendPreviewMessage(ctx, answer, result.Variables, docTree)
+		if err != nil {
+			return result, err
+		}
+		result.Turns = append(result.Turns, PreviewTurn{Answer: answer, Messages: response.Data.Messages})
+		if response.Data.Variables != nil {
+			result.Variables = response.Data.Variables
+		}
+		if response.Data.Complete {
+			result.Complete = true
+			break
+		}
+	}
+	if !result.Complete {
+		return result, ErrPreviewIncomplete
+	}
+	variables, err := c.transformVariables(ctx, result.Variables)
+	if err != nil {
+		return result, err
+	}
+	opts := RenderOptions{Location: c.Location}
+	result.Lint = lintRendered(document, variables, opts)
+	if rendered, err := RenderDocumentWithOptions(document, variables, opts); err == nil {
+		result.Report = rendered.Report
+	}
+	doc, err := c.requestPreviewDoc(ctx, variables, document)
+	if err != nil {
+		return result, err
+	}
+	result.Document = doc
+	return result, nil
+}
+
+// PreviewSession carries the variables of a preview interview from one turn to the next.
+// It is safe for concurrent use, turns are sent one at a time in the order they are made.
+type PreviewSession struct {
+	client  *Client
+	docTree *DocumentTree
+	mu      sync.Mutex
+	states  []previewState
+}
+
+// previewState is the session after a turn
+type previewState struct {
+	turn      PreviewTurn
+	variables map[string]interface{}
+	complete  bool
+}
+
+// PreviewSnapshot is the saved state of a PreviewSession, it is unaffected by later turns
+type PreviewSnapshot struct {
+	states []previewState
+}
+
+// Turns returns the turns made before the snapshot was taken
+func (s PreviewSnapshot) Turns() []PreviewTurn {
+	turns := make([]PreviewTurn, len(s.states))
+	for i, state := range s.states {
+		turns[i] = state.turn
+	}
+	return turns
+}
+
+// NewPreviewSession initializes a preview session of the tree with no variables
+func (c *Client) NewPreviewSession(docTree *DocumentTree) *PreviewSession {
+	return &PreviewSession{client: c, docTree: docTree}
+}
+
+// Send sends a message in the session, the session's variables are updated with the reply's
+func (s *PreviewSession) Send(ctx context.Context, message string, callOpts ...CallOption) (*PreviewMessageResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	variables := s.variables()
+	response, err := s.client.sendPreviewMessage(ctx, message, variables, s.docTree)
+	if err != nil {
+		return nil, err
+	}
+	if response.Data.Variables != nil {
+		variables = response.Data.Variables
+	}
+	s.states = append(s.states, previewState{
+		turn:      PreviewTurn{Answer: message, Messages: response.Data.Messages},
+		variables: copyVariables(variables),
+		complete:  response.Data.Complete,
+	})
+	return response, nil
+}
+
+// Variables returns a copy of the variables collected so far
+func (s *PreviewSession) Variables() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyVariables(s.variables())
+}
+
+// Turns returns the turns made in the session
+func (s *PreviewSession) Turns() []PreviewTurn {
+	return s.Snapshot().Turns()
+}
+
+// Complete reports whether the last turn completed the interview
+func (s *PreviewSession) Complete() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.states) > 0 && s.states[len(s.states)-1].complete
+}
+
+// Rewind undoes the last n turns, rewinding past the first turn resets the session
+func (s *PreviewSession) Rewind(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > len(s.states) {
+		n = len(s.states)
+	}
+	if n > 0 {
+		s.states = s.states[:len(s.states)-n]
+	}
+}
+
+// Snapshot saves the session's state so it can be restored after exploring other answers
+func (s *PreviewSession) Snapshot() PreviewSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return PreviewSnapshot{states: append([]previewState(nil), s.states...)}
+}
+
+// Restore returns the session to a snapshot taken from it or any other session of the same tree
+func (s *PreviewSession) Restore(snapshot PreviewSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states = append([]previewState(nil), snapshot.states...)
+}
+
+// Fork returns a new session starting from this session's current state, turns in either session
+// don't affect the other
+func (s *PreviewSession) Fork() *PreviewSession {
+	fork := s.client.NewPreviewSession(s.docTree)
+	fork.Restore(s.Snapshot())
+	return fork
+}
+
+// Document gets the preview document for the variables collected so far, the caller must close it
+func (s *PreviewSession) Document(ctx context.Context, document *Document, callOpts ...CallOption) (io.ReadCloser, error) {
+	return s.client.getPreviewDoc(withCallOptions(ctx, callOpts), s.Variables(), document)
+}
+
+func (s *PreviewSession) variables() map[string]interface{} {
+	if len(s.states) == 0 {
+		return map[string]interface{}{}
+	}
+	return s.states[len(s.states)-1].variables
+}
+
+// copyVariables returns a shallow copy of variables
+func copyVariables(variables map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		copied[name] = value
+	}
+	return copied
+}
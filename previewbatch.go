@@ -0,0 +1,184 @@
+package docubotlib
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultPreviewConcurrency is the number of previews RenderPreviews generates at once when its concurrency
+// isn't set, previews are slow to generate and light to send so more of them are in flight than other helpers'
+const defaultPreviewConcurrency int = 8
+
+// PreviewBatchOptions configures RenderPreviews
+type PreviewBatchOptions struct {
+	// Concurrency is the number of previews generated at once, 8 when zero. Workers pause while the account's
+	// rate limit is exhausted instead of failing.
+	Concurrency int
+	// OnProgress is called after every preview is generated or fails
+	OnProgress func(PreviewBatchProgress)
+	// OnDocument, when set, receives every generated pdf, such as to store it for review. Its error fails the
+	// preview. Pdfs aren't kept otherwise.
+	OnDocument func(index int, pdf []byte) error
+}
+
+// PreviewBatchProgress is reported after every preview of a RenderPreviews run
+type PreviewBatchProgress struct {
+	// Done counts the previews generated or failed so far, Failed the ones that failed
+	Done    int
+	Failed  int
+	Total   int
+	Elapsed time.Duration
+}
+
+// PreviewResult is the outcome of generating the preview of one variable set
+type PreviewResult struct {
+	// Index is the variable set's index in the variable sets passed to RenderPreviews
+	Index int
+	// Size is the size of the pdf in bytes
+	Size int
+	// Checksum is a hash of the pdf's text, so previews with the same text match whatever metadata the pdf
+	// carries, such as its creation time. It is empty when the text couldn't be extracted.
+	Checksum string
+	Duration time.Duration
+	Err      error
+}
+
+// PreviewBatchReport aggregates the results of a RenderPreviews run
+type PreviewBatchReport struct {
+	// Results are in the order of the variable sets
+	Results   []PreviewResult
+	Succeeded int
+	Failed    int
+	// Bytes is the size of every generated pdf together
+	Bytes   int
+	Elapsed time.Duration
+	// P50, P95, and Max are the durations of the generated previews
+	P50 time.Duration
+	P95 time.Duration
+	Max time.Duration
+	// Errors counts the failures by error message
+	Errors map[string]int
+}
+
+// RenderPreviews generates preview documents of document for many variable sets at once, such as to regression
+// test a template change against historical intakes. A failure for one variable set doesn't stop the others,
+// variable sets not yet started when ctx is done fail with its error. Compare the report with a run from before
+// the change with Changed.
+func (c *Client) RenderPreviews(ctx context.Context, document *Document, variableSets []map[string]interface{}, opts PreviewBatchOptions, callOpts ...CallOption) *PreviewBatchReport {
+	ctx = withCallOptions(ctx, callOpts)
+	start := time.Now()
+	report := &PreviewBatchReport{Results: make([]PreviewResult, len(variableSets)), Errors: map[string]int{}}
+	for i := range report.Results {
+		report.Results[i] = PreviewResult{Index: i, Err: context.Canceled}
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultPreviewConcurrency
+	}
+	var mu sync.Mutex
+	progress := PreviewBatchProgress{Total: len(variableSets)}
+	finished := func(result PreviewResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		report.Results[result.Index] = result
+		progress.Done++
+		if result.Err != nil {
+			progress.Failed++
+		}
+		progress.Elapsed = time.Since(start)
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+	c.runPool(ctx, concurrency, len(variableSets), func(i int) {
+		finished(c.renderPreview(ctx, i, document, variableSets[i], opts))
+	}, func(i int, err error) {
+		c.reportError(err)
+		finished(PreviewResult{Index: i, Err: err})
+	})
+	if err := ctx.Err(); err != nil {
+		for i := range report.Results {
+			if report.Results[i].Err == context.Canceled {
+				report.Results[i].Err = err
+			}
+		}
+	}
+	report.Elapsed = time.Since(start)
+	report.summarize()
+	return report
+}
+
+func (c *Client) renderPreview(ctx context.Context, i int, document *Document, variables map[string]interface{}, opts PreviewBatchOptions) PreviewResult {
+	result := PreviewResult{Index: i}
+	if status := c.RateLimitStatus(); status.Exhausted() {
+		if err := sleep(ctx, time.Until(status.Reset)); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	start := time.Now()
+	pdf, err := readBody(c.getPreviewDoc(ctx, variables, document))
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Size = len(pdf)
+	if lines, err := pdfTextLines(pdf); err == nil {
+		sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+		result.Checksum = hex.EncodeToString(sum[:])
+	}
+	if opts.OnDocument != nil {
+		result.Err = opts.OnDocument(i, pdf)
+	}
+	return result
+}
+
+// summarize totals the results
+func (r *PreviewBatchReport) summarize() {
+	var durations []time.Duration
+	for _, result := range r.Results {
+		if result.Err != nil {
+			r.Failed++
+			r.Errors[result.Err.Error()]++
+			continue
+		}
+		r.Succeeded++
+		r.Bytes += result.Size
+		durations = append(durations, result.Duration)
+	}
+	if len(durations) == 0 {
+		return
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	r.P50 = durations[(len(durations)-1)*50/100]
+	r.P95 = durations[(len(durations)-1)*95/100]
+	r.Max = durations[len(durations)-1]
+}
+
+// Changed returns the indexes of the variable sets whose preview text differs from the baseline's, a run of the
+// same variable sets from before a template change. Variable sets that failed or lack a checksum in either run
+// aren't compared.
+func (r *PreviewBatchReport) Changed(baseline *PreviewBatchReport) []int {
+	var changed []int
+	for i, result := range r.Results {
+		if i >= len(baseline.Results) {
+			break
+		}
+		before := baseline.Results[i]
+		if result.Err != nil || before.Err != nil || result.Checksum == "" || before.Checksum == "" {
+			continue
+		}
+		if result.Checksum != before.Checksum {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,99 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// previewLatency is how long the fake preview server takes to generate a document
+const previewLatency time.Duration = 2 * time.Millisecond
+
+// previewServer answers preview document requests with a pdf writing a line per variable
+func previewServer(b *testing.B) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		time.Sleep(previewLatency)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(textPDF(body.Variables))
+	}))
+	b.Cleanup(server.Close)
+	return server
+}
+
+// textPDF writes a one page pdf with a line of text for each variable
+func textPDF(variables map[string]interface{}) []byte {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var content strings.Builder
+	content.WriteString("BT /F1 10 Tf 14 TL 72 720 Td\n")
+	for _, name := range names {
+		fmt.Fprintf(&content, "(%v: %v) Tj T*\n", name, variables[name])
+	}
+	content.WriteString("ET")
+	return []byte(fmt.Sprintf(
+		"%%PDF-1.4\n"+
+			"1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n"+
+			"2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj\n"+
+			"3 0 obj << /Type /Page /Parent 2 0 R /Contents 4 0 R >> endobj\n"+
+			"4 0 obj << /Length %v >>\nstream\n%v\nendstream\nendobj\n%%%%EOF\n",
+		content.Len(), content.String(),
+	))
+}
+
+func previewVariableSets(sets int, variables int) []map[string]interface{} {
+	variableSets := make([]map[string]interface{}, sets)
+	for i := range variableSets {
+		variableSets[i] = map[string]interface{}{}
+		for j := 0; j < variables; j++ {
+			variableSets[i][fmt.Sprintf("variable_%v", j)] = fmt.Sprintf("answer %v of intake %v", j, i)
+		}
+	}
+	return variableSets
+}
+
+func BenchmarkRenderPreviews(b *testing.B) {
+	document := &Document{BodyHTML: "<p>{{variable_0}}</p>"}
+	for _, sets := range []int{16, 128} {
+		for _, variables := range []int{10, 200} {
+			for _, concurrency := range []int{1, 4, 8, 16} {
+				name := fmt.Sprintf("sets=%v/variables=%v/concurrency=%v", sets, variables, concurrency)
+				b.Run(name, func(b *testing.B) {
+					c := NewClient(previewServer(b).URL, "key", "secret")
+					variableSets := previewVariableSets(sets, variables)
+					opts := PreviewBatchOptions{Concurrency: concurrency}
+					b.ResetTimer()
+					start := time.Now()
+					var bytes int
+					for i := 0; i < b.N; i++ {
+						report := c.RenderPreviews(context.Background(), document, variableSets, opts)
+						if report.Failed > 0 {
+							b.Fatalf("%v previews failed: %v", report.Failed, report.Errors)
+						}
+						if report.Results[0].Checksum == "" {
+							b.Fatal("preview text wasn't extracted")
+						}
+						bytes += report.Bytes
+					}
+					b.ReportMetric(float64(sets*b.N)/time.Since(start).Seconds(), "previews/s")
+					b.ReportMetric(float64(bytes)/float64(b.N), "pdf-bytes/op")
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package docubotlib
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Diff operations of a DiffLine
+const (
+	DiffEqual  string = " "
+	DiffDelete string = "-"
+	DiffInsert string = "+"
+)
+
+// pdfLineTolerance is how far apart, in points, text runs can be vertically and still be on the same line
+const pdfLineTolerance float64 = 2
+
+// DiffLine is a line of text in a diff, deleted lines are only in the old text and inserted lines only in the new
+type DiffLine struct {
+	Op   string
+	Text string
+}
+
+// PreviewDiff compares a thread's last generated document with a preview of it regenerated from the current variables
+type PreviewDiff struct {
+	// Lines is a line diff of the text of the generated document against the text of the preview
+	Lines []DiffLine
+	// Generated and Preview are the pdfs compared, for a side by side visual comparison
+	Generated []byte
+	Preview   []byte
+	// Variables are the variables the preview was generated from
+	Variables map[string]interface{}
+}
+
+// Changed reports whether regenerating the document would change its text
+func (d *PreviewDiff) Changed() bool {
+	for _, line := range d.Lines {
+		if line.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// String writes the diff with each line prefixed by its operation
+func (d *PreviewDiff) String() string {
+	var b strings.Builder
+	for _, line := range d.Lines {
+		b.WriteString(line.Op + " " + line.Text + "\n")
+	}
+	return b.String()
+}
+
+// DiffPreview previews the thread's document regenerated with the thread's current variables and the document
+// template, and diffs it against the document last generated for the thread, so a regeneration can be reviewed
+// before it happens. Only text written with simple fonts is compared, as with FindPDFAnchors.
+func (c *Client) DiffPreview(ctx context.Context, thread string, user string, document *Document, callOpts ...CallOption) (*PreviewDiff, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	variables, err := c.getDocubotVariables(ctx, thread, user)
+	if err != nil {
+		return nil, err
+	}
+	diff := &PreviewDiff{Variables: variables.Data.Variables}
+	if diff.Generated, err = readBody(c.getDocubotDoc(ctx, thread, user)); err != nil {
+		return nil, err
+	}
+	if diff.Preview, err = readBody(c.getPreviewDoc(ctx, diff.Variables, document)); err != nil {
+		return nil, err
+	}
+	generated, err := pdfTextLines(diff.Generated)
+	if err != nil {
+		return nil, err
+	}
+	preview, err := pdfTextLines(diff.Preview)
+	if err != nil {
+		return nil, err
+	}
+	diff.Lines = diffLines(generated, preview)
+	return diff, nil
+}
+
+// readBody reads and closes a response body
+func readBody(body io.ReadCloser, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// pdfTextLines returns the lines of text of every page of a pdf, top to bottom
+func pdfTextLines(data []byte) ([]string, error) {
+	doc, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, page := range doc.pages() {
+		runs := doc.textRuns(page)
+		sort.SliceStable(runs, func(i, j int) bool {
+			return runs[i].y > runs[j].y
+		})
+		for start := 0; start < len(runs); {
+			end := start + 1
+			for end < len(runs) && runs[end-1].y-runs[end].y <= pdfLineTolerance {
+				end++
+			}
+			line := runs[start:end]
+			sort.SliceStable(line, func(i, j int) bool {
+				return line[i].x < line[j].x
+			})
+			var text strings.Builder
+			for _, run := range line {
+				text.WriteString(run.text)
+			}
+			if t := strings.TrimSpace(text.String()); t != "" {
+				lines = append(lines, t)
+			}
+			start = end
+		}
+	}
+	return lines, nil
+}
+
+// diffLines returns a shortest line diff turning a into b, from their longest common subsequence
+func diffLines(a []string, b []string) []DiffLine {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	var diff []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: b[j]})
+	}
+	return diff
+}
@@ -0,0 +1,186 @@
+package docubotlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultPreviewPollInterval is how often a TemplatePreview checks its files when PollInterval isn't set
+const defaultPreviewPollInterval time.Duration = 500 * time.Millisecond
+
+// templatePreviewReload reloads the page when the server reports a change to the template files
+const templatePreviewReload string = `<script>new EventSource("?events").onmessage = function () { location.reload() }</script>`
+
+// TemplatePreview is an http.Handler previewing a Document template being edited on disk. It renders the files
+// with the local renderer, so no api calls are made, and browsers showing the preview reload as soon as a file
+// changes. A file that is left empty keeps the Document's html. It is meant for template authors, not production.
+type TemplatePreview struct {
+	// Document holds the template's sections and their conditions, an empty document when nil
+	Document *Document
+	// HeaderFile, BodyFile, and FooterFile are the paths of the template's html
+	HeaderFile string
+	BodyFile   string
+	FooterFile string
+	// StyleFile is the path of the template's css, it is added to the page's head
+	StyleFile string
+	// SectionFiles maps section names to the paths of their html
+	SectionFiles map[string]string
+	// Variables are the answers the template is rendered with, VariablesFile, a json object, replaces them when set
+	Variables     map[string]interface{}
+	VariablesFile string
+	// Options customizes the rendering, such as the locale dates and numbers are written in
+	Options RenderOptions
+	// PollInterval is how often the files are checked for changes, half a second when zero
+	PollInterval time.Duration
+}
+
+// ServeHTTP renders the preview page, or streams change notifications to it when the query has "events"
+func (p *TemplatePreview) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["events"]; ok {
+		p.serveEvents(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	page, err := p.render()
+	if err != nil {
+		// the error is shown until the files are fixed, the page still reloads on changes
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "<!DOCTYPE html><html><head><meta charset=\"utf-8\"></head><body><pre>%v</pre>%v</body></html>", html.EscapeString(err.Error()), templatePreviewReload)
+		return
+	}
+	fmt.Fprint(w, page)
+}
+
+// render reads the files and renders the page
+func (p *TemplatePreview) render() (string, error) {
+	document := Document{}
+	if p.Document != nil {
+		document = *p.Document
+		document.Sections = append([]DocumentSection(nil), p.Document.Sections...)
+	}
+	for _, file := range []struct {
+		path string
+		html *string
+	}{
+		{p.HeaderFile, &document.HeaderHTML},
+		{p.BodyFile, &document.BodyHTML},
+		{p.FooterFile, &document.FooterHTML},
+	} {
+		if err := readPreviewFile(file.path, file.html); err != nil {
+			return "", err
+		}
+	}
+	for name, path := range p.SectionFiles {
+		section := DocumentSection{Name: name}
+		if existing := document.Section(name); existing != nil {
+			section = *existing
+		}
+		if err := readPreviewFile(path, &section.HTML); err != nil {
+			return "", err
+		}
+		document.SetSection(section)
+	}
+	var style string
+	if err := readPreviewFile(p.StyleFile, &style); err != nil {
+		return "", err
+	}
+	variables := p.Variables
+	if p.VariablesFile != "" {
+		b, err := os.ReadFile(p.VariablesFile)
+		if err != nil {
+			return "", err
+		}
+		if err := json.Unmarshal(b, &variables); err != nil {
+			return "", fmt.Errorf("docubot: reading %v: %w", p.VariablesFile, err)
+		}
+	}
+	rendered, err := RenderDocumentWithOptions(&document, variables, p.Options)
+	if err != nil {
+		return "", err
+	}
+	var page strings.Builder
+	page.WriteString("<!DOCTYPE html><html><head><meta charset=\"utf-8\">")
+	if style != "" {
+		page.WriteString("<style>" + style + "</style>")
+	}
+	page.WriteString("</head><body>")
+	page.WriteString("<header>" + rendered.HeaderHTML + "</header>")
+	page.WriteString("<main>" + rendered.BodyHTML + "</main>")
+	page.WriteString("<footer>" + rendered.FooterHTML + "</footer>")
+	page.WriteString(templatePreviewReload)
+	page.WriteString("</body></html>")
+	return page.String(), nil
+}
+
+// readPreviewFile reads the file at path into s, it leaves s alone when path is empty
+func readPreviewFile(path string, s *string) error {
+	if path == "" {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	*s = string(b)
+	return nil
+}
+
+// serveEvents sends a server-sent event every time the files change, until the browser goes away
+func (p *TemplatePreview) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	interval := p.PollInterval
+	if interval <= 0 {
+		interval = defaultPreviewPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := p.fileState()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+		if state := p.fileState(); state != last {
+			last = state
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// fileState describes the size and modification time of every file, it changes when any of them is written
+func (p *TemplatePreview) fileState() string {
+	paths := []string{p.HeaderFile, p.BodyFile, p.FooterFile, p.StyleFile, p.VariablesFile}
+	for _, path := range p.SectionFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var state strings.Builder
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&state, "%v:%v:%v;", path, info.Size(), info.ModTime().UnixNano())
+		} else {
+			fmt.Fprintf(&state, "%v:missing;", path)
+		}
+	}
+	return state.String()
+}
@@ -0,0 +1,102 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+)
+
+// deleteUserDataBatchSize is the number of threads fetched per page while deleting user data
+const deleteUserDataBatchSize int = 50
+
+// UserDataDeletionReport describes what DeleteUserData removed
+type UserDataDeletionReport struct {
+	UserID string
+	// Threads are the IDs of the deleted threads
+	Threads []string
+	// Variables are the IDs of the threads whose variables were deleted
+	Variables []string
+	// Documents are the IDs of the threads whose documents were deleted
+	Documents []string
+	// Failures holds the threads that could not be fully deleted
+	Failures []ThreadDeletionFailure
+}
+
+// ThreadDeletionFailure records a thread that DeleteUserData could not fully delete
+type ThreadDeletionFailure struct {
+	ThreadID string
+	Err      error
+}
+
+// DeleteUserData deletes every thread, variable, and document belonging to an end user across the account.
+// Threads are deleted in batches, a failure on one thread doesn't stop the others;
+// the returned report lists everything that was removed and everything that failed. In dry run mode the report
+// lists what would be removed.
+func (c *Client) DeleteUserData(ctx context.Context, userID string, callOpts ...CallOption) (*UserDataDeletionReport, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	report := &UserDataDeletionReport{UserID: userID}
+	failed := map[string]bool{}
+	// in dry run mode nothing is deleted, so the listing is paged through with its cursor instead
+	dryRun := c.resolveCallOptions(ctx).dryRun
+	cursor := ""
+	for {
+		// deleted threads drop out of the listing, so every page starts from the beginning
+		// and skips the threads that already failed
+		limit := deleteUserDataBatchSize + len(failed)
+		if dryRun {
+			limit = deleteUserDataBatchSize
+		}
+		page, err := c.ListThreads(ctx, ListThreadsOptions{
+			User:   userID,
+			Limit:  limit,
+			Cursor: cursor,
+		})
+		if err != nil {
+			return report, err
+		}
+		remaining := 0
+		for _, thread := range page.Data.Threads {
+			if failed[thread.ID] {
+				continue
+			}
+			remaining++
+			if err := c.deleteUserThread(ctx, thread, userID, report); err != nil {
+				failed[thread.ID] = true
+				report.Failures = append(report.Failures, ThreadDeletionFailure{ThreadID: thread.ID, Err: err})
+			}
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+		}
+		if dryRun {
+			if cursor = page.Meta.NextCursor; cursor == "" {
+				break
+			}
+			continue
+		}
+		if remaining == 0 || page.Meta.NextCursor == "" && len(page.Data.Threads) < limit {
+			break
+		}
+	}
+	if len(report.Failures) > 0 {
+		return report, fmt.Errorf("docubot: failed to delete %v of the user's threads", len(report.Failures))
+	}
+	return report, nil
+}
+
+func (c *Client) deleteUserThread(ctx context.Context, thread Thread, user string, report *UserDataDeletionReport) error {
+	if thread.HasDocument {
+		if err := c.DeleteDocubotDoc(ctx, thread.ID, user); err != nil {
+			return err
+		}
+		report.Documents = append(report.Documents, thread.ID)
+	}
+	if err := c.DeleteDocubotVariables(ctx, thread.ID, user); err != nil {
+		return err
+	}
+	report.Variables = append(report.Variables, thread.ID)
+	if err := c.DeleteThread(ctx, thread.ID, user); err != nil {
+		return err
+	}
+	report.Threads = append(report.Threads, thread.ID)
+	return nil
+}
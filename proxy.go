@@ -0,0 +1,118 @@
+package docubotlib
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// ProxiedDocument identifies the document a DocumentProxy request asks for
+type ProxiedDocument struct {
+	Thread string
+	User   string
+	// DocumentID is one of the documents generated in the thread, the thread's docubot document when empty
+	DocumentID string
+}
+
+// DocumentAccessLog describes a request served by a DocumentProxy
+type DocumentAccessLog struct {
+	Document   ProxiedDocument
+	RemoteAddr string
+	// Status is the status the proxy answered with, Bytes how much of the document was sent
+	Status   int
+	Bytes    int64
+	Duration time.Duration
+	// Err is why the request failed, nil when the document was sent
+	Err error
+	At  time.Time
+}
+
+// DocumentProxy is an http.Handler serving documents generated by docubot from our own domain, streaming
+// them from docubot so client facing links never expose docubot's infrastructure
+type DocumentProxy struct {
+	Client *Client
+	// Resolve returns the document the request asks for, such as by looking up a token in its path.
+	// The thread, user, and document query parameters are used when nil.
+	Resolve func(r *http.Request) (ProxiedDocument, error)
+	// Authorize decides whether the request may download the document, every request is refused when nil
+	Authorize func(r *http.Request, document ProxiedDocument) error
+	// OnAccess, when set, is called once for every request
+	OnAccess func(DocumentAccessLog)
+}
+
+// NewDocumentProxy initializes a proxy serving the documents authorize allows
+func NewDocumentProxy(client *Client, authorize func(r *http.Request, document ProxiedDocument) error) *DocumentProxy {
+	return &DocumentProxy{Client: client, Authorize: authorize}
+}
+
+// ServeHTTP streams the requested document to the response
+func (p *DocumentProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	entry := DocumentAccessLog{RemoteAddr: r.RemoteAddr, At: time.Now()}
+	entry.Status, entry.Bytes, entry.Err = p.serve(w, r, &entry.Document)
+	entry.Duration = time.Since(entry.At)
+	// a failure once the document started streaming can't change the status anymore
+	if entry.Err != nil && entry.Status != http.StatusOK {
+		http.Error(w, http.StatusText(entry.Status), entry.Status)
+	}
+	if p.OnAccess != nil {
+		p.OnAccess(entry)
+	}
+}
+
+func (p *DocumentProxy) serve(w http.ResponseWriter, r *http.Request, document *ProxiedDocument) (int, int64, error) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		return http.StatusMethodNotAllowed, 0, errors.New("docubot: documents are only proxied for GET requests")
+	}
+	var err error
+	*document, err = p.resolve(r)
+	if err != nil {
+		return http.StatusNotFound, 0, err
+	}
+	if p.Authorize == nil {
+		return http.StatusForbidden, 0, errors.New("docubot: document proxy has no Authorize callback")
+	}
+	if err := p.Authorize(r, *document); err != nil {
+		return http.StatusForbidden, 0, err
+	}
+	var download *DocumentDownload
+	if document.DocumentID == "" {
+		download, err = p.Client.DownloadDocubotDoc(r.Context(), document.Thread, document.User)
+	} else {
+		download, err = p.Client.DownloadThreadDocument(r.Context(), document.Thread, document.User, document.DocumentID)
+	}
+	if err != nil {
+		var apiError *APIError
+		if errors.As(err, &apiError) && apiError.StatusCode == http.StatusNotFound {
+			return http.StatusNotFound, 0, err
+		}
+		return http.StatusBadGateway, 0, err
+	}
+	defer download.Body.Close()
+	filename := download.Filename
+	if filename == "" {
+		filename = "document" + download.Extension
+	}
+	w.Header().Set("Content-Type", download.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == "HEAD" {
+		return http.StatusOK, 0, nil
+	}
+	n, err := io.Copy(w, download.Body)
+	return http.StatusOK, n, err
+}
+
+func (p *DocumentProxy) resolve(r *http.Request) (ProxiedDocument, error) {
+	if p.Resolve != nil {
+		return p.Resolve(r)
+	}
+	query := r.URL.Query()
+	document := ProxiedDocument{Thread: query.Get("thread"), User: query.Get("user"), DocumentID: query.Get("document")}
+	if document.Thread == "" || document.User == "" {
+		return document, errors.New("docubot: document request is missing its thread or user")
+	}
+	return document, nil
+}
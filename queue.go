@@ -0,0 +1,260 @@
+package docubotlib
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Defaults used by NewMessageQueue
+const (
+	defaultQueueMaxAttempts int           = 5
+	defaultQueueBackoff     time.Duration = 500 * time.Millisecond
+	maxQueueBackoff         time.Duration = 30 * time.Second
+)
+
+// ErrEarlierMessageFailed is returned for queued messages that were not sent because an earlier
+// message for the same thread failed or was canceled, sending them would answer the wrong questions
+var ErrEarlierMessageFailed = errors.New("docubot: an earlier message for the thread failed")
+
+// MessageQueue sends messages so that messages for one thread are delivered in the order they were queued,
+// even when they are queued concurrently. Failed sends are retried before any later message for the thread
+// is sent, and every retry carries the same idempotency key so docubot never records an answer twice.
+type MessageQueue struct {
+	// MaxAttempts is the number of times a message is sent before giving up
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it doubles with every retry
+	Backoff time.Duration
+	// OnError, when set, receives the error of every failed send attempt, including ones that are retried,
+	// the client's OnError is used when it is nil
+	OnError func(error)
+
+	client  *Client
+	mu      sync.Mutex
+	threads map[string][]*queuedMessage
+	closed  bool
+	drains  sync.WaitGroup
+}
+
+// QueueError is reported to OnError when an attempt to send a queued message fails
+type QueueError struct {
+	Thread string
+	// Attempt is the number of the failed attempt, starting at 1
+	Attempt int
+	Err     error
+}
+
+func (e *QueueError) Error() string {
+	return fmt.Sprintf("docubot: queued message for thread %v failed on attempt %v: %v", e.Thread, e.Attempt, e.Err)
+}
+
+// Unwrap returns the error of the failed attempt
+func (e *QueueError) Unwrap() error {
+	return e.Err
+}
+
+type queuedMessage struct {
+	ctx       context.Context
+	message   string
+	thread    string
+	sender    string
+	docTreeID string
+	key       string
+	done      chan queuedResult
+}
+
+type queuedResult struct {
+	response *MessageResponse
+	err      error
+}
+
+// NewMessageQueue initializes a message queue that sends with client
+func NewMessageQueue(client *Client) *MessageQueue {
+	return &MessageQueue{
+		MaxAttempts: defaultQueueMaxAttempts,
+		Backoff:     defaultQueueBackoff,
+		client:      client,
+		threads:     map[string][]*queuedMessage{},
+	}
+}
+
+// Send queues a message and waits for docubot's response.
+// If ctx is done before the message is sent, Send returns ctx's error and the message is skipped once its turn
+// comes, which fails the thread's later messages with ErrEarlierMessageFailed. A message whose ctx is done while
+// it is being sent may still have been recorded by docubot.
+// Once the queue or its client is shut down, messages aren't queued and fail with ErrClosed.
+func (q *MessageQueue) Send(ctx context.Context, message string, thread string, sender string, docTreeID string) (*MessageResponse, error) {
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	m := &queuedMessage{
+		ctx:       ctx,
+		message:   message,
+		thread:    thread,
+		sender:    sender,
+		docTreeID: docTreeID,
+		key:       key,
+		done:      make(chan queuedResult, 1),
+	}
+	q.mu.Lock()
+	pending := q.threads[thread]
+	if q.closed || (len(pending) == 0 && !q.startDrain(thread)) {
+		q.mu.Unlock()
+		return nil, ErrClosed
+	}
+	q.threads[thread] = append(pending, m)
+	q.mu.Unlock()
+	select {
+	case result := <-m.done:
+		return result.response, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Pending returns the number of messages waiting to be sent for the thread
+func (q *MessageQueue) Pending(thread string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.threads[thread])
+}
+
+// Shutdown stops the queue taking messages and waits until the messages already queued are sent, or until ctx is
+// done. Client.Shutdown waits for the queued messages of every queue of the client.
+func (q *MessageQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	drained := make(chan struct{})
+	go func() {
+		q.drains.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close shuts the queue down like Shutdown, waiting for as long as the queued messages take
+func (q *MessageQueue) Close() error {
+	return q.Shutdown(context.Background())
+}
+
+// startDrain starts draining the thread's messages in the background of the client, q.mu must be held.
+// It reports false once the client is shut down.
+func (q *MessageQueue) startDrain(thread string) bool {
+	q.drains.Add(1)
+	started := q.client.background(context.Background(), false, func(context.Context) {
+		defer q.drains.Done()
+		q.drain(thread)
+	})
+	if !started {
+		q.drains.Done()
+	}
+	return started
+}
+
+// drain sends the thread's messages one at a time until none are left
+func (q *MessageQueue) drain(thread string) {
+	for {
+		q.mu.Lock()
+		pending := q.threads[thread]
+		if len(pending) == 0 {
+			delete(q.threads, thread)
+			q.mu.Unlock()
+			return
+		}
+		m := pending[0]
+		q.mu.Unlock()
+
+		response, err := q.sendRecovered(m)
+		m.done <- queuedResult{response: response, err: err}
+
+		q.mu.Lock()
+		remaining := q.threads[thread][1:]
+		if err != nil {
+			for _, later := range remaining {
+				later.done <- queuedResult{err: fmt.Errorf("%w: %v", ErrEarlierMessageFailed, err)}
+			}
+			remaining = nil
+		}
+		q.threads[thread] = remaining
+		q.mu.Unlock()
+	}
+}
+
+// sendRecovered sends the message, failing it with a *PanicError if sending panics so the thread's queue keeps draining
+func (q *MessageQueue) sendRecovered(m *queuedMessage) (*MessageResponse, error) {
+	var response *MessageResponse
+	err := q.client.recoverCall("message queue", func() error {
+		var err error
+		response, err = q.send(m)
+		return err
+	})
+	return response, err
+}
+
+func (q *MessageQueue) send(m *queuedMessage) (*MessageResponse, error) {
+	backoff := q.Backoff
+	var err error
+	for attempt := 0; attempt < q.maxAttempts(); attempt++ {
+		if err := m.ctx.Err(); err != nil {
+			return nil, err
+		}
+		var response *MessageResponse
+		response, err = q.client.sendMessage(m.ctx, m.message, m.thread, m.sender, m.docTreeID, "", m.key)
+		if err == nil {
+			return response, nil
+		}
+		q.reportError(&QueueError{Thread: m.thread, Attempt: attempt + 1, Err: err})
+		if !IsRetryable(err) {
+			return response, err
+		}
+		wait := backoff
+		var apiError *APIError
+		if errors.As(err, &apiError) && apiError.RateLimited() && apiError.RateLimit.Reset.After(time.Now()) {
+			wait = time.Until(apiError.RateLimit.Reset)
+		}
+		select {
+		case <-time.After(wait):
+		case <-m.ctx.Done():
+			return nil, m.ctx.Err()
+		}
+		if backoff *= 2; backoff > maxQueueBackoff {
+			backoff = maxQueueBackoff
+		}
+	}
+	return nil, err
+}
+
+func (q *MessageQueue) reportError(err error) {
+	if q.OnError != nil {
+		q.OnError(err)
+		return
+	}
+	q.client.reportError(err)
+}
+
+func (q *MessageQueue) maxAttempts() int {
+	if q.MaxAttempts < 1 {
+		return 1
+	}
+	return q.MaxAttempts
+}
+
+// newIdempotencyKey returns a random key identifying one logical request across retries
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
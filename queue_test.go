@@ -0,0 +1,67 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// waitPending waits until n messages are queued for the thread
+func waitPending(q *MessageQueue, thread string, n int) {
+	for q.Pending(thread) != n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueueCanceledMessage(t *testing.T) {
+	release := make(chan struct{})
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		sent++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"messages":["Next"]}}`))
+	}))
+	defer server.Close()
+	q := NewMessageQueue(NewClient(server.URL, "key", "secret"))
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := q.Send(context.Background(), "first", "thread_1", "user", "tree")
+		first <- err
+	}()
+	waitPending(q, "thread_1", 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		_, err := q.Send(ctx, "second", "thread_1", "user", "tree")
+		canceled <- err
+	}()
+	waitPending(q, "thread_1", 2)
+	later := make(chan error, 1)
+	go func() {
+		_, err := q.Send(context.Background(), "third", "thread_1", "user", "tree")
+		later <- err
+	}()
+	waitPending(q, "thread_1", 3)
+	cancel()
+	if err := <-canceled; err != context.Canceled {
+		t.Fatalf("got %v for the canceled message, want context.Canceled", err)
+	}
+	close(release)
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-later; !errors.Is(err, ErrEarlierMessageFailed) {
+		t.Errorf("got %v for the message after the canceled one, want ErrEarlierMessageFailed", err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 {
+		t.Errorf("docubot got %v messages, want only the first", sent)
+	}
+}
@@ -0,0 +1,15 @@
+package docubotlib
+
+import (
+	"errors"
+)
+
+// ErrReadOnly is returned by calls that would change what docubot stores when made in read only mode
+var ErrReadOnly = errors.New("docubot: client is read only")
+
+// WithReadOnly makes a call in read only mode, see Client.ReadOnly
+func WithReadOnly() CallOption {
+	return func(o *callOptions) {
+		o.readOnly = true
+	}
+}
@@ -0,0 +1,113 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+)
+
+// ArchivedLabel labels the threads a ThreadReaper archived, the reaper skips them afterwards
+const ArchivedLabel string = "archived"
+
+// ThreadReaper closes unfinished threads that have been inactive for too long, run it from a cron job. Each stale
+// thread's partial answers are exported, it is given a final reminder, and it is deleted, or archived with
+// ArchivedLabel. A thread that fails a step is left alone and reported, the next run tries it again.
+type ThreadReaper struct {
+	Client *Client
+	// InactiveFor is how long an unfinished thread can go without a message before it is reaped
+	InactiveFor time.Duration
+	// Threads filters the threads considered, such as to one tree
+	Threads ListThreadsOptions
+	// FinalReminder, when set, is called before a thread is reaped, such as to tell its user the interview is
+	// being closed. A reminder that fails keeps the thread for the next run.
+	FinalReminder func(ctx context.Context, thread Thread) error
+	// Export, when set, stores a ThreadSnapshot of each thread under its ID and ".json" before it is reaped,
+	// RestoreThread brings it back
+	Export DocumentStore
+	// Archive labels threads with ArchivedLabel instead of deleting them
+	Archive bool
+}
+
+// ReapReport describes what a ThreadReaper did
+type ReapReport struct {
+	// Reaped are the IDs of the threads deleted or archived, Exported the ones whose snapshot was stored
+	Reaped   []string
+	Exported []string
+	// Failed maps the IDs of the threads that couldn't be reaped to why
+	Failed map[string]error
+}
+
+// Stale lists the threads a run would reap
+func (r *ThreadReaper) Stale(ctx context.Context, callOpts ...CallOption) ([]Thread, error) {
+	threads, err := r.Client.ListStaleThreads(ctx, r.InactiveFor, r.Threads, callOpts...)
+	if err != nil {
+		return nil, err
+	}
+	stale := threads[:0]
+	for _, thread := range threads {
+		if !containsString(thread.Labels, ArchivedLabel) {
+			stale = append(stale, thread)
+		}
+	}
+	return stale, nil
+}
+
+// Run reaps every stale thread. A failed thread doesn't stop the others, it is reported in ReapReport.Failed
+// and to OnError, the error is for listing the threads or when any thread failed.
+func (r *ThreadReaper) Run(ctx context.Context, callOpts ...CallOption) (*ReapReport, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	if r.InactiveFor <= 0 {
+		return nil, fmt.Errorf("docubot: reaper needs a positive InactiveFor, got %v", r.InactiveFor)
+	}
+	threads, err := r.Stale(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &ReapReport{Failed: map[string]error{}}
+	for _, thread := range threads {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if err := r.reap(ctx, thread, report); err != nil {
+			report.Failed[thread.ID] = err
+			r.Client.reportError(fmt.Errorf("docubot: reaping thread %v: %w", thread.ID, err))
+		}
+	}
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("docubot: failed to reap %v of %v stale threads", len(report.Failed), len(threads))
+	}
+	return report, nil
+}
+
+func (r *ThreadReaper) reap(ctx context.Context, thread Thread, report *ReapReport) error {
+	if r.Export != nil {
+		snapshot, err := r.Client.SnapshotThread(ctx, thread.ID, thread.UserID)
+		if err != nil {
+			return err
+		}
+		var b bytes.Buffer
+		if _, err := snapshot.WriteTo(&b); err != nil {
+			return err
+		}
+		if err := r.Export.Put(ctx, thread.ID+".json", &b); err != nil {
+			return err
+		}
+		report.Exported = append(report.Exported, thread.ID)
+	}
+	if r.FinalReminder != nil {
+		if err := r.FinalReminder(ctx, thread); err != nil {
+			return err
+		}
+	}
+	if r.Archive {
+		labels := append(append([]string(nil), thread.Labels...), ArchivedLabel)
+		if _, err := r.Client.UpdateThread(ctx, thread.ID, thread.UserID, ThreadUpdate{Labels: labels}); err != nil {
+			return err
+		}
+	} else if err := r.Client.DeleteThread(ctx, thread.ID, thread.UserID); err != nil {
+		return err
+	}
+	report.Reaped = append(report.Reaped, thread.ID)
+	return nil
+}
@@ -0,0 +1,137 @@
+package docubotlib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const redactedValue string = "[REDACTED]"
+
+// DefaultSensitiveEntityTypes are the entity types masked by NewRedactorFromTree when none are given
+var DefaultSensitiveEntityTypes = []string{"ssn", "dateOfBirth", "dob"}
+
+// DefaultRedactionPatterns catch sensitive values that appear in free text, such as social security numbers
+var DefaultRedactionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+}
+
+// Redactor masks sensitive variables in logs, debug dumps, exports, and error messages.
+// A nil Redactor is valid and leaves everything untouched.
+type Redactor struct {
+	// Mask is the text substituted for a sensitive value, defaults to [REDACTED]
+	Mask string
+	// Patterns are additionally masked wherever they match in free text
+	Patterns  []*regexp.Regexp
+	sensitive map[string]bool
+	allowed   map[string]bool
+}
+
+// NewRedactor creates a redactor that masks the provided variables
+func NewRedactor(variables ...string) *Redactor {
+	r := &Redactor{
+		Patterns:  DefaultRedactionPatterns,
+		sensitive: map[string]bool{},
+	}
+	for _, v := range variables {
+		r.sensitive[v] = true
+	}
+	return r
+}
+
+// NewAllowlistRedactor creates a redactor that masks every variable except the provided ones
+func NewAllowlistRedactor(variables ...string) *Redactor {
+	r := &Redactor{
+		Patterns: DefaultRedactionPatterns,
+		allowed:  map[string]bool{},
+	}
+	for _, v := range variables {
+		r.allowed[v] = true
+	}
+	return r
+}
+
+// NewRedactorFromTree creates a redactor that masks every variable in the tree
+// whose entity type is one of entityTypes (DefaultSensitiveEntityTypes if none are given)
+func NewRedactorFromTree(tree *DocumentTree, entityTypes ...string) *Redactor {
+	if len(entityTypes) == 0 {
+		entityTypes = DefaultSensitiveEntityTypes
+	}
+	types := map[string]bool{}
+	for _, t := range entityTypes {
+		types[strings.ToLower(t)] = true
+	}
+	r := NewRedactor()
+	tree.Walk(func(node *QuestionNode) {
+		if types[strings.ToLower(node.EntityType)] {
+			r.sensitive[node.VariableName] = true
+		}
+	})
+	return r
+}
+
+// Add marks additional variables as sensitive
+func (r *Redactor) Add(variables ...string) {
+	if r.sensitive == nil {
+		r.sensitive = map[string]bool{}
+	}
+	for _, v := range variables {
+		r.sensitive[v] = true
+		delete(r.allowed, v)
+	}
+}
+
+// IsSensitive reports whether the variable should be masked
+func (r *Redactor) IsSensitive(variable string) bool {
+	if r == nil {
+		return false
+	}
+	if r.sensitive[variable] {
+		return true
+	}
+	return r.allowed != nil && !r.allowed[variable]
+}
+
+// RedactVariables returns a copy of variables with every sensitive value masked
+func (r *Redactor) RedactVariables(variables map[string]interface{}) map[string]interface{} {
+	if r == nil || variables == nil {
+		return variables
+	}
+	redacted := make(map[string]interface{}, len(variables))
+	for k, v := range variables {
+		if r.IsSensitive(k) {
+			redacted[k] = r.mask()
+			continue
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// RedactString masks the values of sensitive variables and any matching patterns found in s
+func (r *Redactor) RedactString(s string, variables map[string]interface{}) string {
+	if r == nil {
+		return s
+	}
+	for k, v := range variables {
+		if !r.IsSensitive(k) || v == nil {
+			continue
+		}
+		value := fmt.Sprintf("%v", v)
+		if value == "" {
+			continue
+		}
+		s = strings.ReplaceAll(s, value, r.mask())
+	}
+	for _, p := range r.Patterns {
+		s = p.ReplaceAllString(s, r.mask())
+	}
+	return s
+}
+
+func (r *Redactor) mask() string {
+	if r.Mask == "" {
+		return redactedValue
+	}
+	return r.Mask
+}
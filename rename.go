@@ -0,0 +1,125 @@
+package docubotlib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RenameReport lists what RenameVariable changed
+type RenameReport struct {
+	Old string
+	New string
+	// Questions is the number of questions that asked for the variable
+	Questions int
+	// QuestionConditions and SectionConditions are the number of conditions on the variable that were changed
+	QuestionConditions int
+	SectionConditions  int
+	// Placeholders are the placeholders that referenced the variable, as they were before the rename
+	Placeholders []Placeholder
+}
+
+// RenameVariable renames a variable in the tree's questions and conditions and in the document's placeholders
+// and section conditions. Either tree or document may be nil. Nothing is changed when an error is returned,
+// such as when the new name is already in use.
+func RenameVariable(tree *DocumentTree, document *Document, oldName string, newName string) (*RenameReport, error) {
+	if oldName == "" || newName == "" {
+		return nil, errors.New("docubot: variable names can't be empty")
+	}
+	if oldName == newName {
+		return nil, fmt.Errorf("docubot: variable %q is already named %q", oldName, newName)
+	}
+	if strings.ContainsAny(newName, "{}|") || strings.TrimSpace(newName) != newName ||
+		strings.HasPrefix(newName, sectionMarkerPrefix) || strings.HasPrefix(newName, filePlaceholderPrefix) ||
+		strings.HasPrefix(newName, partialPlaceholderPrefix) {
+		return nil, fmt.Errorf("docubot: %q can't be used as a variable name", newName)
+	}
+	used := variableUses(tree, document)
+	if used[newName] {
+		return nil, fmt.Errorf("docubot: variable %q is already in use", newName)
+	}
+	if !used[oldName] {
+		return nil, fmt.Errorf("docubot: variable %q isn't used by the tree or document", oldName)
+	}
+	report := &RenameReport{Old: oldName, New: newName}
+	tree.Walk(func(node *QuestionNode) {
+		if node.VariableName == oldName {
+			node.VariableName = newName
+			report.Questions++
+		}
+		report.QuestionConditions += renameConditions(node.Conditions, oldName, newName)
+	})
+	if document == nil {
+		return report, nil
+	}
+	document.HeaderHTML = report.renamePlaceholders("headerHtml", document.HeaderHTML)
+	document.BodyHTML = report.renamePlaceholders("bodyHtml", document.BodyHTML)
+	document.FooterHTML = report.renamePlaceholders("footerHtml", document.FooterHTML)
+	for i := range document.Sections {
+		section := &document.Sections[i]
+		section.HTML = report.renamePlaceholders(sectionMarkerPrefix+section.Name, section.HTML)
+		report.SectionConditions += renameConditions(section.Conditions, oldName, newName)
+	}
+	return report, nil
+}
+
+// variableUses returns every variable asked for, compared, or placed by the tree and document
+func variableUses(tree *DocumentTree, document *Document) map[string]bool {
+	used := map[string]bool{}
+	tree.Walk(func(node *QuestionNode) {
+		used[node.VariableName] = true
+		for _, condition := range node.Conditions {
+			used[condition.VariableName] = true
+		}
+	})
+	if document == nil {
+		return used
+	}
+	for _, placeholder := range ExtractPlaceholders(document) {
+		if placeholder.Kind != PlaceholderSection && placeholder.Kind != PlaceholderPartial {
+			used[placeholder.Name] = true
+		}
+	}
+	for _, section := range document.Sections {
+		for _, condition := range section.Conditions {
+			used[condition.VariableName] = true
+		}
+	}
+	delete(used, "")
+	return used
+}
+
+func renameConditions(conditions []QuestionCondition, oldName string, newName string) int {
+	renamed := 0
+	for i := range conditions {
+		if conditions[i].VariableName == oldName {
+			conditions[i].VariableName = newName
+			renamed++
+		}
+	}
+	return renamed
+}
+
+// renamePlaceholders rewrites the variable and file placeholders of the old variable in s, keeping their filters and spacing
+func (r *RenameReport) renamePlaceholders(location string, s string) string {
+	var b strings.Builder
+	last := 0
+	for _, placeholder := range extractPlaceholders(location, s) {
+		if placeholder.Kind == PlaceholderSection || placeholder.Kind == PlaceholderPartial || placeholder.Name != r.Old {
+			continue
+		}
+		prefix := ""
+		if placeholder.Kind == PlaceholderFile {
+			prefix = filePlaceholderPrefix
+		}
+		b.WriteString(s[last:placeholder.Offset])
+		b.WriteString(strings.Replace(placeholder.Raw, prefix+r.Old, prefix+r.New, 1))
+		last = placeholder.Offset + len(placeholder.Raw)
+		r.Placeholders = append(r.Placeholders, placeholder)
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package docubotlib
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// sectionMarkerPrefix starts placeholders that place a DocumentSection
+const sectionMarkerPrefix string = "section:"
+
+// placeholderPattern matches {{variableName}}, {{file:variableName}}, {{section:name}}, and {{partial:name}}
+// placeholders in document html
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// RenderedDocument is the html of a Document with its placeholders filled in
+type RenderedDocument struct {
+	HeaderHTML string
+	BodyHTML   string
+	FooterHTML string
+	// Report is which variables and sections went into the html
+	Report *RenderReport
+}
+
+// Variable returns the placeholder that renders the variable's value
+func Variable(name string) string {
+	return "{{" + name + "}}"
+}
+
+// SectionMarker returns the placeholder that renders the named section when its conditions hold
+func SectionMarker(name string) string {
+	return "{{" + sectionMarkerPrefix + name + "}}"
+}
+
+// Section returns the document's section with the name, nil if there isn't one
+func (d *Document) Section(name string) *DocumentSection {
+	for i := range d.Sections {
+		if d.Sections[i].Name == name {
+			return &d.Sections[i]
+		}
+	}
+	return nil
+}
+
+// SetSection adds the section to the document, replacing any section with the same name
+func (d *Document) SetSection(section DocumentSection) {
+	if existing := d.Section(section.Name); existing != nil {
+		*existing = section
+		return
+	}
+	d.Sections = append(d.Sections, section)
+}
+
+// Renders reports whether the section's conditions hold for the variables
+func (s *DocumentSection) Renders(variables map[string]interface{}) bool {
+	return EvaluateConditions(s.LogicalOperator, s.Conditions, variables)
+}
+
+// RenderDocument fills in a document's placeholders locally, without calling docubot.
+// Variables are html escaped, unanswered variables render as empty text, filters such as FilterDate format
+// them, and sections render only when their conditions hold.
+func RenderDocument(document *Document, variables map[string]interface{}) (*RenderedDocument, error) {
+	return RenderDocumentWithOptions(document, variables, RenderOptions{})
+}
+
+// RenderOptions customizes RenderDocumentWithOptions
+type RenderOptions struct {
+	// Locale, when set, writes dates and numbers the way the locale does
+	Locale *Locale
+	// Location, when set, is the time zone instants are written in, so they show the day the user saw
+	Location *time.Location
+	// Tree, when set with Locale, writes the answers to its currency questions as amounts of money
+	Tree *DocumentTree
+	// Partials maps the names of partials to their html, such as from LoadPartials. Rendering a document
+	// placing a partial that isn't in it fails.
+	Partials map[string]string
+}
+
+// RenderDocumentWithOptions fills in a document's placeholders locally like RenderDocument
+func RenderDocumentWithOptions(document *Document, variables map[string]interface{}, opts RenderOptions) (*RenderedDocument, error) {
+	r := renderer{document: document, variables: variables, opts: opts, report: &RenderReport{}}
+	rendered := RenderedDocument{Report: r.report}
+	var err error
+	if rendered.HeaderHTML, err = r.render(document.HeaderHTML, nil); err != nil {
+		return nil, err
+	}
+	if rendered.BodyHTML, err = r.render(document.BodyHTML, nil); err != nil {
+		return nil, err
+	}
+	if rendered.FooterHTML, err = r.render(document.FooterHTML, nil); err != nil {
+		return nil, err
+	}
+	r.report.finish(variables)
+	return &rendered, nil
+}
+
+type renderer struct {
+	document  *Document
+	variables map[string]interface{}
+	opts      RenderOptions
+	report    *RenderReport
+}
+
+// render fills in the placeholders of s, parents holds the sections and partials being rendered to catch cycles
+func (r renderer) render(s string, parents []string) (string, error) {
+	var err error
+	out := placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if err != nil {
+			return ""
+		}
+		kind, name, filters := parsePlaceholder(placeholderPattern.FindStringSubmatch(match)[1])
+		var html string
+		switch kind {
+		case PlaceholderFile:
+			html = r.file(name)
+		case PlaceholderSection:
+			html, err = r.section(name, parents)
+		case PlaceholderPartial:
+			html, err = r.partial(name, parents)
+		default:
+			html, err = r.value(name, filters)
+		}
+		return html
+	})
+	return out, err
+}
+
+// value writes the variable with the filters applied, an unknown filter fails the rendering
+func (r renderer) value(name string, filters []string) (string, error) {
+	parsed := make([]placeholderFilter, 0, len(filters))
+	for _, filter := range filters {
+		f := parseFilter(filter)
+		if err := f.check(); err != nil {
+			return "", err
+		}
+		parsed = append(parsed, f)
+	}
+	v, ok := r.variables[name]
+	if !ok || v == nil || v == "" {
+		r.report.empty(name)
+		return "", nil
+	}
+	r.report.consume(name)
+	if t, ok := variableTime(v); ok && r.opts.Location != nil {
+		v = t.In(r.opts.Location)
+	}
+	if r.opts.Locale != nil && r.opts.Tree != nil {
+		if question := r.opts.Tree.Question(name); question != nil && strings.EqualFold(question.EntityType, EntityTypeCurrency) {
+			if m, ok := MoneyVariable(r.variables, name, r.opts.Locale.CurrencyCode); ok {
+				v = m
+			}
+		}
+	}
+	if len(parsed) > 0 {
+		for _, f := range parsed {
+			v = r.filter(v, f)
+		}
+		return html.EscapeString(r.format(v)), nil
+	}
+	if r.opts.Locale != nil {
+		return html.EscapeString(r.opts.Locale.FormatValue(v)), nil
+	}
+	return html.EscapeString(fmt.Sprintf("%v", v)), nil
+}
+
+func (r renderer) file(name string) string {
+	file, ok := FileVariableValue(r.variables, name)
+	if !ok {
+		r.report.empty(name)
+		return ""
+	}
+	r.report.consume(name)
+	return html.EscapeString(file.URL)
+}
+
+func (r renderer) section(name string, parents []string) (string, error) {
+	for _, p := range parents {
+		if p == name {
+			return "", fmt.Errorf("docubot: section %q contains itself", name)
+		}
+	}
+	section := r.document.Section(name)
+	if section == nil {
+		return "", fmt.Errorf("docubot: document has no section %q", name)
+	}
+	if !section.Renders(r.variables) {
+		r.report.section(name, false)
+		return "", nil
+	}
+	r.report.section(name, true)
+	return r.render(section.HTML, append(parents, name))
+}
+
+func (r renderer) partial(name string, parents []string) (string, error) {
+	marker := partialPlaceholderPrefix + name
+	for _, p := range parents {
+		if p == marker {
+			return "", fmt.Errorf("docubot: partial %q contains itself", name)
+		}
+	}
+	html, ok := r.opts.Partials[name]
+	if !ok {
+		return "", fmt.Errorf("docubot: no partial %q", name)
+	}
+	return r.render(html, append(parents, marker))
+}
@@ -0,0 +1,66 @@
+package docubotlib
+
+import (
+	"context"
+	"sort"
+)
+
+// RenderReport is which variables and sections went into a rendered document, so QA can check
+// the document matches the answers
+type RenderReport struct {
+	// Consumed are the variables whose values were rendered, in the order they first appear
+	Consumed []string
+	// Empty are the variables whose placeholders rendered empty because they are unanswered or blank
+	Empty []string
+	// RenderedSections and SkippedSections are the sections whose conditions held and didn't
+	RenderedSections []string
+	SkippedSections  []string
+	// Unused are the answered variables no rendered placeholder uses, sorted
+	Unused []string
+}
+
+func (r *RenderReport) consume(name string) {
+	if !containsString(r.Consumed, name) {
+		r.Consumed = append(r.Consumed, name)
+	}
+}
+
+func (r *RenderReport) empty(name string) {
+	if !containsString(r.Empty, name) {
+		r.Empty = append(r.Empty, name)
+	}
+}
+
+func (r *RenderReport) section(name string, rendered bool) {
+	if rendered && !containsString(r.RenderedSections, name) {
+		r.RenderedSections = append(r.RenderedSections, name)
+	}
+	if !rendered && !containsString(r.SkippedSections, name) {
+		r.SkippedSections = append(r.SkippedSections, name)
+	}
+}
+
+// finish records the answered variables nothing rendered
+func (r *RenderReport) finish(variables map[string]interface{}) {
+	for name, v := range variables {
+		if v != nil && v != "" && !containsString(r.Consumed, name) {
+			r.Unused = append(r.Unused, name)
+		}
+	}
+	sort.Strings(r.Unused)
+}
+
+// GetRenderReport renders the document template locally with the thread's variables and reports which
+// variables and sections went into it, the way docubot fills in the thread's generated document
+func (c *Client) GetRenderReport(ctx context.Context, thread string, user string, document *Document, callOpts ...CallOption) (*RenderReport, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	variables, err := c.getDocubotVariables(ctx, thread, user)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := RenderDocumentWithOptions(document, variables.Data.Variables, RenderOptions{Location: c.Location})
+	if err != nil {
+		return nil, err
+	}
+	return rendered.Report, nil
+}
@@ -0,0 +1,82 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// DataResolver derives variables from an answer using data the user never types, such as looking up
+// the county of a zip code. variables are the thread's variables before the answer.
+type DataResolver func(ctx context.Context, answer string, variables map[string]interface{}) (map[string]interface{}, error)
+
+// Resolve registers the resolver a Conversation calls before sending an answer to the question asking for variable.
+// The variables it returns are written to the thread before the answer is sent, so the tree can branch on them.
+// Resolvers need the conversation's Tree or Trees to know which question an answer is for.
+func (conv *Conversation) Resolve(variable string, resolver DataResolver) {
+	if conv.Resolvers == nil {
+		conv.Resolvers = map[string]DataResolver{}
+	}
+	conv.Resolvers[variable] = resolver
+}
+
+// resolve calls the resolver of the question the answer is for and writes the variables it derives
+func (conv *Conversation) resolve(ctx context.Context, answer string) error {
+	if len(conv.Resolvers) == 0 || (conv.Tree == nil && conv.Trees == nil) {
+		return nil
+	}
+	question, variables, err := conv.pendingQuestion(ctx)
+	if err != nil || question == nil {
+		return err
+	}
+	resolver, ok := conv.Resolvers[question.VariableName]
+	if !ok {
+		return nil
+	}
+	derived, err := resolver(ctx, answer, variables)
+	if err != nil {
+		return fmt.Errorf("docubot: resolving %v: %w", question.VariableName, err)
+	}
+	if len(derived) == 0 {
+		return nil
+	}
+	_, err = conv.Client.setDocubotVariables(ctx, conv.Thread, conv.Sender, derived)
+	return err
+}
+
+// SetDocubotVariables writes variables to the provided user's thread, they are merged into the thread's
+// variables and a nil value removes the variable. WithActor attributes the change to the person who made it.
+func (c *Client) SetDocubotVariables(ctx context.Context, thread string, user string, variables map[string]interface{}, callOpts ...CallOption) (*DocumentVariablesResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.setDocubotVariables(ctx, thread, user, variables)
+}
+
+func (c *Client) setDocubotVariables(ctx context.Context, thread string, user string, variables map[string]interface{}) (*DocumentVariablesResponse, error) {
+	variables, err := c.prepareVariables(ctx, thread, variables)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/variables?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "PATCH", url, map[string]interface{}{"variables": variables})
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentVariablesResponse
+	err = c.doJSON(req, variables, &response)
+	if err == nil {
+		c.wroteVariables(thread, response.Meta.Version)
+	}
+	return &response, err
+}
+
+// prepareVariables readies variables written to the thread, the designated text values are encrypted for it
+func (c *Client) prepareVariables(ctx context.Context, thread string, variables map[string]interface{}) (map[string]interface{}, error) {
+	return c.VariableEncryption.encryptVariables(ctx, thread, variables)
+}
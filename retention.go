@@ -0,0 +1,171 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetentionPolicy is a data model
+type RetentionPolicy struct {
+	// DocumentTreeID is the tree the policy applies to, empty for the account default
+	DocumentTreeID string `json:"documentTreeId"`
+	// ThreadRetentionDays is how long threads are kept after their last message, zero keeps them forever
+	ThreadRetentionDays int `json:"threadRetentionDays"`
+	// DocumentRetentionDays is how long generated documents are kept, zero keeps them forever
+	DocumentRetentionDays int       `json:"documentRetentionDays"`
+	UpdatedAt             time.Time `json:"updatedAt"`
+	CreatedAt             time.Time `json:"createdAt"`
+}
+
+// ScheduledDeletion is a data model
+type ScheduledDeletion struct {
+	// Type is either "thread" or "document"
+	Type           string    `json:"type"`
+	ThreadID       string    `json:"threadId"`
+	UserID         string    `json:"userId"`
+	DocumentTreeID string    `json:"documentTreeId"`
+	DeleteAt       time.Time `json:"deleteAt"`
+}
+
+// RetentionPolicyResponse is the response received from getting or setting a retention policy
+type RetentionPolicyResponse struct {
+	Data RetentionPolicyData    `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// RetentionPolicyData is the response data received from getting or setting a retention policy
+type RetentionPolicyData struct {
+	Policy RetentionPolicy `json:"policy"`
+}
+
+// RetentionPolicyListResponse is the response received from listing retention policies
+type RetentionPolicyListResponse struct {
+	Data RetentionPolicyListData `json:"data"`
+	Meta map[string]interface{}  `json:"meta"`
+}
+
+// RetentionPolicyListData is the response data received from listing retention policies
+type RetentionPolicyListData struct {
+	Policies []RetentionPolicy `json:"policies"`
+}
+
+// ListScheduledDeletionsOptions filters the deletions returned by ListScheduledDeletions
+type ListScheduledDeletionsOptions struct {
+	// DocumentTreeID only returns deletions for this tree when set
+	DocumentTreeID string
+	// Before only returns deletions scheduled before this time when set
+	Before time.Time
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	// Limit is the maximum number of deletions in the page, the server default is used when zero
+	Limit int
+}
+
+// ScheduledDeletionListResponse is the response received from listing scheduled deletions
+type ScheduledDeletionListResponse struct {
+	Data ScheduledDeletionListData `json:"data"`
+	Meta Page                      `json:"meta"`
+}
+
+// ScheduledDeletionListData is the response data received from listing scheduled deletions
+type ScheduledDeletionListData struct {
+	Deletions []ScheduledDeletion `json:"deletions"`
+}
+
+// ListRetentionPolicies lists the account's retention policies
+func (c *Client) ListRetentionPolicies(ctx context.Context, callOpts ...CallOption) (*RetentionPolicyListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/retention", c.DocubotAPIURLBase)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response RetentionPolicyListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetRetentionPolicy gets the retention policy for a tree, an empty docTreeID gets the account default
+func (c *Client) GetRetentionPolicy(ctx context.Context, docTreeID string, callOpts ...CallOption) (*RetentionPolicyResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	req, err := c.newRequest(ctx, "GET", c.retentionPolicyURL(docTreeID), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response RetentionPolicyResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// SetRetentionPolicy creates or replaces the retention policy for policy.DocumentTreeID
+func (c *Client) SetRetentionPolicy(ctx context.Context, policy *RetentionPolicy, callOpts ...CallOption) (*RetentionPolicyResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	if policy == nil {
+		return nil, errors.New("docubot: no retention policy to set")
+	}
+	req, err := c.newRequest(
+		ctx,
+		"PUT",
+		c.retentionPolicyURL(policy.DocumentTreeID),
+		map[string]interface{}{
+			"policy": policy,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response RetentionPolicyResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteRetentionPolicy removes a tree's retention policy so the account default applies again
+func (c *Client) DeleteRetentionPolicy(ctx context.Context, docTreeID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	req, err := c.newRequest(ctx, "DELETE", c.retentionPolicyURL(docTreeID), nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// ListScheduledDeletions lists a page of the threads and documents that retention policies will delete
+func (c *Client) ListScheduledDeletions(ctx context.Context, opts ListScheduledDeletionsOptions, callOpts ...CallOption) (*ScheduledDeletionListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	if opts.DocumentTreeID != "" {
+		params.Set("docTreeId", opts.DocumentTreeID)
+	}
+	if !opts.Before.IsZero() {
+		params.Set("before", opts.Before.UTC().Format(time.RFC3339))
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	url := fmt.Sprintf(
+		"%v/api/v1/retention/scheduled?%v",
+		c.DocubotAPIURLBase,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ScheduledDeletionListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+func (c *Client) retentionPolicyURL(docTreeID string) string {
+	if docTreeID == "" {
+		return fmt.Sprintf("%v/api/v1/retention/default", c.DocubotAPIURLBase)
+	}
+	return fmt.Sprintf("%v/api/v1/retention/trees/%v", c.DocubotAPIURLBase, docTreeID)
+}
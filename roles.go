@@ -0,0 +1,25 @@
+package docubotlib
+
+import "context"
+
+// SenderRole is who a message sent to a thread comes from
+type SenderRole string
+
+// Sender roles docubot understands
+const (
+	// SenderEndUser messages are answers to the interview, the default
+	SenderEndUser SenderRole = "endUser"
+	// SenderAgent messages come from a human agent, they are shown in the thread but don't answer or advance the interview
+	SenderAgent SenderRole = "agent"
+	// SenderSystem messages are notices from the integration, they don't answer or advance the interview either
+	SenderSystem SenderRole = "system"
+	// SenderBot marks docubot's own messages in transcripts, it can't be sent
+	SenderBot SenderRole = "bot"
+)
+
+// SendMessageAs sends a message to docubot as the role, so agents and integrations can write into a thread
+// without it being taken as the user's answer. sender is the id of the user or agent sending the message.
+func (c *Client) SendMessageAs(ctx context.Context, role SenderRole, message string, thread string, sender string, docTreeID string, callOpts ...CallOption) (*MessageResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.sendMessage(ctx, message, thread, sender, docTreeID, role, "")
+}
@@ -0,0 +1,511 @@
+package docubotlib
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SanitizePolicy decides what SanitizeHTML removes from Document html
+type SanitizePolicy struct {
+	// RemoveElements are removed along with their content
+	RemoveElements []string
+	// AllowedResourceHosts are the hosts images, fonts, and other resources may be loaded from,
+	// resources from any other host are removed. Links to other hosts are always kept.
+	AllowedResourceHosts []string
+	// AllowDataImages keeps images embedded as data: urls
+	AllowDataImages bool
+}
+
+// DefaultSanitizePolicy removes scripts, frames, plugins, and everything that loads resources from another host
+var DefaultSanitizePolicy = SanitizePolicy{
+	RemoveElements: []string{
+		"script", "noscript", "iframe", "frame", "frameset", "object", "embed", "applet",
+		"link", "meta", "base", "form", "svg", "math", "template",
+	},
+	AllowDataImages: true,
+}
+
+// voidElements have no content or closing tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// resourceAttributes load what they reference when the document is rendered
+var resourceAttributes = map[string]bool{
+	"src": true, "srcset": true, "background": true, "poster": true, "lowsrc": true, "dynsrc": true,
+	"data": true, "xlink:href": true, "action": true, "formaction": true,
+}
+
+// linkAttributes reference urls that are only loaded when followed
+var linkAttributes = map[string]bool{
+	"href": true, "cite": true, "longdesc": true,
+}
+
+var (
+	cssImportPattern     = regexp.MustCompile(`(?i)@import[^;]*;?`)
+	cssURLPattern        = regexp.MustCompile(`(?i)url\(\s*(['"]?)(.*?)(['"]?)\s*\)`)
+	cssExpressionPattern = regexp.MustCompile(`(?i)expression\s*\(|behavio(u)?r\s*:|-moz-binding`)
+)
+
+// SanitizeHTML removes scripts, event handlers, and resource loads the policy doesn't allow from s.
+// Placeholders are kept as they are.
+func (p SanitizePolicy) SanitizeHTML(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		lt := strings.IndexByte(s[i:], '<')
+		if lt < 0 {
+			b.WriteString(s[i:])
+			break
+		}
+		b.WriteString(s[i : i+lt])
+		i += lt
+		if strings.HasPrefix(s[i:], "<!--") {
+			// comments are dropped, some renderers act on conditional comments
+			end := strings.Index(s[i+4:], "-->")
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+		t, n, ok := parseTag(s[i:])
+		if !ok {
+			b.WriteString("&lt;")
+			i++
+			continue
+		}
+		i += n
+		if p.removes(t.name) {
+			if !t.closing && !t.selfClosing && !voidElements[t.name] {
+				i = skipElement(s, i, t.name)
+			}
+			continue
+		}
+		if t.closing {
+			b.WriteString("</" + t.name + ">")
+			continue
+		}
+		b.WriteString(p.startTag(t))
+		if t.name == "style" && !t.selfClosing {
+			end := closingTagIndex(s, i, "style")
+			if css, ok := p.sanitizeStylesheet(s[i:end]); ok {
+				b.WriteString(css)
+			}
+			i = end
+		}
+	}
+	return b.String()
+}
+
+// SanitizeDocument returns a copy of the document with the html of its header, body, footer, and sections sanitized
+func (p SanitizePolicy) SanitizeDocument(document *Document) *Document {
+	sanitized := *document
+	sanitized.HeaderHTML = p.SanitizeHTML(document.HeaderHTML)
+	sanitized.BodyHTML = p.SanitizeHTML(document.BodyHTML)
+	sanitized.FooterHTML = p.SanitizeHTML(document.FooterHTML)
+	sanitized.Sections = make([]DocumentSection, len(document.Sections))
+	for i, section := range document.Sections {
+		section.HTML = p.SanitizeHTML(section.HTML)
+		sanitized.Sections[i] = section
+	}
+	if document.Sections == nil {
+		sanitized.Sections = nil
+	}
+	return &sanitized
+}
+
+func (p SanitizePolicy) removes(name string) bool {
+	for _, e := range p.RemoveElements {
+		if strings.EqualFold(e, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p SanitizePolicy) startTag(t htmlTag) string {
+	var b strings.Builder
+	b.WriteString("<" + t.name)
+	for _, attr := range t.attrs {
+		value, ok := p.attribute(t.name, attr)
+		if !ok {
+			continue
+		}
+		b.WriteString(" " + attr.name)
+		if attr.hasValue {
+			b.WriteString(`="` + strings.Replace(value, `"`, "&quot;", -1) + `"`)
+		}
+	}
+	if t.selfClosing {
+		b.WriteString(" /")
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// attribute returns the value to keep for the attribute, false if it is removed
+func (p SanitizePolicy) attribute(element string, attr htmlAttr) (string, bool) {
+	if strings.HasPrefix(attr.name, "on") {
+		return "", false
+	}
+	switch {
+	case attr.name == "style":
+		value := html.UnescapeString(attr.value)
+		if cssExpressionPattern.MatchString(decodeCSSEscapes(value)) {
+			return "", false
+		}
+		css, ok := p.sanitizeStylesheet(value)
+		if !ok {
+			return "", false
+		}
+		return html.EscapeString(css), true
+	case attr.name == "srcset":
+		for _, candidate := range strings.Split(html.UnescapeString(attr.value), ",") {
+			fields := strings.Fields(candidate)
+			if len(fields) > 0 && !p.allowsResource(element, fields[0]) {
+				return "", false
+			}
+		}
+	case resourceAttributes[attr.name]:
+		if !p.allowsResource(element, html.UnescapeString(attr.value)) {
+			return "", false
+		}
+	case linkAttributes[attr.name]:
+		if unsafeScheme(html.UnescapeString(attr.value), false) {
+			return "", false
+		}
+	}
+	return attr.value, true
+}
+
+// allowsResource reports whether the url may be loaded by the element
+func (p SanitizePolicy) allowsResource(element string, rawURL string) bool {
+	u := normalizeURL(rawURL)
+	if unsafeScheme(u, p.AllowDataImages && (element == "img" || element == "source" || element == "")) {
+		return false
+	}
+	host, external := externalHost(u)
+	if !external {
+		return true
+	}
+	for _, allowed := range p.AllowedResourceHosts {
+		if strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeStylesheet sanitizes css the way browsers read it, with its escapes decoded so escaped rules such as
+// @\69mport are caught. False is returned when css with escapes needed sanitizing, the escapes can't be kept
+// apart from what was removed.
+func (p SanitizePolicy) sanitizeStylesheet(css string) (string, bool) {
+	decoded := decodeCSSEscapes(css)
+	sanitized := p.sanitizeCSS(decoded)
+	switch {
+	case sanitized == decoded:
+		return css, true
+	case decoded == css:
+		return sanitized, true
+	}
+	return "", false
+}
+
+func (p SanitizePolicy) sanitizeCSS(css string) string {
+	css = cssImportPattern.ReplaceAllString(css, "")
+	css = cssExpressionPattern.ReplaceAllString(css, "")
+	css = p.sanitizeImageSets(css)
+	return cssURLPattern.ReplaceAllStringFunc(css, func(match string) string {
+		if p.allowsResource("", html.UnescapeString(cssURLPattern.FindStringSubmatch(match)[2])) {
+			return match
+		}
+		return "none"
+	})
+}
+
+// sanitizeImageSets replaces the image-set() and -webkit-image-set() functions that load a plain string url the
+// policy doesn't allow with none, the url() functions in them are left to sanitizeCSS
+func (p SanitizePolicy) sanitizeImageSets(css string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(strings.ToLower(css), "image-set(")
+		if start < 0 {
+			b.WriteString(css)
+			return b.String()
+		}
+		end := cssFunctionEnd(css, start+len("image-set("))
+		prefix := start
+		if prefix >= len("-webkit-") && strings.EqualFold(css[prefix-len("-webkit-"):prefix], "-webkit-") {
+			prefix -= len("-webkit-")
+		}
+		allowed := true
+		for _, rawURL := range cssStrings(css[start:end]) {
+			allowed = allowed && p.allowsResource("", html.UnescapeString(rawURL))
+		}
+		if allowed {
+			b.WriteString(css[:end])
+		} else {
+			b.WriteString(css[:prefix] + "none")
+		}
+		css = css[end:]
+	}
+}
+
+// cssFunctionEnd returns the index just after the parenthesis closing the function whose arguments start at i,
+// len(css) when it isn't closed
+func cssFunctionEnd(css string, i int) int {
+	depth := 1
+	for ; i < len(css); i++ {
+		switch css[i] {
+		case '"', '\'':
+			if end := strings.IndexByte(css[i+1:], css[i]); end >= 0 {
+				i += end + 1
+			} else {
+				return len(css)
+			}
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(css)
+}
+
+// cssStrings returns the contents of the quoted strings in css, an unterminated string runs to the end
+func cssStrings(css string) []string {
+	var strs []string
+	for i := 0; i < len(css); i++ {
+		if css[i] != '"' && css[i] != '\'' {
+			continue
+		}
+		end := strings.IndexByte(css[i+1:], css[i])
+		if end < 0 {
+			return append(strs, css[i+1:])
+		}
+		strs = append(strs, css[i+1:i+1+end])
+		i += end + 1
+	}
+	return strs
+}
+
+// decodeCSSEscapes replaces the escapes in css, such as \75 or \u, with the characters they stand for
+func decodeCSSEscapes(css string) string {
+	if !strings.Contains(css, "\\") {
+		return css
+	}
+	var b strings.Builder
+	for i := 0; i < len(css); i++ {
+		if css[i] != '\\' {
+			b.WriteByte(css[i])
+			continue
+		}
+		i++
+		if i == len(css) {
+			b.WriteRune(utf8.RuneError)
+			break
+		}
+		j := i
+		for j < len(css) && j-i < 6 && isHexDigit(css[j]) {
+			j++
+		}
+		if j == i {
+			// an escaped newline continues a string, any other character stands for itself
+			if css[i] != '\n' {
+				b.WriteByte(css[i])
+			}
+			continue
+		}
+		r, _ := strconv.ParseUint(css[i:j], 16, 32)
+		if r == 0 || r > unicode.MaxRune || (r >= 0xd800 && r <= 0xdfff) {
+			r = utf8.RuneError
+		}
+		b.WriteRune(rune(r))
+		// one whitespace character ends the escape
+		switch {
+		case strings.HasPrefix(css[j:], "\r\n"):
+			i = j + 1
+		case j < len(css) && isHTMLSpace(css[j]):
+			i = j
+		default:
+			i = j - 1
+		}
+	}
+	return b.String()
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// normalizeURL removes the whitespace and control characters browsers ignore in urls
+func normalizeURL(rawURL string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, rawURL)
+}
+
+// unsafeScheme reports whether the url runs code, data: image urls are allowed when allowDataImages is set
+func unsafeScheme(rawURL string, allowDataImages bool) bool {
+	u := strings.ToLower(normalizeURL(rawURL))
+	switch {
+	case strings.HasPrefix(u, "javascript:"), strings.HasPrefix(u, "vbscript:"):
+		return true
+	case strings.HasPrefix(u, "data:"):
+		return !allowDataImages || !strings.HasPrefix(u, "data:image/") || strings.HasPrefix(u, "data:image/svg")
+	}
+	return false
+}
+
+// externalHost returns the host of an absolute or protocol relative url
+func externalHost(u string) (string, bool) {
+	lower := strings.ToLower(u)
+	var rest string
+	switch {
+	case strings.HasPrefix(lower, "//"):
+		rest = u[2:]
+	case strings.Contains(lower, "://"):
+		rest = u[strings.Index(lower, "://")+3:]
+	default:
+		return "", false
+	}
+	if end := strings.IndexAny(rest, "/?#"); end >= 0 {
+		rest = rest[:end]
+	}
+	if at := strings.LastIndexByte(rest, '@'); at >= 0 {
+		rest = rest[at+1:]
+	}
+	if colon := strings.LastIndexByte(rest, ':'); colon >= 0 && !strings.HasSuffix(rest, "]") {
+		rest = rest[:colon]
+	}
+	return rest, true
+}
+
+type htmlTag struct {
+	name        string
+	closing     bool
+	selfClosing bool
+	attrs       []htmlAttr
+}
+
+type htmlAttr struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+// parseTag parses the tag at the start of s, returning the tag and its length
+func parseTag(s string) (htmlTag, int, bool) {
+	var t htmlTag
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		t.closing = true
+		i++
+	}
+	start := i
+	for i < len(s) && isTagNameChar(s[i]) {
+		i++
+	}
+	if i == start {
+		return t, 0, false
+	}
+	t.name = strings.ToLower(s[start:i])
+	for i < len(s) {
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		switch {
+		case s[i] == '>':
+			return t, i + 1, true
+		case strings.HasPrefix(s[i:], "/>"):
+			t.selfClosing = true
+			return t, i + 2, true
+		case s[i] == '/':
+			i++
+			continue
+		}
+		nameStart := i
+		for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		attr := htmlAttr{name: strings.ToLower(s[nameStart:i])}
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isHTMLSpace(s[i]) {
+				i++
+			}
+			attr.hasValue = true
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				end := strings.IndexByte(s[i+1:], s[i])
+				if end < 0 {
+					return t, 0, false
+				}
+				attr.value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valueStart := i
+				for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				attr.value = s[valueStart:i]
+			}
+		}
+		if !t.closing {
+			t.attrs = append(t.attrs, attr)
+		}
+	}
+	return t, 0, false
+}
+
+// skipElement returns the index just after the closing tag of the element whose content starts at i
+func skipElement(s string, i int, name string) int {
+	end := closingTagIndex(s, i, name)
+	if end == len(s) {
+		return end
+	}
+	if gt := strings.IndexByte(s[end:], '>'); gt >= 0 {
+		return end + gt + 1
+	}
+	return len(s)
+}
+
+// closingTagIndex returns the index of the element's closing tag at or after i, len(s) when it isn't closed
+func closingTagIndex(s string, i int, name string) int {
+	lower := strings.ToLower(s[i:])
+	for offset := 0; ; {
+		j := strings.Index(lower[offset:], "</"+name)
+		if j < 0 {
+			return len(s)
+		}
+		j += offset
+		after := j + 2 + len(name)
+		if after >= len(lower) || !isTagNameChar(lower[after]) {
+			return i + j
+		}
+		offset = after
+	}
+}
+
+func isTagNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == ':'
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
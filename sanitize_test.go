@@ -0,0 +1,97 @@
+package docubotlib
+
+import (
+	"strings"
+	"testing"
+)
+
+// assertSanitized checks that sanitizing each input with p leaves none of the forbidden text
+func assertSanitized(t *testing.T, p SanitizePolicy, inputs map[string]string, forbidden ...string) {
+	t.Helper()
+	for name, in := range inputs {
+		out := p.SanitizeHTML(in)
+		for _, f := range forbidden {
+			if strings.Contains(strings.ToLower(out), f) {
+				t.Errorf("%v: sanitized %q to %q", name, in, out)
+			}
+		}
+	}
+}
+
+func TestSanitizeImageSets(t *testing.T) {
+	assertSanitized(t, DefaultSanitizePolicy, map[string]string{
+		"image-set":          `<p style="background: image-set('https://evil.test/a.png' 1x)">x</p>`,
+		"webkit image-set":   `<p style="background: -webkit-image-set(&quot;https://evil.test/a.png&quot; 1x, url(b.png) 2x)">x</p>`,
+		"unclosed":           `<style>p { background: image-set("//evil.test/a.png" 1x</style>`,
+		"escaped":            `<style>p { background: image-set("\2f\2f evil.test/a.png" 1x) }</style>`,
+		"escaped function":   `<style>p { background: \69mage-set("https://evil.test/a.png" 1x) }</style>`,
+		"upper case":         `<style>p { background: IMAGE-SET("https://evil.test/a.png" 1x) }</style>`,
+		"url in image-set":   `<style>p { background: image-set(url(https://evil.test/a.png) 1x) }</style>`,
+		"second of the set":  `<style>p { background: image-set("a.png" 1x, "https://evil.test/a.png" 2x) }</style>`,
+		"protocol relative":  `<style>p { background: image-set('//evil.test/a.png' 1x) }</style>`,
+		"javascript scheme":  `<style>p { background: image-set("javascript:alert(1)" 1x) }</style>`,
+		"whitespace in host": `<style>p { background: image-set("https:/ /evil.test/a.png" 1x) }</style>`,
+	}, "evil.test", "javascript")
+
+	local := `<style>p { background: image-set("a.png" 1x, "b.png" 2x) }</style>`
+	if out := DefaultSanitizePolicy.SanitizeHTML(local); out != local {
+		t.Errorf("sanitized an image-set of local images to %q", out)
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	assertSanitized(t, DefaultSanitizePolicy, map[string]string{
+		"script":                `<p>a</p><script>alert(1)</script>`,
+		"upper case script":     `<SCRIPT>alert(1)</SCRIPT >`,
+		"unclosed script":       `<script>alert(1)`,
+		"event handler":         `<img src="a.png" onerror="alert(1)">`,
+		"unquoted handler":      `<img src=a.png onerror=alert(1)>`,
+		"javascript link":       `<a href="javascript:alert(1)">x</a>`,
+		"entity encoded scheme": `<a href="&#106;avascript:alert(1)">x</a>`,
+		"whitespace in scheme":  `<a href="java&#x09;script:alert(1)">x</a>`,
+		"iframe":                `<iframe src="https://evil.test"></iframe>`,
+		"svg":                   `<svg><script>alert(1)</script></svg>`,
+		"conditional comment":   `<!--[if IE]><script>alert(1)</script><![endif]-->`,
+	}, "alert")
+	assertSanitized(t, DefaultSanitizePolicy, map[string]string{
+		"external image":       `<img src="https://evil.test/a.png">`,
+		"protocol relative":    `<img src="//evil.test/a.png">`,
+		"srcset":               `<img srcset="a.png 1x, https://evil.test/a.png 2x">`,
+		"background attribute": `<td background="https://evil.test/a.png">`,
+		"style url":            `<p style="background: url(https://evil.test/a.png)">x</p>`,
+		"quoted style url":     `<p style="background: url('https://evil.test/a.png')">x</p>`,
+		"import":               `<style>@import "https://evil.test/a.css";</style>`,
+		"escaped import":       `<style>@\69mport "https://evil.test/a.css";</style>`,
+		"escaped url":          `<style>p { background: \75rl(https://evil.test/a.png) }</style>`,
+		"entity in style":      `<p style="background: u&#114;l(https://evil.test/a.png)">x</p>`,
+		"credentials in host":  `<img src="https://docubot.test@evil.test/a.png">`,
+		"link element":         `<link rel="stylesheet" href="https://evil.test/a.css">`,
+		"svg data image":       `<img src="data:image/svg+xml;base64,PHN2Zz4=">`,
+	}, "evil.test", "data:image/svg")
+	assertSanitized(t, DefaultSanitizePolicy, map[string]string{
+		"expression":         `<p style="width: expression(alert(1))">x</p>`,
+		"escaped expression": `<p style="width: e\78pression(alert(1))">x</p>`,
+		"behavior":           `<p style="behavior: url(a.htc)">x</p>`,
+		"moz binding":        `<p style="-moz-binding: url(a.xml)">x</p>`,
+	}, "alert", "behavior", "binding")
+
+	kept := map[string]string{
+		"placeholder":   `<p>{{name}}</p>`,
+		"local image":   `<img src="images/logo.png">`,
+		"external link": `<a href="https://docubot.test/help">help</a>`,
+		"data image":    `<img src="data:image/png;base64,iVBORw0KGgo=">`,
+		"local style":   `<p style="color: red">x</p>`,
+	}
+	for name, in := range kept {
+		if out := DefaultSanitizePolicy.SanitizeHTML(in); out != in {
+			t.Errorf("%v: sanitized %q to %q", name, in, out)
+		}
+	}
+	allowing := SanitizePolicy{AllowedResourceHosts: []string{"cdn.docubot.test"}}
+	if in := `<img src="https://cdn.docubot.test/a.png">`; allowing.SanitizeHTML(in) != in {
+		t.Errorf("removed an image from an allowed host: %q", allowing.SanitizeHTML(in))
+	}
+	if out := allowing.SanitizeHTML(`<img src="data:image/png;base64,iVBORw0KGgo=">`); strings.Contains(out, "data:") {
+		t.Errorf("kept a data image the policy doesn't allow: %q", out)
+	}
+}
@@ -0,0 +1,133 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Reminder channels
+const (
+	ReminderEmail   string = "email"
+	ReminderWebhook string = "webhook"
+)
+
+// ThreadSchedule is a data model for when a thread expires and when its user is reminded to finish it
+type ThreadSchedule struct {
+	// ExpiresAt is when the thread is closed, it never expires when nil
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// ExpireAfterDays closes the thread after that many days without a message, zero disables it
+	ExpireAfterDays int              `json:"expireAfterDays,omitempty"`
+	Reminders       []ThreadReminder `json:"reminders,omitempty"`
+}
+
+// ThreadReminder is a notification sent when an unfinished thread has had no message for AfterDays
+type ThreadReminder struct {
+	AfterDays int `json:"afterDays"`
+	// Channel is ReminderEmail or ReminderWebhook, webhook reminders are delivered as EventThreadReminder
+	Channel string `json:"channel"`
+	// Email is the address email reminders are sent to, the user's address is used when empty
+	Email string `json:"email,omitempty"`
+	// SentAt is set by docubot once the reminder has been sent
+	SentAt *time.Time `json:"sentAt,omitempty"`
+}
+
+// Validate checks the schedule's reminders are sent on a known channel after a positive number of days
+func (s *ThreadSchedule) Validate() error {
+	if s.ExpireAfterDays < 0 {
+		return errors.New("docubot: thread expiry must not be negative")
+	}
+	for _, reminder := range s.Reminders {
+		if reminder.AfterDays < 1 {
+			return errors.New("docubot: reminders must be sent after at least one day")
+		}
+		if reminder.Channel != ReminderEmail && reminder.Channel != ReminderWebhook {
+			return fmt.Errorf("docubot: unknown reminder channel %q", reminder.Channel)
+		}
+	}
+	return nil
+}
+
+// ThreadScheduleResponse is the response received from getting or setting a thread's schedule
+type ThreadScheduleResponse struct {
+	Data ThreadScheduleData     `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// ThreadScheduleData is the response data received from getting or setting a thread's schedule
+type ThreadScheduleData struct {
+	Schedule ThreadSchedule `json:"schedule"`
+}
+
+// GetThreadSchedule gets the expiry and reminders of the provided user's thread
+func (c *Client) GetThreadSchedule(ctx context.Context, thread string, user string, callOpts ...CallOption) (*ThreadScheduleResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	req, err := c.newRequest(ctx, "GET", c.threadScheduleURL(thread, user), nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadScheduleResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// SetThreadSchedule replaces the expiry and reminders of the provided user's thread
+func (c *Client) SetThreadSchedule(ctx context.Context, thread string, user string, schedule *ThreadSchedule, callOpts ...CallOption) (*ThreadScheduleResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(
+		ctx,
+		"PUT",
+		c.threadScheduleURL(thread, user),
+		map[string]interface{}{
+			"schedule": schedule,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadScheduleResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteThreadSchedule removes the expiry and reminders of the provided user's thread
+func (c *Client) DeleteThreadSchedule(ctx context.Context, thread string, user string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	req, err := c.newRequest(ctx, "DELETE", c.threadScheduleURL(thread, user), nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+func (c *Client) threadScheduleURL(thread string, user string) string {
+	params := url.Values{}
+	params.Set("user", user)
+	return fmt.Sprintf(
+		"%v/api/v1/docubot/%v/schedule?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+}
+
+// ListStaleThreads lists every unfinished thread that has had no message for inactiveFor,
+// opts filters the threads considered and its Cursor is ignored
+func (c *Client) ListStaleThreads(ctx context.Context, inactiveFor time.Duration, opts ListThreadsOptions, callOpts ...CallOption) ([]Thread, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	cutoff := time.Now().Add(-inactiveFor)
+	var stale []Thread
+	opts.Cursor = ""
+	err := c.walkThreads(ctx, opts, func(thread Thread) error {
+		if !thread.Complete && thread.UpdatedAt.Before(cutoff) {
+			stale = append(stale, thread)
+		}
+		return nil
+	})
+	return stale, err
+}
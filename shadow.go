@@ -0,0 +1,258 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of ShadowTraffic
+const (
+	defaultShadowTimeout     time.Duration = 30 * time.Second
+	defaultShadowMaxInFlight int           = 8
+)
+
+// ShadowDiff describes a mirrored request whose shadow response differed from docubot's
+type ShadowDiff struct {
+	Method string
+	// URL is the url of the original request, the mirror went to the same path on the shadow's BaseURL
+	URL string
+	// StatusCode and ShadowStatusCode are the statuses of the responses, a changed status is a difference
+	StatusCode       int
+	ShadowStatusCode int
+	// Paths are the json paths whose values differ, such as "$.data.thread.complete", empty when only the status
+	// differs. Bodies that aren't json are compared whole, under "$".
+	Paths []string
+	// Err is why the mirrored request failed, it is a difference too
+	Err error
+}
+
+// ShadowTraffic mirrors a sample of read requests to another docubot environment, such as its beta ahead of
+// an upgrade, and compares the responses. Mirrors are sent in the background after the original response
+// arrives and never change it. Add its Middleware to a client. It is safe for concurrent use.
+type ShadowTraffic struct {
+	// BaseURL is the environment requests are mirrored to, such as "https://beta.docubotapi.1law.com"
+	BaseURL string
+	// Rate is the fraction of GET requests mirrored, between 0 and 1
+	Rate float64
+	// Weights override Rate for the requests whose path starts with a key, the longest matching key wins,
+	// such as {"/api/v1/docubot": 0.5} to sample thread reads more than the rest
+	Weights map[string]float64
+	// IgnoreFields are json field names left out of the comparison wherever they are, such as "updatedAt"
+	IgnoreFields []string
+	// OnDiff is called with every difference
+	OnDiff func(ShadowDiff)
+	// Key and Secret authenticate the mirrors when set, otherwise they carry the original request's credentials
+	Key    string
+	Secret string
+	// HTTPClient sends the mirrors, a default http.Client when nil
+	HTTPClient *http.Client
+	// Timeout bounds each mirror, 30 seconds when zero
+	Timeout time.Duration
+	// MaxInFlight is how many mirrors can be pending at once, 8 when zero, samples past it are skipped
+	MaxInFlight int
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	inFlight int
+	wg       sync.WaitGroup
+}
+
+// NewShadowTraffic initializes shadow traffic mirroring rate of the read requests to baseURL
+func NewShadowTraffic(baseURL string, rate float64, onDiff func(ShadowDiff)) *ShadowTraffic {
+	return &ShadowTraffic{BaseURL: baseURL, Rate: rate, OnDiff: onDiff}
+}
+
+// Middleware mirrors a sample of the GET requests sent through it
+func (s *ShadowTraffic) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err != nil || req.Method != "GET" || !s.sample(req) {
+				return resp, err
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if err != nil {
+				s.done()
+				return resp, nil
+			}
+			mirror := req.Clone(context.Background())
+			original := req.URL.String()
+			status := resp.StatusCode
+			go func() {
+				defer s.done()
+				s.compare(mirror, original, status, body)
+			}()
+			return resp, nil
+		})
+	}
+}
+
+// Wait waits for the pending mirrors to be compared, such as before a service exits
+func (s *ShadowTraffic) Wait() {
+	s.wg.Wait()
+}
+
+// sample draws whether to mirror the request, reserving an in flight slot when it is
+func (s *ShadowTraffic) sample(req *http.Request) bool {
+	rate := s.Rate
+	matched := -1
+	for prefix, weight := range s.Weights {
+		if strings.HasPrefix(req.URL.Path, prefix) && len(prefix) > matched {
+			rate, matched = weight, len(prefix)
+		}
+	}
+	if rate <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rand == nil {
+		s.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	max := s.MaxInFlight
+	if max <= 0 {
+		max = defaultShadowMaxInFlight
+	}
+	if s.inFlight >= max || s.rand.Float64() >= rate {
+		return false
+	}
+	s.inFlight++
+	s.wg.Add(1)
+	return true
+}
+
+func (s *ShadowTraffic) done() {
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	s.wg.Done()
+}
+
+// compare sends the mirror and reports how its response differs from the original's
+func (s *ShadowTraffic) compare(mirror *http.Request, original string, status int, body []byte) {
+	diff := ShadowDiff{Method: mirror.Method, URL: original, StatusCode: status}
+	shadowStatus, shadowBody, err := s.send(mirror)
+	if err != nil {
+		diff.Err = err
+		s.report(diff)
+		return
+	}
+	diff.ShadowStatusCode = shadowStatus
+	var a, b interface{}
+	if json.Unmarshal(body, &a) == nil && json.Unmarshal(shadowBody, &b) == nil {
+		diff.Paths = jsonDiffPaths(s.withoutIgnored(a), s.withoutIgnored(b), "$")
+	} else if !bytes.Equal(body, shadowBody) {
+		diff.Paths = []string{"$"}
+	}
+	if status != shadowStatus || len(diff.Paths) > 0 {
+		s.report(diff)
+	}
+}
+
+func (s *ShadowTraffic) send(mirror *http.Request) (int, []byte, error) {
+	base, err := url.Parse(s.BaseURL)
+	if err != nil {
+		return 0, nil, err
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultShadowTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	mirror = mirror.WithContext(ctx)
+	mirror.URL.Scheme, mirror.URL.Host = base.Scheme, base.Host
+	mirror.URL.Path = strings.TrimSuffix(base.Path, "/") + mirror.URL.Path
+	mirror.Host = ""
+	if s.Key != "" || s.Secret != "" {
+		mirror.SetBasicAuth(s.Key, s.Secret)
+	}
+	client := s.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	resp, err := client.Do(mirror)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, body, err
+}
+
+func (s *ShadowTraffic) report(diff ShadowDiff) {
+	if s.OnDiff != nil {
+		s.OnDiff(diff)
+	}
+}
+
+// withoutIgnored removes the IgnoreFields from a decoded json value, at any depth
+func (s *ShadowTraffic) withoutIgnored(v interface{}) interface{} {
+	if len(s.IgnoreFields) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if containsString(s.IgnoreFields, key) {
+				delete(v, key)
+			} else {
+				v[key] = s.withoutIgnored(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = s.withoutIgnored(value)
+		}
+	}
+	return v
+}
+
+// jsonDiffPaths returns the paths at which two decoded json values differ, sorted
+func jsonDiffPaths(a interface{}, b interface{}, path string) []string {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var paths []string
+		for key, value := range a {
+			paths = append(paths, jsonDiffPaths(value, b[key], path+"."+key)...)
+		}
+		for key, value := range b {
+			if _, ok := a[key]; !ok {
+				paths = append(paths, jsonDiffPaths(nil, value, path+"."+key)...)
+			}
+		}
+		sort.Strings(paths)
+		return paths
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			break
+		}
+		var paths []string
+		for i := range a {
+			paths = append(paths, jsonDiffPaths(a[i], b[i], fmt.Sprintf("%v[%v]", path, i))...)
+		}
+		return paths
+	}
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+	return []string{path}
+}
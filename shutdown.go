@@ -0,0 +1,117 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrClosed is returned for work started after the client or message queue was shut down
+var ErrClosed = errors.New("docubot: shut down")
+
+// Shutdown stops the client's background work so a service can terminate cleanly during a deploy. Event
+// subscriptions and thread and tree streams are ended, their error channels receive ErrClosed. Messages already
+// in a MessageQueue are sent, and Group tasks and bulk helpers such as StartInterviews already running finish,
+// Shutdown waits for them until ctx is done, then returns ctx's error and abandons them. Idle connections and
+// the Limiter, when it is an io.Closer, are closed last. Afterwards every call fails with ErrClosed.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	if c.shuttingDown == nil {
+		c.shuttingDown = make(chan struct{})
+	}
+	select {
+	case <-c.shuttingDown:
+	default:
+		close(c.shuttingDown)
+	}
+	c.mu.Unlock()
+	drained := make(chan struct{})
+	go func() {
+		c.work.Wait()
+		close(drained)
+	}()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	c.httpClient().CloseIdleConnections()
+	if closer, ok := c.Limiter.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Close shuts the client down like Shutdown, waiting for as long as its background work takes
+func (c *Client) Close() error {
+	return c.Shutdown(context.Background())
+}
+
+// shutdownStarted returns a channel closed once Shutdown is called
+func (c *Client) shutdownStarted() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shuttingDown == nil {
+		c.shuttingDown = make(chan struct{})
+	}
+	return c.shuttingDown
+}
+
+// isClosed reports whether Shutdown has finished, calls fail with ErrClosed afterwards
+func (c *Client) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// background runs work in a goroutine Shutdown waits for. When end is set, work's context is canceled once
+// Shutdown is called, for work that would otherwise run forever. It reports false without running work once
+// Shutdown has been called.
+func (c *Client) background(ctx context.Context, end bool, work func(ctx context.Context)) bool {
+	shutdown := c.shutdownStarted()
+	c.mu.Lock()
+	select {
+	case <-shutdown:
+		c.mu.Unlock()
+		return false
+	default:
+	}
+	c.work.Add(1)
+	c.mu.Unlock()
+	cancel := context.CancelFunc(func() {})
+	if end {
+		ctx, cancel = context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-shutdown:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	go func() {
+		defer c.work.Done()
+		defer cancel()
+		work(ctx)
+	}()
+	return true
+}
+
+// shutdownError returns ErrClosed for the cancellation of work Shutdown ended, err otherwise
+func (c *Client) shutdownError(err error) error {
+	if !errors.Is(err, context.Canceled) {
+		return err
+	}
+	select {
+	case <-c.shutdownStarted():
+		return ErrClosed
+	default:
+		return err
+	}
+}
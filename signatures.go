@@ -0,0 +1,152 @@
+package docubotlib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Signature field types
+const (
+	SignatureFieldSignature string = "signature"
+	SignatureFieldInitials  string = "initials"
+	SignatureFieldDate      string = "dateSigned"
+)
+
+// SignatureField is a data model for a place in a generated Document where a signer signs,
+// e-sign providers use it to place their tabs
+type SignatureField struct {
+	Name string `json:"name"`
+	// Role is the signer role the field is assigned to, such as "client" or "witness"
+	Role string `json:"role"`
+	// Type is one of the SignatureField constants, SignatureFieldSignature when empty
+	Type string `json:"type,omitempty"`
+	// AnchorText places the field where the text appears in the generated document
+	AnchorText string `json:"anchorText,omitempty"`
+	// Page, X, and Y place the field at a fixed position when AnchorText is empty.
+	// Pages start at 1, X and Y are in points from the bottom left of the page.
+	Page   int     `json:"page,omitempty"`
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
+}
+
+// ValidateSignatureFields checks the fields have unique names and roles, and are either anchored or placed on a page
+func ValidateSignatureFields(fields []SignatureField) error {
+	seen := map[string]bool{}
+	for _, field := range fields {
+		switch {
+		case field.Name == "":
+			return errors.New("docubot: signature field has no name")
+		case seen[field.Name]:
+			return fmt.Errorf("docubot: signature field name %q is used more than once", field.Name)
+		case field.Role == "":
+			return fmt.Errorf("docubot: signature field %q has no signer role", field.Name)
+		case field.AnchorText == "" && field.Page < 1:
+			return fmt.Errorf("docubot: signature field %q needs anchor text or a page", field.Name)
+		case field.Type != "" && field.Type != SignatureFieldSignature && field.Type != SignatureFieldInitials && field.Type != SignatureFieldDate:
+			return fmt.Errorf("docubot: signature field %q has unknown type %q", field.Name, field.Type)
+		}
+		seen[field.Name] = true
+	}
+	return nil
+}
+
+// PDFAnchor is where a piece of anchor text was found in a pdf
+type PDFAnchor struct {
+	Text string
+	// Page starts at 1, X and Y are in points from the bottom left of the page
+	Page int
+	X    float64
+	Y    float64
+}
+
+// FindPDFAnchors finds every occurrence of the anchor texts in a pdf.
+// Only text written with simple fonts can be found, docubot writes signature anchors that way,
+// text in pdfs from other generators may not be searchable.
+func FindPDFAnchors(pdf io.Reader, anchors []string) ([]PDFAnchor, error) {
+	data, err := io.ReadAll(pdf)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+	var found []PDFAnchor
+	for i, page := range doc.pages() {
+		runs := doc.textRuns(page)
+		var text strings.Builder
+		starts := make([]int, len(runs))
+		for j, run := range runs {
+			starts[j] = text.Len()
+			text.WriteString(run.text)
+		}
+		joined := text.String()
+		for _, anchor := range anchors {
+			if anchor == "" {
+				continue
+			}
+			for offset := 0; ; {
+				k := strings.Index(joined[offset:], anchor)
+				if k < 0 {
+					break
+				}
+				k += offset
+				run := runAt(starts, k)
+				found = append(found, PDFAnchor{Text: anchor, Page: i + 1, X: runs[run].x, Y: runs[run].y})
+				offset = k + len(anchor)
+			}
+		}
+	}
+	return found, nil
+}
+
+// LocateSignatureFields returns the fields with the Page, X, and Y of anchored fields filled in from a generated pdf.
+// A field whose anchor text appears more than once is returned once for every occurrence.
+func LocateSignatureFields(pdf io.Reader, fields []SignatureField) ([]SignatureField, error) {
+	var anchors []string
+	for _, field := range fields {
+		if field.AnchorText != "" {
+			anchors = append(anchors, field.AnchorText)
+		}
+	}
+	found, err := FindPDFAnchors(pdf, anchors)
+	if err != nil {
+		return nil, err
+	}
+	var located []SignatureField
+	for _, field := range fields {
+		if field.AnchorText == "" {
+			located = append(located, field)
+			continue
+		}
+		matched := false
+		for _, anchor := range found {
+			if anchor.Text == field.AnchorText {
+				placed := field
+				placed.Page, placed.X, placed.Y = anchor.Page, anchor.X, anchor.Y
+				located = append(located, placed)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("docubot: anchor text %q of signature field %q is not in the pdf", field.AnchorText, field.Name)
+		}
+	}
+	return located, nil
+}
+
+// runAt returns the index of the run containing the byte offset
+func runAt(starts []int, offset int) int {
+	run := 0
+	for i, start := range starts {
+		if start > offset {
+			break
+		}
+		run = i
+	}
+	return run
+}
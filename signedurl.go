@@ -0,0 +1,72 @@
+package docubotlib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidURLSignature is returned for document urls that weren't signed with the client's api secret,
+	// or that point somewhere other than docubot, and for every url when the client has no api secret
+	ErrInvalidURLSignature = errors.New("docubot: invalid document url signature")
+	// ErrURLExpired is returned for signed document urls whose expiry has passed
+	ErrURLExpired = errors.New("docubot: document url has expired")
+)
+
+// VerifiedDocumentURL is a document url VerifyDocumentURL found to be signed by docubot
+type VerifiedDocumentURL struct {
+	URL       *url.URL
+	ExpiresAt time.Time
+}
+
+// VerifyDocumentURL checks, without contacting docubot, that a document url received from a third party was signed
+// with the client's api secret, hasn't expired, and points at the client's documents url base. Signed urls carry
+// an "expires" unix time and a "signature", the hex hmac-sha256 of the url's path and query without the signature.
+func (c *Client) VerifyDocumentURL(rawURL string) (*VerifiedDocumentURL, error) {
+	if c.DocubotAPISecret == "" {
+		return nil, ErrInvalidURLSignature
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, ErrInvalidURLSignature
+	}
+	if base, err := url.Parse(c.documentsURLBase()); err != nil || !strings.EqualFold(u.Host, base.Host) || u.Scheme != base.Scheme {
+		return nil, ErrInvalidURLSignature
+	}
+	var signature string
+	var signed []string
+	for _, part := range strings.Split(u.RawQuery, "&") {
+		if strings.HasPrefix(part, "signature=") {
+			if signature != "" {
+				return nil, ErrInvalidURLSignature
+			}
+			signature = strings.TrimPrefix(part, "signature=")
+			continue
+		}
+		signed = append(signed, part)
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return nil, ErrInvalidURLSignature
+	}
+	mac := hmac.New(sha256.New, []byte(c.DocubotAPISecret))
+	mac.Write([]byte(u.EscapedPath() + "?" + strings.Join(signed, "&")))
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return nil, ErrInvalidURLSignature
+	}
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidURLSignature
+	}
+	verified := &VerifiedDocumentURL{URL: u, ExpiresAt: time.Unix(expires, 0)}
+	if !time.Now().Before(verified.ExpiresAt) {
+		return verified, ErrURLExpired
+	}
+	return verified, nil
+}
@@ -0,0 +1,56 @@
+package docubotlib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// signDocumentURL signs a document url the way docubot does
+func signDocumentURL(secret string, base string, path string, expires time.Time) string {
+	query := fmt.Sprintf("user=user&expires=%v", expires.Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(path + "?" + query))
+	return fmt.Sprintf("%v%v?%v&signature=%v", base, path, query, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestVerifyDocumentURL(t *testing.T) {
+	c := NewClient("https://docubot.test", "key", "secret")
+	path := "/api/v1/docubot/thread_1/doc"
+	valid := signDocumentURL("secret", "https://docubot.test", path, time.Now().Add(time.Hour))
+	verified, err := c.VerifyDocumentURL(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.URL.Path != path {
+		t.Errorf("verified path %q, want %q", verified.URL.Path, path)
+	}
+	expired := signDocumentURL("secret", "https://docubot.test", path, time.Now().Add(-time.Minute))
+	if _, err := c.VerifyDocumentURL(expired); err != ErrURLExpired {
+		t.Errorf("got %v for an expired url, want ErrURLExpired", err)
+	}
+	for name, rawURL := range map[string]string{
+		"another secret":  signDocumentURL("other secret", "https://docubot.test", path, time.Now().Add(time.Hour)),
+		"another host":    signDocumentURL("secret", "https://evil.test", path, time.Now().Add(time.Hour)),
+		"http":            signDocumentURL("secret", "http://docubot.test", path, time.Now().Add(time.Hour)),
+		"tampered":        valid + "&thread=thread_2",
+		"two signatures":  valid + "&signature=00",
+		"no signature":    "https://docubot.test" + path + "?user=user&expires=9999999999",
+		"empty signature": "https://docubot.test" + path + "?user=user&expires=9999999999&signature=",
+	} {
+		if _, err := c.VerifyDocumentURL(rawURL); err != ErrInvalidURLSignature {
+			t.Errorf("%v: got %v, want ErrInvalidURLSignature", name, err)
+		}
+	}
+}
+
+func TestVerifyDocumentURLWithoutSecret(t *testing.T) {
+	c := NewClient("https://docubot.test", "key", "")
+	rawURL := signDocumentURL("", "https://docubot.test", "/api/v1/docubot/thread_1/doc", time.Now().Add(time.Hour))
+	if _, err := c.VerifyDocumentURL(rawURL); err != ErrInvalidURLSignature {
+		t.Errorf("got %v for a url signed with an empty secret, want ErrInvalidURLSignature", err)
+	}
+}
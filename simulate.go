@@ -0,0 +1,80 @@
+package docubotlib
+
+import (
+	"errors"
+)
+
+// ErrSimulationComplete is returned when answering a Simulation that has no question left to ask
+var ErrSimulationComplete = errors.New("docubot: simulation is complete")
+
+// Simulation runs an interview of a tree locally, without docubot. Questions are asked in tree order,
+// parents before their children, and a question whose conditions don't hold is skipped with its children.
+type Simulation struct {
+	tree      *DocumentTree
+	variables map[string]interface{}
+	asked     []string
+	// evaluated, when set, is told the outcome of every question's conditions as they are evaluated
+	evaluated func(node *QuestionNode, holds bool, conditions []bool)
+}
+
+// NewSimulation starts a simulated interview of the tree, variables are answered before it starts and may be nil
+func NewSimulation(tree *DocumentTree, variables map[string]interface{}) *Simulation {
+	return &Simulation{tree: tree, variables: copyVariables(variables)}
+}
+
+// Next returns the question the interview asks next, nil once it is complete
+func (s *Simulation) Next() *QuestionNode {
+	if s.tree == nil || s.tree.EntryQuestion == nil {
+		return nil
+	}
+	return s.next(s.tree.EntryQuestion)
+}
+
+func (s *Simulation) next(node *QuestionNode) *QuestionNode {
+	holds := EvaluateConditions(node.LogicalOperator, node.Conditions, s.variables)
+	if s.evaluated != nil {
+		outcomes := make([]bool, len(node.Conditions))
+		for i, condition := range node.Conditions {
+			outcomes[i] = EvaluateCondition(condition, s.variables)
+		}
+		s.evaluated(node, holds, outcomes)
+	}
+	if !holds {
+		return nil
+	}
+	if _, answered := s.variables[node.VariableName]; node.VariableName != "" && !answered {
+		return node
+	}
+	for i := range node.ChildQuestions {
+		if question := s.next(&node.ChildQuestions[i]); question != nil {
+			return question
+		}
+	}
+	return nil
+}
+
+// Answer answers the question Next returns with value
+func (s *Simulation) Answer(value interface{}) error {
+	question := s.Next()
+	if question == nil {
+		return ErrSimulationComplete
+	}
+	s.variables[question.VariableName] = value
+	s.asked = append(s.asked, question.VariableName)
+	return nil
+}
+
+// Complete reports whether every question the interview reaches has been answered
+func (s *Simulation) Complete() bool {
+	return s.Next() == nil
+}
+
+// Variables returns a copy of the variables answered so far
+func (s *Simulation) Variables() map[string]interface{} {
+	return copyVariables(s.variables)
+}
+
+// Asked returns the variables asked for so far, in the order they were asked
+func (s *Simulation) Asked() []string {
+	return append([]string(nil), s.asked...)
+}
@@ -0,0 +1,168 @@
+package docubotlib
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSLOWindow is how far back an SLOTracker looks when its window isn't set
+const defaultSLOWindow time.Duration = 5 * time.Minute
+
+// EndpointSLO is the success rate and latency of one endpoint over an SLOTracker's window
+type EndpointSLO struct {
+	// Endpoint is the method and path of the endpoint, with ids replaced by ":id"
+	Endpoint string
+	Requests int
+	// SuccessRate is the fraction of requests that didn't fail with a transport error, a 429 or a 5xx
+	SuccessRate float64
+	P50         time.Duration
+	P95         time.Duration
+}
+
+type sloSample struct {
+	at      time.Time
+	latency time.Duration
+	ok      bool
+}
+
+// SLOTracker records the success rate and latency of every docubot endpoint the client calls over
+// a sliding window, so a service can shed work or fall back, such as by skipping previews while docubot is slow.
+// Add its Middleware to the client. It is safe for concurrent use.
+type SLOTracker struct {
+	// Window is how far back the tracker looks, 5 minutes when zero
+	Window time.Duration
+	// Endpoint names the endpoint a request is recorded under, the method and the path with ids
+	// replaced by ":id" when nil
+	Endpoint func(req *http.Request) string
+
+	mu      sync.Mutex
+	samples map[string][]sloSample
+}
+
+// NewSLOTracker initializes a tracker looking back over window
+func NewSLOTracker(window time.Duration) *SLOTracker {
+	return &SLOTracker{Window: window}
+}
+
+// Middleware records every request sent through it
+func (t *SLOTracker) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			ok := err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500
+			t.record(t.endpoint(req), sloSample{at: start, latency: time.Since(start), ok: ok})
+			return resp, err
+		})
+	}
+}
+
+func (t *SLOTracker) endpoint(req *http.Request) string {
+	if t.Endpoint != nil {
+		return t.Endpoint(req)
+	}
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, segment := range segments {
+		if sloIDSegment(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return req.Method + " /" + strings.Join(segments, "/")
+}
+
+// sloIDSegment guesses whether a path segment is an id rather than part of the route, ids are
+// long or contain digits, the version segment aside
+func sloIDSegment(segment string) bool {
+	if len(segment) > 24 {
+		return true
+	}
+	if len(segment) == 2 && segment[0] == 'v' {
+		return false
+	}
+	return strings.IndexAny(segment, "0123456789") >= 0
+}
+
+func (t *SLOTracker) window() time.Duration {
+	if t.Window > 0 {
+		return t.Window
+	}
+	return defaultSLOWindow
+}
+
+func (t *SLOTracker) record(endpoint string, sample sloSample) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.samples == nil {
+		t.samples = map[string][]sloSample{}
+	}
+	t.samples[endpoint] = append(t.prune(t.samples[endpoint], time.Now()), sample)
+}
+
+// prune drops the samples older than the window, samples are in the order they started
+func (t *SLOTracker) prune(samples []sloSample, now time.Time) []sloSample {
+	cutoff := now.Add(-t.window())
+	i := 0
+	for i < len(samples) && samples[i].at.Before(cutoff) {
+		i++
+	}
+	return samples[i:]
+}
+
+// Snapshot returns the endpoints called within the window, sorted by endpoint
+func (t *SLOTracker) Snapshot() []EndpointSLO {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	var snapshot []EndpointSLO
+	for endpoint, samples := range t.samples {
+		samples = t.prune(samples, now)
+		t.samples[endpoint] = samples
+		if len(samples) == 0 {
+			delete(t.samples, endpoint)
+			continue
+		}
+		snapshot = append(snapshot, summarizeSLO(endpoint, samples))
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Endpoint < snapshot[j].Endpoint
+	})
+	return snapshot
+}
+
+// EndpointSLO returns the endpoint's slo within the window, the endpoint named as in EndpointSLO.Endpoint
+func (t *SLOTracker) EndpointSLO(endpoint string) EndpointSLO {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	samples := t.prune(t.samples[endpoint], time.Now())
+	if len(samples) == 0 {
+		return EndpointSLO{Endpoint: endpoint}
+	}
+	return summarizeSLO(endpoint, samples)
+}
+
+func summarizeSLO(endpoint string, samples []sloSample) EndpointSLO {
+	latencies := make([]time.Duration, len(samples))
+	succeeded := 0
+	for i, sample := range samples {
+		latencies[i] = sample.latency
+		if sample.ok {
+			succeeded++
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	percentile := func(p int) time.Duration {
+		return latencies[(len(latencies)-1)*p/100]
+	}
+	return EndpointSLO{
+		Endpoint:    endpoint,
+		Requests:    len(samples),
+		SuccessRate: float64(succeeded) / float64(len(samples)),
+		P50:         percentile(50),
+		P95:         percentile(95),
+	}
+}
@@ -0,0 +1,136 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// threadSnapshotVersion is the format version written in new snapshots
+const threadSnapshotVersion int = 1
+
+// ThreadSnapshot is a portable copy of a thread's answers and state, restore it with RestoreThread
+// in the same or another account to reproduce the thread
+type ThreadSnapshot struct {
+	Version   int                    `json:"version"`
+	Thread    Thread                 `json:"thread"`
+	Variables map[string]interface{} `json:"variables"`
+	TakenAt   time.Time              `json:"takenAt"`
+}
+
+// RestoreThreadOptions changes where a snapshot is restored
+type RestoreThreadOptions struct {
+	// User owns the restored thread, the snapshot's user when empty
+	User string
+	// DocumentTreeID is the tree the restored thread uses, the snapshot's tree when empty,
+	// set it when restoring in another account where the tree has a different ID
+	DocumentTreeID string
+}
+
+// GetThread gets the provided user's thread
+func (c *Client) GetThread(ctx context.Context, thread string, user string, callOpts ...CallOption) (*ThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// SnapshotThread takes a snapshot of the provided user's thread
+func (c *Client) SnapshotThread(ctx context.Context, thread string, user string, callOpts ...CallOption) (*ThreadSnapshot, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	got, err := c.GetThread(ctx, thread, user)
+	if err != nil {
+		return nil, err
+	}
+	variables, err := c.getDocubotVariables(ctx, thread, user)
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadSnapshot{
+		Version:   threadSnapshotVersion,
+		Thread:    got.Data.Thread,
+		Variables: variables.Data.Variables,
+		TakenAt:   time.Now().UTC(),
+	}, nil
+}
+
+// RestoreThread starts a new thread with the snapshot's answers, labels, and attributes.
+// The restored thread continues from the first unanswered question. Snapshots hold decrypted answers, the ones
+// the client's VariableEncryption designates are encrypted again for the new thread, see StartThreadOptions.
+func (c *Client) RestoreThread(ctx context.Context, snapshot *ThreadSnapshot, opts RestoreThreadOptions, callOpts ...CallOption) (*StartThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	if snapshot.Version > threadSnapshotVersion {
+		return nil, fmt.Errorf("docubot: thread snapshot version %v is newer than this client supports", snapshot.Version)
+	}
+	user := opts.User
+	if user == "" {
+		user = snapshot.Thread.UserID
+	}
+	docTreeID := opts.DocumentTreeID
+	if docTreeID == "" {
+		docTreeID = snapshot.Thread.DocumentTreeID
+	}
+	return c.StartThread(ctx, docTreeID, user, StartThreadOptions{
+		Variables:  snapshot.Variables,
+		Labels:     snapshot.Thread.Labels,
+		Attributes: snapshot.Thread.Attributes,
+	})
+}
+
+// CloneThread starts a new interview for the provided user prefilled with the answers, labels, and attributes of
+// their thread, such as for the yearly renewal of the same document. overrides replace the answers of their
+// variables, a nil value clears the answer so its question is asked again. The new thread uses the current version
+// of the tree and continues from the first unanswered question, the source thread is unchanged.
+func (c *Client) CloneThread(ctx context.Context, sourceThread string, user string, overrides map[string]interface{}, callOpts ...CallOption) (*StartThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	snapshot, err := c.SnapshotThread(ctx, sourceThread, user)
+	if err != nil {
+		return nil, err
+	}
+	variables := copyVariables(snapshot.Variables)
+	for name, value := range overrides {
+		if value == nil {
+			delete(variables, name)
+			continue
+		}
+		variables[name] = value
+	}
+	snapshot.Variables = variables
+	return c.RestoreThread(ctx, snapshot, RestoreThreadOptions{})
+}
+
+// WriteTo writes the snapshot as json
+func (s *ThreadSnapshot) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadThreadSnapshot reads a snapshot written by ThreadSnapshot.WriteTo
+func ReadThreadSnapshot(r io.Reader) (*ThreadSnapshot, error) {
+	var snapshot ThreadSnapshot
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,53 @@
+package docubotlib
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCloneThreadReencryptsVariables(t *testing.T) {
+	ctx := context.Background()
+	f, c := newFakeDocubot(t)
+	c.VariableEncryption = testEncryption()
+	ciphertext, err := c.VariableEncryption.Encrypt(ctx, "thread_1", testSSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.addThread(Thread{ID: "thread_1", UserID: "user", DocumentTreeID: "tree"}, map[string]interface{}{"ssn": ciphertext, "name": "Jane Roe"})
+
+	snapshot, err := c.SnapshotThread(ctx, "thread_1", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Variables["ssn"] != testSSN {
+		t.Fatalf("snapshot holds %v, want the decrypted answer", snapshot.Variables["ssn"])
+	}
+	restored, err := c.RestoreThread(ctx, snapshot, RestoreThreadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloned, err := c.CloneThread(ctx, "thread_1", "user", map[string]interface{}{"name": "Jane Doe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.sent(testSSN) {
+		t.Fatal("an encrypted variable was sent in plaintext")
+	}
+	for _, thread := range []string{restored.Data.Thread.ID, cloned.Data.Thread.ID} {
+		stored, _ := f.stored(thread)["ssn"].(string)
+		if !strings.HasPrefix(stored, encryptedVariablePrefix) || stored == ciphertext {
+			t.Errorf("%v: stored %q, want ciphertext bound to the new thread", thread, stored)
+		}
+		got, err := c.GetDocubotVariables(thread, "user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Data.Variables["ssn"] != testSSN {
+			t.Errorf("%v: read back %v, want %v", thread, got.Data.Variables["ssn"], testSSN)
+		}
+	}
+	if got := f.stored(cloned.Data.Thread.ID)["name"]; got != "Jane Doe" {
+		t.Errorf("clone has name %v, want the override", got)
+	}
+}
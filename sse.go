@@ -0,0 +1,65 @@
+package docubotlib
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// sseEvent is a single server sent event
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// sseReader reads server sent events from a text/event-stream body
+type sseReader struct {
+	scanner *bufio.Scanner
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	return &sseReader{scanner: scanner}
+}
+
+// Next returns the next event, io.EOF is returned once the stream ends
+func (r *sseReader) Next() (*sseEvent, error) {
+	var event sseEvent
+	var data []string
+	hasField := false
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if !hasField {
+				continue
+			}
+			event.Data = strings.Join(data, "\n")
+			if event.Event == "" {
+				event.Event = "message"
+			}
+			return &event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		hasField = true
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.ID = value
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
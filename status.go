@@ -0,0 +1,161 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// StatusResponse is the response received from docubot's status endpoint
+type StatusResponse struct {
+	Data StatusData             `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// StatusData is the response data received from docubot's status endpoint
+type StatusData struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+// PingResult describes a successful Ping
+type PingResult struct {
+	// Latency is the round trip time of the status request
+	Latency time.Duration
+	// APIVersion is the version of the docubot api that answered
+	APIVersion string
+	// Status is the status reported by docubot, usually "ok"
+	Status string
+}
+
+// Ping checks that docubot is reachable and accepts the client's credentials
+func (c *Client) Ping(ctx context.Context, callOpts ...CallOption) (*PingResult, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/status", c.DocubotAPIURLBase)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	var response StatusResponse
+	if err := c.doJSON(req, nil, &response); err != nil {
+		return nil, err
+	}
+	return &PingResult{
+		Latency:    time.Since(start),
+		APIVersion: response.Data.Version,
+		Status:     response.Data.Status,
+	}, nil
+}
+
+// Capability names reported by docubot in Capabilities.Features
+const (
+	CapabilityWebhooks        string = "webhooks"
+	CapabilityAsyncGeneration string = "asyncGeneration"
+	CapabilityStreaming       string = "streaming"
+	CapabilityAccessiblePDF   string = "accessiblePdf"
+)
+
+// Capabilities describes the optional features supported by the connected docubot instance
+type Capabilities struct {
+	APIVersion string `json:"apiVersion"`
+	// DocumentFormats are the formats documents can be generated in, such as "pdf" and "docx"
+	DocumentFormats []string `json:"documentFormats"`
+	// Locales are the locales interviews can be conducted in
+	Locales []string `json:"locales"`
+	// Features holds the optional features and whether they are enabled
+	Features map[string]bool `json:"features"`
+}
+
+// baselineCapabilities are assumed for docubot instances that predate capability discovery, a fresh value is
+// returned every time since callers may change the capabilities they are handed
+func baselineCapabilities() Capabilities {
+	return Capabilities{
+		DocumentFormats: []string{"pdf"},
+		Locales:         []string{"en-US"},
+		Features:        map[string]bool{},
+	}
+}
+
+// copy returns a deep copy of the capabilities, so the cached ones can't be changed through what callers are handed
+func (c *Capabilities) copy() *Capabilities {
+	copied := *c
+	copied.DocumentFormats = append([]string(nil), c.DocumentFormats...)
+	copied.Locales = append([]string(nil), c.Locales...)
+	copied.Features = make(map[string]bool, len(c.Features))
+	for feature, enabled := range c.Features {
+		copied.Features[feature] = enabled
+	}
+	return &copied
+}
+
+// Supports reports whether the feature is enabled
+func (c *Capabilities) Supports(feature string) bool {
+	return c != nil && c.Features[feature]
+}
+
+// SupportsFormat reports whether documents can be generated in the format
+func (c *Capabilities) SupportsFormat(format string) bool {
+	if c == nil {
+		return false
+	}
+	for _, f := range c.DocumentFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// CapabilitiesResponse is the response received from docubot's capabilities endpoint
+type CapabilitiesResponse struct {
+	Data CapabilitiesData       `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// CapabilitiesData is the response data received from docubot's capabilities endpoint
+type CapabilitiesData struct {
+	Capabilities Capabilities `json:"capabilities"`
+}
+
+// GetCapabilities gets the optional features supported by the connected docubot instance.
+// Instances without capability discovery report the baseline of pdf documents in en-US.
+func (c *Client) GetCapabilities(ctx context.Context, callOpts ...CallOption) (*Capabilities, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/capabilities", c.DocubotAPIURLBase)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response CapabilitiesResponse
+	err = c.doJSON(req, nil, &response)
+	var apiError *APIError
+	if errors.As(err, &apiError) && apiError.StatusCode == http.StatusNotFound {
+		response.Data.Capabilities = baselineCapabilities()
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	capabilities := response.Data.Capabilities
+	if capabilities.Features == nil {
+		capabilities.Features = map[string]bool{}
+	}
+	c.mu.Lock()
+	c.capabilities = capabilities.copy()
+	c.mu.Unlock()
+	return &capabilities, nil
+}
+
+// cachedCapabilities returns the capabilities from the last GetCapabilities call, fetching them if needed
+func (c *Client) cachedCapabilities(ctx context.Context) (*Capabilities, error) {
+	c.mu.Lock()
+	capabilities := c.capabilities
+	c.mu.Unlock()
+	if capabilities != nil {
+		return capabilities.copy(), nil
+	}
+	return c.GetCapabilities(ctx)
+}
@@ -0,0 +1,28 @@
+package docubotlib
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCapabilitiesAreCopied(t *testing.T) {
+	ctx := context.Background()
+	_, c := newFakeDocubot(t)
+	capabilities, err := c.GetCapabilities(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	capabilities.Features[CapabilityDryRun] = true
+	capabilities.DocumentFormats[0] = "docx"
+	cached, err := c.cachedCapabilities(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.Supports(CapabilityDryRun) || !cached.SupportsFormat("pdf") {
+		t.Fatalf("changing the capabilities GetCapabilities returned changed the cached ones: %+v", cached)
+	}
+	cached.Features[CapabilityDryRun] = true
+	if again, _ := c.cachedCapabilities(ctx); again.Supports(CapabilityDryRun) {
+		t.Error("changing the cached capabilities handed out changed the cache")
+	}
+}
@@ -0,0 +1,82 @@
+package docubotlib
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DocumentStore persists documents downloaded from docubot
+type DocumentStore interface {
+	// Put stores the contents of r under key, replacing anything already there
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get opens the document stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// FileStore is a DocumentStore that keeps documents as files inside Dir
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore initializes a file store rooted at dir
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// Put writes the document to a file named key inside the store's directory
+func (s *FileStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".docubot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, contextReader{ctx: ctx, r: r}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the file named key inside the store's directory
+func (s *FileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(s.path(key))
+}
+
+func (s *FileStore) path(key string) string {
+	key = filepath.FromSlash(strings.TrimLeft(key, "/"))
+	return filepath.Join(s.Dir, filepath.Clean(string(filepath.Separator)+key))
+}
+
+// SaveDocubotDoc downloads the docubot document for the thread and writes it to store under key
+func (c *Client) SaveDocubotDoc(ctx context.Context, thread string, user string, store DocumentStore, key string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	doc, err := c.getDocubotDoc(ctx, thread, user)
+	if err != nil {
+		return err
+	}
+	defer doc.Close()
+	return store.Put(ctx, key, doc)
+}
+
+// contextReader stops reading once its context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
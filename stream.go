@@ -0,0 +1,143 @@
+package docubotlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// PreviewChunk is part of a preview reply delivered by a PreviewStream
+type PreviewChunk struct {
+	// Message is the index of the reply message the text belongs to
+	Message int `json:"message"`
+	// Text is appended to the message's text received so far
+	Text string `json:"text"`
+}
+
+// PreviewStream delivers a streamed preview reply as it is produced
+type PreviewStream struct {
+	body     io.ReadCloser
+	events   *sseReader
+	response *PreviewMessageResponse
+	pending  []PreviewChunk
+	// redactor and variables mask sensitive values in streamed errors
+	redactor  *Redactor
+	variables map[string]interface{}
+	client    *Client
+}
+
+// Next returns the next chunk of the reply, io.EOF is returned once the reply is complete
+func (s *PreviewStream) Next() (*PreviewChunk, error) {
+	if len(s.pending) > 0 {
+		chunk := s.pending[0]
+		s.pending = s.pending[1:]
+		return &chunk, nil
+	}
+	if s.events == nil {
+		return nil, io.EOF
+	}
+	for {
+		event, err := s.events.Next()
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch event.Event {
+		case "chunk":
+			var chunk PreviewChunk
+			if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
+				return nil, err
+			}
+			return &chunk, nil
+		case "done":
+			var response PreviewMessageResponse
+			if err := s.client.decodeJSON(strings.NewReader(event.Data), &response); err != nil {
+				return nil, err
+			}
+			s.client.localizeVariables(response.Data.Variables)
+			s.response = &response
+			s.events = nil
+			return nil, io.EOF
+		case "error":
+			var error MessageResponseError
+			json.Unmarshal([]byte(event.Data), &error)
+			apiError := &APIError{StatusCode: http.StatusOK, Code: error.Code, Message: unknownErrorMessage}
+			for _, e := range error.Errors {
+				apiError.Errors = append(apiError.Errors, s.redactor.RedactString(e, s.variables))
+			}
+			if len(apiError.Errors) > 0 {
+				apiError.Message = apiError.Errors[0]
+			}
+			return nil, apiError
+		}
+	}
+}
+
+// Response returns the complete reply once Next has returned io.EOF
+func (s *PreviewStream) Response() *PreviewMessageResponse {
+	return s.response
+}
+
+// Close releases the stream's connection
+func (s *PreviewStream) Close() error {
+	if s.body == nil {
+		return nil
+	}
+	return s.body.Close()
+}
+
+// StreamPreviewMessage sends a preview message to docubot and streams the reply as it is produced.
+// Docubot instances without streaming support deliver each reply message as a single chunk.
+func (c *Client) StreamPreviewMessage(ctx context.Context, message string, variables map[string]interface{}, docTree *DocumentTree, callOpts ...CallOption) (*PreviewStream, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	capabilities, err := c.cachedCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !capabilities.Supports(CapabilityStreaming) {
+		response, err := c.sendPreviewMessage(ctx, message, variables, docTree)
+		if err != nil {
+			return nil, err
+		}
+		stream := &PreviewStream{response: response}
+		for i, m := range response.Data.Messages {
+			stream.pending = append(stream.pending, PreviewChunk{Message: i, Text: m})
+		}
+		return stream, nil
+	}
+	url := fmt.Sprintf("%v/api/v1/preview/stream", c.DocubotPreviewAPIURLBase)
+	req, err := c.newRequest(
+		ctx,
+		"POST",
+		url,
+		map[string]interface{}{
+			"message":   message,
+			"docTree":   docTree,
+			"variables": variables,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, c.responseError(resp, variables)
+	}
+	return &PreviewStream{
+		body:      resp.Body,
+		events:    newSSEReader(resp.Body),
+		redactor:  c.Redactor,
+		variables: variables,
+		client:    c,
+	}, nil
+}
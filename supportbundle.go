@@ -0,0 +1,198 @@
+package docubotlib
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// supportBundleVersion is the format version written to a support bundle's manifest
+const supportBundleVersion int = 1
+
+// SupportBundleOptions customizes WriteSupportBundle
+type SupportBundleOptions struct {
+	// Redactor masks sensitive variables in the bundle, the client's Redactor when nil. When neither is set
+	// every variable's value is masked, set a redactor that allows the variables support needs to see.
+	Redactor *Redactor
+}
+
+// SupportBundleManifest describes the contents of a support bundle
+type SupportBundleManifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Thread    string    `json:"thread"`
+	User      string    `json:"user"`
+	// Files are the names of the files in the bundle
+	Files []string `json:"files"`
+	// Errors maps the files that couldn't be gathered to why, a bundle is written even when some are missing
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// supportBundle writes the files of a bundle, recording the ones that couldn't be gathered
+type supportBundle struct {
+	archive  *tar.Writer
+	manifest *SupportBundleManifest
+}
+
+// fail records why the named file couldn't be gathered
+func (b *supportBundle) fail(name string, err error) {
+	b.manifest.Errors[name] = err.Error()
+}
+
+// add writes v as the named file
+func (b *supportBundle) add(name string, v interface{}) error {
+	if err := writeArchiveJSON(b.archive, name, v); err != nil {
+		return err
+	}
+	b.manifest.Files = append(b.manifest.Files, name)
+	return nil
+}
+
+// WriteSupportBundle writes what docubot support needs to investigate a problematic thread to w as a gzipped tar
+// archive: the thread's state, its redacted variables and answer audit, the tree at the version the thread uses,
+// the recent calls about the thread when the client keeps a CallHistory, the client's Config, and its rate limit
+// status. Attach the archive to the support ticket.
+func (c *Client) WriteSupportBundle(ctx context.Context, w io.Writer, thread string, user string, opts SupportBundleOptions, callOpts ...CallOption) (*SupportBundleManifest, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	redactor := opts.Redactor
+	if redactor == nil {
+		redactor = c.Redactor
+	}
+	if redactor == nil {
+		redactor = NewAllowlistRedactor()
+	}
+	gz := gzip.NewWriter(w)
+	bundle := &supportBundle{
+		archive: tar.NewWriter(gz),
+		manifest: &SupportBundleManifest{
+			Version:   supportBundleVersion,
+			CreatedAt: time.Now().UTC(),
+			Thread:    thread,
+			User:      user,
+			Errors:    map[string]string{},
+		},
+	}
+	got, err := c.GetThread(ctx, thread, user)
+	if err != nil {
+		bundle.fail("thread.json", err)
+	} else {
+		if err := bundle.add("thread.json", got.Data.Thread); err != nil {
+			return nil, err
+		}
+		tree, err := c.GetThreadTree(ctx, &got.Data.Thread)
+		if err != nil {
+			bundle.fail("tree.json", err)
+		} else if err := bundle.add("tree.json", tree.Data.DocumentTree); err != nil {
+			return nil, err
+		}
+	}
+	var threadVariables map[string]interface{}
+	variables, err := c.getDocubotVariables(ctx, thread, user)
+	if err != nil {
+		bundle.fail("variables.json", err)
+	} else {
+		threadVariables = variables.Data.Variables
+		if err := bundle.add("variables.json", redactor.RedactVariables(threadVariables)); err != nil {
+			return nil, err
+		}
+	}
+	audit, err := c.redactedAnswerAudit(ctx, thread, user, redactor)
+	if err != nil {
+		bundle.fail("audit.json", err)
+	} else if err := bundle.add("audit.json", audit); err != nil {
+		return nil, err
+	}
+	if c.CallHistory > 0 {
+		if err := bundle.add("calls.json", c.threadCalls(thread, redactor, threadVariables)); err != nil {
+			return nil, err
+		}
+	}
+	if err := bundle.add("config.json", c.Config()); err != nil {
+		return nil, err
+	}
+	if err := bundle.add("ratelimit.json", c.RateLimitStatus()); err != nil {
+		return nil, err
+	}
+	if err := writeArchiveJSON(bundle.archive, "manifest.json", bundle.manifest); err != nil {
+		return nil, err
+	}
+	if err := bundle.archive.Close(); err != nil {
+		return nil, err
+	}
+	return bundle.manifest, gz.Close()
+}
+
+// redactedAnswerAudit lists the thread's whole answer audit, masking the values of sensitive variables
+func (c *Client) redactedAnswerAudit(ctx context.Context, thread string, user string, redactor *Redactor) ([]AnswerAuditEntry, error) {
+	var entries []AnswerAuditEntry
+	cursor := ""
+	for {
+		page, err := c.ListAnswerAudit(ctx, thread, user, ListOptions{Cursor: cursor})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range page.Data.Entries {
+			if redactor.IsSensitive(entry.VariableName) {
+				entry.Value = redactor.mask()
+			}
+			entries = append(entries, entry)
+		}
+		if cursor = page.Meta.NextCursor; cursor == "" {
+			return entries, nil
+		}
+	}
+}
+
+// threadCalls returns the recent calls whose url or request body mention the thread, with their bodies masked
+// by the bundle's redactor. Without the thread's variables the redactor can't find their values in messages,
+// the bodies are left out.
+func (c *Client) threadCalls(thread string, redactor *Redactor, variables map[string]interface{}) []RecordedCall {
+	calls := []RecordedCall{}
+	for _, call := range c.RecentCalls() {
+		if strings.Contains(call.URL, thread) || strings.Contains(call.RequestBody, thread) {
+			if variables == nil {
+				call.RequestBody, call.ResponseBody = "", ""
+			} else {
+				call.RequestBody = redactBundleBody(call.RequestBody, redactor, variables)
+				call.ResponseBody = redactBundleBody(call.ResponseBody, redactor, variables)
+			}
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// redactBundleBody masks the sensitive variables of the json variables objects in a recorded body, and the
+// values of the thread's sensitive variables wherever else they appear, such as in messages
+func redactBundleBody(body string, redactor *Redactor, variables map[string]interface{}) string {
+	var v interface{}
+	if json.Unmarshal([]byte(body), &v) == nil {
+		if masked, err := json.Marshal(redactVariablesFields(redactor, v)); err == nil {
+			body = string(masked)
+		}
+	}
+	return redactor.RedactString(body, variables)
+}
+
+// redactVariablesFields masks the sensitive variables of the objects in fields named variables, at any depth
+func redactVariablesFields(redactor *Redactor, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if variables, ok := value.(map[string]interface{}); ok && key == "variables" {
+				v[key] = redactor.RedactVariables(variables)
+			} else {
+				v[key] = redactVariablesFields(redactor, value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactVariablesFields(redactor, value)
+		}
+	}
+	return v
+}
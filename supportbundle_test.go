@@ -0,0 +1,53 @@
+package docubotlib
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSupportBundleRedactsCalls(t *testing.T) {
+	ctx := context.Background()
+	f, c := newFakeDocubot(t)
+	c.CallHistory = 20
+	f.addThread(Thread{ID: "thread_1", UserID: "user"}, map[string]interface{}{})
+	variables := map[string]interface{}{"name": "Jane Roe", "birthday": "1980-01-02"}
+	if _, err := c.SetDocubotVariables(ctx, "thread_1", "user", variables); err != nil {
+		t.Fatal(err)
+	}
+	c.SendMessage("1980-01-02", "thread_1", "user", "tree")
+
+	var buf bytes.Buffer
+	if _, err := c.WriteSupportBundle(ctx, &buf, "thread_1", "user", SupportBundleOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := tar.NewReader(gz)
+	found := false
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(archive)
+		found = found || header.Name == "calls.json"
+		for _, value := range []string{"Jane Roe", "1980-01-02"} {
+			if strings.Contains(string(data), value) {
+				t.Errorf("%v has %q", header.Name, value)
+			}
+		}
+	}
+	if !found {
+		t.Error("the bundle has no calls.json")
+	}
+}
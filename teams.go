@@ -0,0 +1,346 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bot Framework endpoints the TeamsAdapter gets its tokens from
+const (
+	teamsTokenURL   string = "https://login.microsoftonline.com/botframework.com/oauth2/v2.0/token"
+	teamsTokenScope string = "https://api.botframework.com/.default"
+)
+
+// teamsServiceHosts are the hosts the Bot Framework sends activities from, replies are only posted to service
+// urls on one of them or a subdomain of one
+var teamsServiceHosts = []string{"smba.trafficmanager.net", "botframework.com", "botframework.azure.us"}
+
+// teamsMentionPattern matches the mentions of the bot Teams puts in messages sent in channels
+var teamsMentionPattern = regexp.MustCompile(`<at>[^<]*</at>`)
+
+// TeamsActivity is a data model for a Bot Framework activity, the messages Teams sends a bot and the bot sends back
+type TeamsActivity struct {
+	Type         string            `json:"type"`
+	ID           string            `json:"id,omitempty"`
+	Text         string            `json:"text,omitempty"`
+	ServiceURL   string            `json:"serviceUrl,omitempty"`
+	From         *TeamsAccount     `json:"from,omitempty"`
+	Conversation *TeamsAccount     `json:"conversation,omitempty"`
+	ReplyToID    string            `json:"replyToId,omitempty"`
+	Attachments  []TeamsAttachment `json:"attachments,omitempty"`
+	// Value is the data of the card button the user pressed
+	Value map[string]interface{} `json:"value,omitempty"`
+}
+
+// TeamsAccount is a data model for a user, bot, or conversation in a TeamsActivity
+type TeamsAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// TeamsAttachment is a data model for a card attached to a TeamsActivity
+type TeamsAttachment struct {
+	ContentType string      `json:"contentType"`
+	Content     interface{} `json:"content"`
+}
+
+// TeamsAdapter runs docubot interviews in Microsoft Teams as the messaging endpoint of a Bot Framework bot.
+// Choices of multiple choice questions are shown as adaptive card buttons and generated documents
+// are sent as cards linking to them. Each Teams conversation is a thread, the Teams user is its user.
+type TeamsAdapter struct {
+	Client         *Client
+	DocumentTreeID string
+	// AppID and AppPassword are the bot's Microsoft app credentials, replies are sent unauthenticated
+	// when they are empty, such as to the Bot Framework Emulator
+	AppID       string
+	AppPassword string
+	// Tree, when set, lets the choices of multiple choice questions be shown as buttons
+	Tree *DocumentTree
+	// DocumentURLDuration is how long document links work, 24 hours when zero
+	DocumentURLDuration time.Duration
+	// Authenticate checks a request comes from the Bot Framework, such as by validating its bearer token, and
+	// rejects the request when it returns an error. Every request is refused while it is nil.
+	Authenticate func(r *http.Request) error
+	// ServiceHosts are hosts trusted to receive replies besides the Bot Framework's, such as "localhost:3978"
+	// for the Bot Framework Emulator. Replies to them may be sent over http.
+	ServiceHosts []string
+	// HTTPClient sends replies to Teams, a default http.Client is used when nil
+	HTTPClient *http.Client
+	// Fallback, when set, is delivered the messages of replies Teams rejects, such as a FallbackChannel
+	// emailing the Teams user, so the interview doesn't stall
+	Fallback MessageChannel
+
+	mu           sync.Mutex
+	token        string
+	tokenExpires time.Time
+}
+
+// NewTeamsAdapter initializes an adapter interviewing Teams users with the tree
+func NewTeamsAdapter(client *Client, docTreeID string, appID string, appPassword string) *TeamsAdapter {
+	return &TeamsAdapter{Client: client, DocumentTreeID: docTreeID, AppID: appID, AppPassword: appPassword}
+}
+
+// ServeHTTP handles an activity Teams sent the bot, replies are posted back to the conversation
+func (a *TeamsAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.Authenticate == nil {
+		a.Client.reportError(errors.New("docubot: teams adapter has no Authenticate callback"))
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if err := a.Authenticate(r); err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var activity TeamsActivity
+	if err := json.NewDecoder(r.Body).Decode(&activity); err != nil {
+		http.Error(w, "invalid activity", http.StatusBadRequest)
+		return
+	}
+	if err := a.checkServiceURL(activity.ServiceURL); err != nil {
+		http.Error(w, "untrusted service url", http.StatusBadRequest)
+		return
+	}
+	if activity.Type == "message" && activity.From != nil && activity.Conversation != nil {
+		if err := a.handle(r.Context(), &activity); err != nil {
+			a.Client.reportError(err)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *TeamsAdapter) handle(ctx context.Context, activity *TeamsActivity) error {
+	answer, _ := activity.Value["answer"].(string)
+	if answer == "" {
+		answer = strings.TrimSpace(teamsMentionPattern.ReplaceAllString(activity.Text, ""))
+	}
+	if answer == "" {
+		return nil
+	}
+	conv := a.Client.NewConversation(activity.Conversation.ID, activity.From.ID, a.DocumentTreeID)
+	conv.IgnorePacing = true
+	conv.Tree = a.Tree
+	var messages []string
+	response, err := conv.Send(ctx, answer, func(message string) error {
+		messages = append(messages, message)
+		return nil
+	})
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		return a.replyAll(ctx, activity, []*TeamsActivity{a.messageActivity(validation.Reprompt)}, []string{validation.Reprompt})
+	}
+	if err != nil {
+		return err
+	}
+	replies := make([]*TeamsActivity, 0, len(messages))
+	for _, message := range messages {
+		replies = append(replies, a.messageActivity(message))
+	}
+	if !response.Data.Complete && a.Tree != nil && len(replies) > 0 {
+		question, _, err := conv.pendingQuestion(ctx)
+		if err != nil {
+			return err
+		}
+		if question != nil && strings.EqualFold(question.EntityType, EntityTypeMultipleChoice) {
+			replies[len(replies)-1] = choiceCard(question, messages[len(messages)-1])
+		}
+	}
+	if response.Data.HasDocument {
+		card, err := a.documentCard(ctx, conv, response)
+		if err != nil {
+			return err
+		}
+		replies = append(replies, card)
+	}
+	return a.replyAll(ctx, activity, replies, messages)
+}
+
+// replyAll posts the replies in order, messages are their texts. When Teams rejects one, the messages of it and
+// the replies after it are delivered through the Fallback instead.
+func (a *TeamsAdapter) replyAll(ctx context.Context, to *TeamsActivity, replies []*TeamsActivity, messages []string) error {
+	for i, reply := range replies {
+		err := a.reply(ctx, to, reply)
+		if err == nil {
+			continue
+		}
+		if a.Fallback == nil || i >= len(messages) {
+			return err
+		}
+		if fallbackErr := a.Fallback.Deliver(ctx, to.From.ID, messages[i:]); fallbackErr != nil {
+			return fmt.Errorf("%w, falling back over %v: %v", err, a.Fallback.Name(), fallbackErr)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (a *TeamsAdapter) messageActivity(text string) *TeamsActivity {
+	return &TeamsActivity{Type: "message", Text: text}
+}
+
+// choiceCard asks a multiple choice question with a button for each choice, a button answers with its choice's key
+func choiceCard(question *QuestionNode, text string) *TeamsActivity {
+	var actions []map[string]interface{}
+	for _, key := range ChoiceKeys(question) {
+		actions = append(actions, map[string]interface{}{
+			"type":  "Action.Submit",
+			"title": question.MetaData.Choices[key],
+			"data":  map[string]string{"answer": key},
+		})
+	}
+	return adaptiveCard(text, actions)
+}
+
+// documentCard links to the documents generated for the conversation's thread
+func (a *TeamsAdapter) documentCard(ctx context.Context, conv *Conversation, response *MessageResponse) (*TeamsActivity, error) {
+	exp := a.DocumentURLDuration
+	if exp <= 0 {
+		exp = 24 * time.Hour
+	}
+	var actions []map[string]interface{}
+	for _, document := range response.Data.Documents {
+		documentURL, err := a.Client.GetThreadDocumentURL(ctx, conv.Thread, conv.Sender, document.ID, exp)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, map[string]interface{}{"type": "Action.OpenUrl", "title": document.Name, "url": documentURL.Data.URL})
+	}
+	if len(actions) == 0 {
+		documentURL, err := a.Client.getDocubotDocURL(ctx, conv.Thread, conv.Sender, exp)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, map[string]interface{}{"type": "Action.OpenUrl", "title": response.Meta.DocumentName, "url": documentURL.Data.URL})
+	}
+	return adaptiveCard("Your document is ready.", actions), nil
+}
+
+func adaptiveCard(text string, actions []map[string]interface{}) *TeamsActivity {
+	return &TeamsActivity{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: map[string]interface{}{
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    []map[string]interface{}{{"type": "TextBlock", "text": text, "wrap": true}},
+				"actions": actions,
+			},
+		}},
+	}
+}
+
+// reply posts an activity to the conversation the incoming activity came from
+func (a *TeamsAdapter) reply(ctx context.Context, to *TeamsActivity, activity *TeamsActivity) error {
+	if err := a.checkServiceURL(to.ServiceURL); err != nil {
+		return err
+	}
+	activity.ReplyToID = to.ID
+	activity.Conversation = to.Conversation
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	replyURL := fmt.Sprintf(
+		"%v/v3/conversations/%v/activities/%v",
+		strings.TrimSuffix(to.ServiceURL, "/"),
+		url.PathEscape(to.Conversation.ID),
+		url.PathEscape(to.ID),
+	)
+	req, err := http.NewRequestWithContext(ctx, "POST", replyURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.AppID != "" {
+		token, err := a.accessToken(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := a.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("docubot: teams rejected reply with status %v", resp.StatusCode)
+	}
+	return nil
+}
+
+// checkServiceURL returns an error unless replies to the service url go to the Bot Framework or one of the
+// ServiceHosts, so the bot's token and document links aren't sent wherever an activity says
+func (a *TeamsAdapter) checkServiceURL(serviceURL string) error {
+	u, err := url.Parse(serviceURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("docubot: invalid teams service url %q", serviceURL)
+	}
+	for _, host := range a.ServiceHosts {
+		if strings.EqualFold(u.Host, host) && (u.Scheme == "https" || u.Scheme == "http") {
+			return nil
+		}
+	}
+	if u.Scheme == "https" && u.Port() == "" {
+		host := strings.ToLower(u.Hostname())
+		for _, trusted := range teamsServiceHosts {
+			if host == trusted || strings.HasSuffix(host, "."+trusted) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("docubot: untrusted teams service url %q", serviceURL)
+}
+
+// accessToken returns a Bot Framework token for the bot's credentials, cached until shortly before it expires
+func (a *TeamsAdapter) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && time.Now().Before(a.tokenExpires) {
+		return a.token, nil
+	}
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", a.AppID)
+	form.Set("client_secret", a.AppPassword)
+	form.Set("scope", teamsTokenScope)
+	req, err := http.NewRequestWithContext(ctx, "POST", teamsTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := a.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("docubot: teams token request failed with status %v", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	a.token = token.AccessToken
+	a.tokenExpires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return a.token, nil
+}
+
+func (a *TeamsAdapter) httpClient() *http.Client {
+	if a.HTTPClient != nil {
+		return a.HTTPClient
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,116 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Template is a data model for an interview in docubot's shared template library
+type Template struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// DocumentTree and Documents are only included when getting a single template
+	DocumentTree *DocumentTree `json:"documentTree,omitempty"`
+	Documents    []Document    `json:"documents,omitempty"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+	CreatedAt    time.Time     `json:"createdAt"`
+}
+
+// ListTemplatesOptions filters the templates returned by ListTemplates
+type ListTemplatesOptions struct {
+	// Category only returns templates in the category when set
+	Category string
+	// Search only returns templates whose name or description match it when set
+	Search string
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	// Limit is the maximum number of templates in the page, the server default is used when zero
+	Limit int
+}
+
+// TemplateResponse is the response received from getting a Template
+type TemplateResponse struct {
+	Data TemplateData           `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// TemplateData is the response data received from getting a Template
+type TemplateData struct {
+	Template Template `json:"template"`
+}
+
+// TemplateListResponse is the response received from listing Templates
+type TemplateListResponse struct {
+	Data TemplateListData `json:"data"`
+	Meta Page             `json:"meta"`
+}
+
+// TemplateListData is the response data received from listing Templates
+type TemplateListData struct {
+	Templates []Template `json:"templates"`
+}
+
+// ListTemplates lists a page of the shared template library
+func (c *Client) ListTemplates(ctx context.Context, opts ListTemplatesOptions, callOpts ...CallOption) (*TemplateListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	if opts.Category != "" {
+		params.Set("category", opts.Category)
+	}
+	if opts.Search != "" {
+		params.Set("search", opts.Search)
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	url := fmt.Sprintf(
+		"%v/api/v1/templates?%v",
+		c.DocubotAPIURLBase,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response TemplateListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetTemplate gets a template of the shared library with its tree and documents
+func (c *Client) GetTemplate(ctx context.Context, id string, callOpts ...CallOption) (*TemplateResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/templates/%v", c.DocubotAPIURLBase, id)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response TemplateResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ImportTemplate copies a template of the shared library into the account, creating its tree and documents
+// with new IDs. opts.NamePrefix namespaces the imported tree, such as by tenant.
+func (c *Client) ImportTemplate(ctx context.Context, id string, opts ImportOptions, callOpts ...CallOption) (*ImportReport, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	template, err := c.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tree := template.Data.Template.DocumentTree
+	if tree == nil {
+		return nil, fmt.Errorf("docubot: template %v has no document tree", id)
+	}
+	report := &ImportReport{Trees: map[string]string{}, Documents: map[string]string{}}
+	return report, c.createTrees(ctx, []DocumentTree{*tree}, template.Data.Template.Documents, opts, report)
+}
@@ -0,0 +1,245 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Thread is a data model
+type Thread struct {
+	ID             string `json:"id"`
+	UserID         string `json:"userId"`
+	DocumentTreeID string `json:"documentTreeId"`
+	DocumentName   string `json:"documentName"`
+	Complete       bool   `json:"complete"`
+	HasDocument    bool   `json:"hasDocument"`
+	// TreeVersion is the version of the tree the thread is pinned to, zero when it follows the tree's edits
+	TreeVersion int `json:"treeVersion,omitempty"`
+	// MergedInto is the ID of the thread a duplicate thread was merged into, the duplicate is closed
+	MergedInto string `json:"mergedInto,omitempty"`
+	// Labels and Attributes tie the thread to records elsewhere, such as a case type and matter number
+	Labels     []string          `json:"labels,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Handoff is set while the bot is paused so a human agent can converse on the thread
+	Handoff   *ThreadHandoff `json:"handoff,omitempty"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// ListThreadsOptions filters the threads returned by ListThreads
+type ListThreadsOptions struct {
+	// User only returns threads belonging to this user when set
+	User string
+	// DocumentTreeID only returns threads for this tree when set
+	DocumentTreeID string
+	// Labels only returns threads that have every one of these labels when set
+	Labels []string
+	// Attributes only returns threads whose attributes have these values when set
+	Attributes map[string]string
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	// Limit is the maximum number of threads in the page, the server default is used when zero
+	Limit int
+}
+
+// ThreadUpdate changes the labels, attributes, and tree version of a thread
+type ThreadUpdate struct {
+	// Labels replaces the thread's labels when not nil, an empty slice removes them all
+	Labels []string `json:"labels,omitempty"`
+	// Attributes are merged into the thread's attributes, an empty value removes the attribute
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// TreeVersion pins the thread to another version of its tree when set, see MigrateThread
+	TreeVersion int `json:"treeVersion,omitempty"`
+}
+
+// ThreadResponse is the response received from updating a thread
+type ThreadResponse struct {
+	Data ThreadData             `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// ThreadData is the response data received from updating a thread
+type ThreadData struct {
+	Thread Thread `json:"thread"`
+}
+
+// ThreadListResponse is the response received from listing threads
+type ThreadListResponse struct {
+	Data ThreadListData `json:"data"`
+	Meta Page           `json:"meta"`
+}
+
+// ThreadListData is the response data received from listing threads
+type ThreadListData struct {
+	Threads []Thread `json:"threads"`
+}
+
+// ListThreads lists a page of threads on the account
+func (c *Client) ListThreads(ctx context.Context, opts ListThreadsOptions, callOpts ...CallOption) (*ThreadListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	if opts.User != "" {
+		params.Set("user", opts.User)
+	}
+	if opts.DocumentTreeID != "" {
+		params.Set("docTreeId", opts.DocumentTreeID)
+	}
+	for _, label := range opts.Labels {
+		params.Add("label", label)
+	}
+	for name, value := range opts.Attributes {
+		params.Set("attributes."+name, value)
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot?%v",
+		c.DocubotAPIURLBase,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// UpdateThread changes the labels, attributes, and tree version of the provided user's thread
+func (c *Client) UpdateThread(ctx context.Context, thread string, user string, update ThreadUpdate, callOpts ...CallOption) (*ThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+	body := map[string]interface{}{}
+	if update.Labels != nil {
+		body["labels"] = update.Labels
+	}
+	if update.Attributes != nil {
+		body["attributes"] = update.Attributes
+	}
+	if update.TreeVersion > 0 {
+		body["treeVersion"] = update.TreeVersion
+	}
+	req, err := c.newRequest(ctx, "PATCH", url, body)
+	if err != nil {
+		return nil, err
+	}
+	var response ThreadResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteThread deletes the provided user's thread
+func (c *Client) DeleteThread(ctx context.Context, thread string, user string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.deleteThreadResource(ctx, thread, user, "")
+}
+
+// DeleteDocubotVariables deletes the variables stored for the provided user in the provided thread
+func (c *Client) DeleteDocubotVariables(ctx context.Context, thread string, user string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.deleteThreadResource(ctx, thread, user, "/variables")
+}
+
+// DeleteDocubotDoc deletes the document generated for the provided user in the provided thread
+func (c *Client) DeleteDocubotDoc(ctx context.Context, thread string, user string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.deleteThreadResource(ctx, thread, user, "/doc")
+}
+
+func (c *Client) deleteThreadResource(ctx context.Context, thread string, user string, resource string) error {
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v%v?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		resource,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// StartThreadOptions configures the thread created by StartThread
+type StartThreadOptions struct {
+	// Variables are answered before the interview starts, questions for them are skipped. Variables the client's
+	// VariableEncryption designates are encrypted and written right after the thread is created, the opening
+	// Messages are docubot's from before they were written.
+	Variables map[string]interface{}
+	// Labels and Attributes are set on the thread as it is created
+	Labels     []string
+	Attributes map[string]string
+	// TreeVersion pins the thread to a version of the tree, such as the current DocumentTree.Version,
+	// so later edits to the tree don't change the interview or the document it generates
+	TreeVersion int
+}
+
+// StartThreadResponse is the response received from starting a thread
+type StartThreadResponse struct {
+	Data StartThreadData     `json:"data"`
+	Meta MessageResponseMeta `json:"meta"`
+}
+
+// StartThreadData is the response data received from starting a thread
+type StartThreadData struct {
+	Thread Thread `json:"thread"`
+	// Messages are docubot's opening messages, ending with the first question
+	Messages []string `json:"messages"`
+	Complete bool     `json:"complete"`
+}
+
+// StartThread creates a thread for the user on the server, the returned thread ID is the one to pass to SendMessage
+func (c *Client) StartThread(ctx context.Context, docTreeID string, user string, opts StartThreadOptions, callOpts ...CallOption) (*StartThreadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	body := map[string]interface{}{
+		"docTreeId": docTreeID,
+		"user":      user,
+	}
+	// encrypted answers are bound to the thread, so they are written once it exists
+	variables, encrypted := c.VariableEncryption.splitVariables(opts.Variables)
+	if variables != nil {
+		body["variables"] = variables
+	}
+	if opts.Labels != nil {
+		body["labels"] = opts.Labels
+	}
+	if opts.Attributes != nil {
+		body["attributes"] = opts.Attributes
+	}
+	if opts.TreeVersion > 0 {
+		body["treeVersion"] = opts.TreeVersion
+	}
+	url := fmt.Sprintf("%v/api/v1/docubot/threads", c.DocubotAPIURLBase)
+	req, err := c.newRequest(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	var response StartThreadResponse
+	if err := c.doJSON(req, opts.Variables, &response); err != nil {
+		return &response, err
+	}
+	if len(encrypted) > 0 {
+		if _, err := c.setDocubotVariables(ctx, response.Data.Thread.ID, user, encrypted); err != nil {
+			return &response, fmt.Errorf("docubot: writing encrypted variables of thread %v: %w", response.Data.Thread.ID, err)
+		}
+	}
+	return &response, nil
+}
@@ -0,0 +1,73 @@
+package docubotlib
+
+import (
+	"time"
+)
+
+// EntityTypeDateTime is the entity type of questions answered with a date and time
+const EntityTypeDateTime string = "datetime"
+
+// canonicalDateTimeLayout is the form docubot expects datetime answers in
+const canonicalDateTimeLayout string = time.RFC3339
+
+// DateAnswerIn returns the message that answers a date question with the day t falls on in loc,
+// so that an instant just after midnight UTC is still the previous day for a user on the west coast
+func DateAnswerIn(t time.Time, loc *time.Location) string {
+	return DateAnswer(t.In(loc))
+}
+
+// DateTimeAnswer returns the message that answers a datetime question with t written in loc
+func DateTimeAnswer(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(canonicalDateTimeLayout)
+}
+
+// NormalizeDateVariables returns a copy of variables with the answers to the tree's date and datetime questions
+// converted to loc. Date answers sent as instants become the day they fall on in loc and datetime answers are
+// written with loc's offset, other variables are copied as they are.
+func NormalizeDateVariables(tree *DocumentTree, variables map[string]interface{}, loc *time.Location) map[string]interface{} {
+	normalized := copyVariables(variables)
+	tree.Walk(func(question *QuestionNode) {
+		t, ok := variableTime(normalized[question.VariableName])
+		if !ok {
+			return
+		}
+		switch question.EntityType {
+		case EntityTypeDate:
+			normalized[question.VariableName] = DateAnswerIn(t, loc)
+		case EntityTypeDateTime:
+			normalized[question.VariableName] = DateTimeAnswer(t, loc)
+		}
+	})
+	return normalized
+}
+
+// localizeVariables writes the datetime values in variables with the client's Location, in place
+func (c *Client) localizeVariables(variables map[string]interface{}) {
+	if c.Location == nil {
+		return
+	}
+	for name, v := range variables {
+		if s, ok := v.(string); ok {
+			if t, err := time.Parse(canonicalDateTimeLayout, s); err == nil {
+				variables[name] = DateTimeAnswer(t, c.Location)
+			}
+		}
+	}
+}
+
+// variableTime returns the instant held by a variable, dates without a time aren't instants
+func variableTime(v interface{}) (time.Time, bool) {
+	switch value := v.(type) {
+	case time.Time:
+		return value, true
+	case *time.Time:
+		if value != nil {
+			return *value, true
+		}
+	case string:
+		if t, err := time.Parse(canonicalDateTimeLayout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
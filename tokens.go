@@ -0,0 +1,103 @@
+package docubotlib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Errors returned when verifying thread tokens
+var (
+	ErrInvalidToken = errors.New("docubot: invalid token")
+	ErrTokenExpired = errors.New("docubot: token expired")
+	ErrTokenScope   = errors.New("docubot: token is not valid for this thread")
+)
+
+// ThreadTokenClaims are the claims carried by a thread token
+type ThreadTokenClaims struct {
+	ThreadID  string `json:"thread"`
+	UserID    string `json:"user"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Expiry returns when the token stops being valid
+func (c ThreadTokenClaims) Expiry() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// Allows reports whether the claims grant access to the user's thread
+func (c ThreadTokenClaims) Allows(thread string, user string) bool {
+	return c.ThreadID == thread && c.UserID == user
+}
+
+// MintThreadToken creates a token that grants access to a single user's thread until ttl elapses.
+// Tokens are signed with the client's api secret, hand them to untrusted clients such as browsers
+// and verify them in a proxy with VerifyThreadToken before calling docubot on their behalf.
+func (c *Client) MintThreadToken(thread string, user string, ttl time.Duration) (string, error) {
+	if c.DocubotAPISecret == "" {
+		return "", errors.New("docubot: thread tokens need the client's api secret")
+	}
+	if ttl <= 0 {
+		return "", errors.New("docubot: token ttl must be positive")
+	}
+	now := time.Now()
+	claims, err := json.Marshal(ThreadTokenClaims{
+		ThreadID:  thread,
+		UserID:    user,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + c.signToken(payload), nil
+}
+
+// VerifyThreadToken checks a token minted by MintThreadToken and returns its claims, every token is invalid
+// to a client without an api secret
+func (c *Client) VerifyThreadToken(token string) (*ThreadTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 || c.DocubotAPISecret == "" {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[1]), []byte(c.signToken(parts[0]))) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims ThreadTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !time.Now().Before(claims.Expiry()) {
+		return nil, ErrTokenExpired
+	}
+	return &claims, nil
+}
+
+// VerifyThreadTokenFor checks a token and that it grants access to the user's thread
+func (c *Client) VerifyThreadTokenFor(token string, thread string, user string) (*ThreadTokenClaims, error) {
+	claims, err := c.VerifyThreadToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.Allows(thread, user) {
+		return nil, ErrTokenScope
+	}
+	return claims, nil
+}
+
+func (c *Client) signToken(payload string) string {
+	mac := hmac.New(sha256.New, []byte(c.DocubotAPISecret))
+	mac.Write([]byte("thread-token:"))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
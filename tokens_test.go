@@ -0,0 +1,71 @@
+package docubotlib
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signedToken signs claims the way MintThreadToken does, with whatever secret c has
+func signedToken(t *testing.T, c *Client, claims ThreadTokenClaims) string {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + c.signToken(payload)
+}
+
+func TestThreadToken(t *testing.T) {
+	c := NewClient("https://docubot.test", "key", "secret")
+	token, err := c.MintThreadToken("thread_1", "user", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := c.VerifyThreadTokenFor(token, "thread_1", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ThreadID != "thread_1" || claims.UserID != "user" {
+		t.Errorf("got claims %+v", claims)
+	}
+	if _, err := c.VerifyThreadTokenFor(token, "thread_2", "user"); err != ErrTokenScope {
+		t.Errorf("got %v for another thread, want ErrTokenScope", err)
+	}
+
+	expired := signedToken(t, c, ThreadTokenClaims{ThreadID: "thread_1", UserID: "user", ExpiresAt: time.Now().Add(-time.Second).Unix()})
+	if _, err := c.VerifyThreadToken(expired); err != ErrTokenExpired {
+		t.Errorf("got %v for an expired token, want ErrTokenExpired", err)
+	}
+	other := NewClient("https://docubot.test", "key", "other secret")
+	forged := signedToken(t, other, ThreadTokenClaims{ThreadID: "thread_1", UserID: "user", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	parts := strings.Split(token, ".")
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"thread":"thread_2","user":"user","exp":9999999999}`)) + "." + parts[1]
+	for name, token := range map[string]string{
+		"another secret": forged,
+		"tampered":       tampered,
+		"no signature":   parts[0],
+		"extra part":     token + ".x",
+		"empty":          "",
+	} {
+		if _, err := c.VerifyThreadToken(token); err != ErrInvalidToken {
+			t.Errorf("%v: got %v, want ErrInvalidToken", name, err)
+		}
+	}
+	if _, err := c.MintThreadToken("thread_1", "user", 0); err == nil {
+		t.Error("minted a token without a ttl")
+	}
+}
+
+func TestThreadTokenWithoutSecret(t *testing.T) {
+	c := NewClient("https://docubot.test", "key", "")
+	if _, err := c.MintThreadToken("thread_1", "user", time.Minute); err == nil {
+		t.Error("minted a token without an api secret")
+	}
+	token := signedToken(t, c, ThreadTokenClaims{ThreadID: "thread_1", UserID: "user", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if _, err := c.VerifyThreadToken(token); err != ErrInvalidToken {
+		t.Errorf("got %v for a token signed with an empty secret, want ErrInvalidToken", err)
+	}
+}
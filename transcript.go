@@ -0,0 +1,160 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TranscriptResponse is the response received from listing a page of a thread's transcript
+type TranscriptResponse struct {
+	Data TranscriptData `json:"data"`
+	Meta Page           `json:"meta"`
+}
+
+// TranscriptData is the response data received from listing a page of a thread's transcript
+type TranscriptData struct {
+	Messages []TranscriptMessage `json:"messages"`
+}
+
+// Turn is a question a thread asked and the answer it accepted
+type Turn struct {
+	// VariableName is the variable the question asks for
+	VariableName string
+	// Question is the text of docubot's messages asking it, joined by newlines
+	Question string
+	// Answer is the accepted answer, or the last answer given while the question is still open
+	Answer string
+	// Answered is false for the question the thread is waiting on
+	Answered bool
+	// Role and Sender are who gave the answer
+	Role   SenderRole
+	Sender string
+	// Retries is how many answers the question rejected before accepting one, RejectedAnswers are those answers
+	Retries         int
+	RejectedAnswers []string
+	AskedAt         time.Time
+	AnsweredAt      time.Time
+}
+
+// TurnIterator delivers a thread's turns in the order they were asked, listing the transcript as it goes
+type TurnIterator struct {
+	client *Client
+	ctx    context.Context
+	thread string
+	user   string
+	// messages are the listed messages not yet assembled, cursor the next page's when more is listed
+	messages []TranscriptMessage
+	cursor   string
+	listed   bool
+	turn     *Turn
+}
+
+// GetThreadTranscript lists a page of the messages of the provided user's thread, oldest first
+func (c *Client) GetThreadTranscript(ctx context.Context, thread string, user string, opts ListOptions, callOpts ...CallOption) (*TranscriptResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := opts.params()
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/transcript?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response TranscriptResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ThreadHistory returns an iterator over the question and answer turns of the provided user's thread, assembled
+// from its transcript. A question docubot asks again after an answer counts that answer as rejected.
+func (c *Client) ThreadHistory(ctx context.Context, thread string, user string, callOpts ...CallOption) *TurnIterator {
+	return &TurnIterator{client: c, ctx: withCallOptions(ctx, callOpts), thread: thread, user: user}
+}
+
+// Next returns the next turn, io.EOF is returned once every turn has been delivered. The last turn is the
+// question the thread is waiting on, unless it is complete.
+func (it *TurnIterator) Next() (*Turn, error) {
+	for {
+		message, err := it.message()
+		if err == io.EOF && it.turn != nil {
+			turn := it.turn
+			it.turn = nil
+			return turn, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if turn := it.add(message); turn != nil {
+			return turn, nil
+		}
+	}
+}
+
+// All returns every remaining turn
+func (it *TurnIterator) All() ([]Turn, error) {
+	var turns []Turn
+	for {
+		turn, err := it.Next()
+		if err == io.EOF {
+			return turns, nil
+		}
+		if err != nil {
+			return turns, err
+		}
+		turns = append(turns, *turn)
+	}
+}
+
+// message returns the next transcript message, listing the next page when the listed ones are used up
+func (it *TurnIterator) message() (TranscriptMessage, error) {
+	for len(it.messages) == 0 {
+		if it.listed {
+			return TranscriptMessage{}, io.EOF
+		}
+		page, err := it.client.GetThreadTranscript(it.ctx, it.thread, it.user, ListOptions{Cursor: it.cursor})
+		if err != nil {
+			return TranscriptMessage{}, err
+		}
+		it.messages = page.Data.Messages
+		it.cursor = page.Meta.NextCursor
+		it.listed = page.Meta.Last()
+	}
+	message := it.messages[0]
+	it.messages = it.messages[1:]
+	return message, nil
+}
+
+// add assembles the message into the current turn, returning the previous turn once a new question starts
+func (it *TurnIterator) add(message TranscriptMessage) *Turn {
+	current := it.turn
+	if message.Role != SenderBot {
+		if current != nil && (message.VariableName == "" || message.VariableName == current.VariableName) {
+			current.Answer = message.Text
+			current.Answered = true
+			current.Role, current.Sender = message.Role, message.Sender
+			current.AnsweredAt = message.SentAt
+		}
+		return nil
+	}
+	switch {
+	case message.VariableName == "":
+		// acknowledgements and other messages that don't ask anything
+		return nil
+	case current != nil && current.VariableName == message.VariableName && current.Answered:
+		current.Retries++
+		current.RejectedAnswers = append(current.RejectedAnswers, current.Answer)
+		current.Answered = false
+		return nil
+	case current != nil && current.VariableName == message.VariableName:
+		current.Question += "\n" + message.Text
+		return nil
+	}
+	it.turn = &Turn{VariableName: message.VariableName, Question: message.Text, AskedAt: message.SentAt}
+	return current
+}
@@ -0,0 +1,41 @@
+package docubotlib
+
+import "context"
+
+// VariableTransform rewrites the variables a document is generated from, such as formatting values,
+// deriving new ones, or adding firm details. It is given a copy it may change and returns the variables to use.
+type VariableTransform func(ctx context.Context, variables map[string]interface{}) (map[string]interface{}, error)
+
+// SetVariable returns a transform that sets the variable to value, replacing any answer
+func SetVariable(name string, value interface{}) VariableTransform {
+	return func(ctx context.Context, variables map[string]interface{}) (map[string]interface{}, error) {
+		variables[name] = value
+		return variables, nil
+	}
+}
+
+// DeriveVariable returns a transform that sets the variable to what derive computes from the other variables
+func DeriveVariable(name string, derive func(variables map[string]interface{}) interface{}) VariableTransform {
+	return func(ctx context.Context, variables map[string]interface{}) (map[string]interface{}, error) {
+		variables[name] = derive(variables)
+		return variables, nil
+	}
+}
+
+// transformVariables runs the client's transforms over a copy of the variables, the caller's map is unchanged
+func (c *Client) transformVariables(ctx context.Context, variables map[string]interface{}) (map[string]interface{}, error) {
+	if len(c.Transforms) == 0 {
+		return variables, nil
+	}
+	transformed := copyVariables(variables)
+	for _, transform := range c.Transforms {
+		var err error
+		if transformed, err = transform(ctx, transformed); err != nil {
+			return nil, err
+		}
+		if transformed == nil {
+			transformed = map[string]interface{}{}
+		}
+	}
+	return transformed, nil
+}
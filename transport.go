@@ -0,0 +1,150 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultRetryBackoff is the delay before the first retry when Client.RetryBackoff isn't set
+const defaultRetryBackoff time.Duration = 500 * time.Millisecond
+
+// Middleware wraps the transport that sends the client's requests, to log, trace, or change them
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is a function that implements http.RoundTripper, for writing Middleware
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Do sends a request to a docubot endpoint that has no typed method yet, such as a newly released one.
+// path is relative to DocubotAPIURLBase, like "/api/v1/status". The request gets the client's auth, call options,
+// retries, middleware, and error mapping, body is encoded with the client's Encoder when not nil, and a successful response is
+// decoded into into when not nil, an *Envelope keeps its data and meta undecoded.
+func (c *Client) Do(ctx context.Context, method string, path string, body interface{}, into interface{}, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := strings.TrimSuffix(c.DocubotAPIURLBase, "/") + "/" + strings.TrimPrefix(path, "/")
+	req, err := c.newRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, into)
+}
+
+// httpClient returns the http client for a request, with the transport wrapped in the client's middleware
+func (c *Client) httpClient() *http.Client {
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	if len(c.Middleware) == 0 {
+		return client
+	}
+	wrapped := *client
+	transport := wrapped.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		transport = c.Middleware[i](transport)
+	}
+	wrapped.Transport = transport
+	return &wrapped
+}
+
+// sendWithRetries sends the request, sending it again up to MaxRetries times while it fails with a retryable error
+func (c *Client) sendWithRetries(req *http.Request) (*http.Response, error) {
+	backoff := c.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	maxRetries := c.resolveCallOptions(req.Context()).maxRetries
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(req)
+		if attempt >= maxRetries || !canRetry(req) || !(retryableResponse(resp, err) || attemptTimedOut(req, err)) {
+			return resp, err
+		}
+		wait := backoff
+		if resp != nil {
+			if status, ok := parseRateLimitStatus(resp.Header); ok && resp.StatusCode == http.StatusTooManyRequests && status.Reset.After(time.Now()) {
+				wait = time.Until(status.Reset)
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			// the previous attempt may have read some or all of the body
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		backoff *= 2
+	}
+}
+
+// retries reports whether calls made with ctx are retried, calls that are need an idempotency key
+func (c *Client) retries(ctx context.Context) bool {
+	return c.resolveCallOptions(ctx).maxRetries > 0
+}
+
+// setReplayableBody sets the request's body so that it can be sent again on retry
+func setReplayableBody(req *http.Request, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	if len(body) == 0 {
+		req.Body = http.NoBody
+	}
+}
+
+// canRetry reports whether sending the request again is safe: its body can be replayed and
+// repeating it can't record anything twice. Streamed bodies, like file uploads, are never retried
+// since a retry could send whatever is left of a partly read stream.
+func canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+func retryableResponse(resp *http.Response, err error) bool {
+	if err != nil {
+		return IsRetryable(err)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// attemptTimedOut reports whether err is the WithTimeout of one attempt running out, while the call can go on
+func attemptTimedOut(req *http.Request, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) && req.Context().Err() == nil
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
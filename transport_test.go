@@ -0,0 +1,97 @@
+package docubotlib
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with a 503 and answers the rest with response
+type flakyServer struct {
+	failures int
+	response string
+
+	mu       sync.Mutex
+	bodies   []string
+	keys     []string
+	attempts int
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.attempts++
+	attempt := s.attempts
+	s.bodies = append(s.bodies, string(body))
+	s.keys = append(s.keys, r.Header.Get("Idempotency-Key"))
+	s.mu.Unlock()
+	if attempt <= s.failures {
+		http.Error(w, `{"errors":["unavailable"]}`, http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, s.response)
+}
+
+func newRetryingClient(t *testing.T, s *flakyServer) *Client {
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	c := NewClient(server.URL, "key", "secret")
+	c.MaxRetries = 3
+	c.RetryBackoff = time.Millisecond
+	return c
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	s := &flakyServer{failures: 2, response: `{}`}
+	c := newRetryingClient(t, s)
+	body := map[string]interface{}{"value": strings.Repeat("x", 64<<10)}
+	if err := c.Do(context.Background(), "PUT", "/api/v1/test", body, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.attempts != 3 {
+		t.Fatalf("got %v attempts, want 3", s.attempts)
+	}
+	for i, sent := range s.bodies {
+		if sent != s.bodies[0] || !strings.Contains(sent, body["value"].(string)) {
+			t.Errorf("attempt %v sent a body of %v bytes, want the full %v bytes", i+1, len(sent), len(s.bodies[0]))
+		}
+	}
+}
+
+func TestRetrySkipsStreamedBody(t *testing.T) {
+	s := &flakyServer{failures: 1, response: `{}`}
+	c := newRetryingClient(t, s)
+	_, err := c.UploadFile(context.Background(), "thread", "user", "signature", "signature.png", "image/png", strings.NewReader("png"))
+	if err == nil {
+		t.Fatal("got no error, want the 503")
+	}
+	if s.attempts != 1 {
+		t.Fatalf("got %v attempts of a streamed upload, want 1", s.attempts)
+	}
+}
+
+func TestSendMessageReusesIdempotencyKey(t *testing.T) {
+	s := &flakyServer{failures: 2, response: `{"data":{"messages":["Hello"]},"meta":{"threadId":"thread"}}`}
+	c := newRetryingClient(t, s)
+	response, err := c.SendMessage("hi", "thread", "user", "tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data.Messages) != 1 {
+		t.Errorf("got messages %v, want [Hello]", response.Data.Messages)
+	}
+	if s.attempts != 3 {
+		t.Fatalf("got %v attempts, want 3", s.attempts)
+	}
+	for i, key := range s.keys {
+		if key == "" || key != s.keys[0] {
+			t.Errorf("attempt %v sent idempotency key %q, want %q on every attempt", i+1, key, s.keys[0])
+		}
+	}
+}
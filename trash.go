@@ -0,0 +1,99 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Kinds of TrashItem
+const (
+	TrashKindTree     string = "tree"
+	TrashKindDocument string = "document"
+)
+
+// TrashItem is a data model for a tree or Document template in the trash, it can be restored until it is purged
+type TrashItem struct {
+	ID string `json:"id"`
+	// Kind is what was deleted, such as TrashKindTree, and ResourceID its ID
+	Kind       string    `json:"kind"`
+	ResourceID string    `json:"resourceId"`
+	Name       string    `json:"name,omitempty"`
+	DeletedAt  time.Time `json:"deletedAt"`
+	// PurgeAt is when docubot purges the item for good, zero when it is kept until purged
+	PurgeAt time.Time `json:"purgeAt"`
+}
+
+// TrashItemResponse is the response received from moving something to the trash or restoring it
+type TrashItemResponse struct {
+	Data TrashItemData          `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// TrashItemData is the response data received from moving something to the trash or restoring it
+type TrashItemData struct {
+	Item TrashItem `json:"item"`
+}
+
+// TrashListResponse is the response received from listing the trash
+type TrashListResponse struct {
+	Data TrashListData `json:"data"`
+	Meta Page          `json:"meta"`
+}
+
+// TrashListData is the response data received from listing the trash
+type TrashListData struct {
+	Items []TrashItem `json:"items"`
+}
+
+// TrashDocumentTree moves a DocumentTree to the trash, it stops serving interviews but can be restored
+func (c *Client) TrashDocumentTree(ctx context.Context, docTreeID string, callOpts ...CallOption) (*TrashItemResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.trash(ctx, fmt.Sprintf("%v/api/v1/trees/%v/trash", c.DocubotAPIURLBase, docTreeID))
+}
+
+// TrashDocument moves a Document template to the trash, it can be restored
+func (c *Client) TrashDocument(ctx context.Context, documentID string, callOpts ...CallOption) (*TrashItemResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.trash(ctx, fmt.Sprintf("%v/api/v1/documents/%v/trash", c.DocubotAPIURLBase, documentID))
+}
+
+// RestoreFromTrash restores a trashed tree or Document template with its original ID
+func (c *Client) RestoreFromTrash(ctx context.Context, itemID string, callOpts ...CallOption) (*TrashItemResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	return c.trash(ctx, fmt.Sprintf("%v/api/v1/trash/%v/restore", c.DocubotAPIURLBase, itemID))
+}
+
+func (c *Client) trash(ctx context.Context, url string) (*TrashItemResponse, error) {
+	req, err := c.newRequest(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response TrashItemResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ListTrash lists a page of the trees and Document templates in the trash, most recently deleted first
+func (c *Client) ListTrash(ctx context.Context, opts ListOptions, callOpts ...CallOption) (*TrashListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trash?%v", c.DocubotAPIURLBase, opts.params().Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response TrashListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// PurgeTrash deletes a trashed item for good, it can't be restored afterwards
+func (c *Client) PurgeTrash(ctx context.Context, itemID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trash/%v", c.DocubotAPIURLBase, itemID)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
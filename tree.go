@@ -0,0 +1,42 @@
+package docubotlib
+
+// Walk calls fn for every question in the tree, parents before their children
+func (t *DocumentTree) Walk(fn func(node *QuestionNode)) {
+	if t == nil || t.EntryQuestion == nil {
+		return
+	}
+	t.EntryQuestion.Walk(fn)
+}
+
+// Walk calls fn for the question and every question below it, parents before their children
+func (n *QuestionNode) Walk(fn func(node *QuestionNode)) {
+	fn(n)
+	for i := range n.ChildQuestions {
+		n.ChildQuestions[i].Walk(fn)
+	}
+}
+
+// Variables returns the names of the variables asked for by the tree's questions, in tree order
+func (t *DocumentTree) Variables() []string {
+	var variables []string
+	seen := map[string]bool{}
+	t.Walk(func(node *QuestionNode) {
+		if node.VariableName == "" || seen[node.VariableName] {
+			return
+		}
+		seen[node.VariableName] = true
+		variables = append(variables, node.VariableName)
+	})
+	return variables
+}
+
+// Question returns the first question that asks for the variable, nil if there isn't one
+func (t *DocumentTree) Question(variable string) *QuestionNode {
+	var question *QuestionNode
+	t.Walk(func(node *QuestionNode) {
+		if question == nil && node.VariableName == variable {
+			question = node
+		}
+	})
+	return question
+}
@@ -0,0 +1,158 @@
+package docubotlib
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultTreeCacheTTL is how long a TreeCache keeps a tree when its TTL isn't set
+const defaultTreeCacheTTL time.Duration = 5 * time.Minute
+
+// treeCacheKey identifies a cached tree, version zero is the tree's latest version
+type treeCacheKey struct {
+	id      string
+	version int
+}
+
+type treeCacheEntry struct {
+	tree    *DocumentTree
+	expires time.Time
+}
+
+// treeLoad is a fetch of a tree in flight, callers asking for the same tree wait on it
+type treeLoad struct {
+	done chan struct{}
+	tree *DocumentTree
+	err  error
+}
+
+// TreeCache keeps DocumentTrees in memory so simulations, renders, and conversations don't fetch a tree
+// on every message. Concurrent misses for the same tree share one fetch. Trees are kept for TTL, or until
+// they are invalidated, such as by passing EventTreeUpdated webhook events to HandleEvent.
+// It is safe for concurrent use.
+type TreeCache struct {
+	Client *Client
+	// TTL is how long a tree is kept, 5 minutes when zero. A version of a tree never changes,
+	// TTL only bounds how long it stays in memory.
+	TTL time.Duration
+	// OnInvalidate, when set, is called with the ID of every tree invalidated
+	OnInvalidate func(docTreeID string)
+
+	mu      sync.Mutex
+	entries map[treeCacheKey]treeCacheEntry
+	loads   map[treeCacheKey]*treeLoad
+}
+
+// NewTreeCache initializes a cache fetching trees with client and keeping them for ttl
+func NewTreeCache(client *Client, ttl time.Duration) *TreeCache {
+	return &TreeCache{Client: client, TTL: ttl}
+}
+
+// Get returns the tree's latest version, fetching it when it isn't cached. The returned tree is shared, don't change it.
+func (t *TreeCache) Get(ctx context.Context, docTreeID string) (*DocumentTree, error) {
+	return t.get(ctx, treeCacheKey{id: docTreeID})
+}
+
+// GetVersion returns the tree as it was at the version, fetching it when it isn't cached
+func (t *TreeCache) GetVersion(ctx context.Context, docTreeID string, version int) (*DocumentTree, error) {
+	return t.get(ctx, treeCacheKey{id: docTreeID, version: version})
+}
+
+// ThreadTree returns the tree the thread interviews with, at the version it is pinned to when it is pinned
+func (t *TreeCache) ThreadTree(ctx context.Context, thread *Thread) (*DocumentTree, error) {
+	return t.GetVersion(ctx, thread.DocumentTreeID, thread.TreeVersion)
+}
+
+func (t *TreeCache) get(ctx context.Context, key treeCacheKey) (*DocumentTree, error) {
+	t.mu.Lock()
+	if entry, ok := t.entries[key]; ok && time.Now().Before(entry.expires) {
+		t.mu.Unlock()
+		return entry.tree, nil
+	}
+	load, loading := t.loads[key]
+	if !loading {
+		load = &treeLoad{done: make(chan struct{})}
+		if t.loads == nil {
+			t.loads = map[treeCacheKey]*treeLoad{}
+		}
+		t.loads[key] = load
+		go t.load(ctx, key, load)
+	}
+	t.mu.Unlock()
+	select {
+	case <-load.done:
+		return load.tree, load.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load fetches a tree for every caller waiting on it. It keeps the first caller's call options
+// but not its cancellation, so one caller giving up doesn't fail the others.
+func (t *TreeCache) load(ctx context.Context, key treeCacheKey, load *treeLoad) {
+	defer close(load.done)
+	load.err = t.Client.recoverCall("tree cache", func() error {
+		opts, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+		fetch := withCallOptions(context.Background(), opts)
+		var response *DocumentTreeResponse
+		var err error
+		if key.version > 0 {
+			response, err = t.Client.GetDocumentTreeVersion(fetch, key.id, key.version)
+		} else {
+			response, err = t.Client.GetDocumentTree(fetch, key.id)
+		}
+		if err != nil {
+			return err
+		}
+		load.tree = &response.Data.DocumentTree
+		return nil
+	})
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// the load may have been invalidated while in flight, its tree is still returned to its callers
+	if t.loads[key] == load {
+		delete(t.loads, key)
+		if load.err == nil {
+			if t.entries == nil {
+				t.entries = map[treeCacheKey]treeCacheEntry{}
+			}
+			t.entries[key] = treeCacheEntry{tree: load.tree, expires: time.Now().Add(t.ttl())}
+		}
+	}
+}
+
+func (t *TreeCache) ttl() time.Duration {
+	if t.TTL > 0 {
+		return t.TTL
+	}
+	return defaultTreeCacheTTL
+}
+
+// Invalidate drops the tree's latest version so the next Get fetches it again, cached versions are kept
+func (t *TreeCache) Invalidate(docTreeID string) {
+	t.mu.Lock()
+	key := treeCacheKey{id: docTreeID}
+	delete(t.entries, key)
+	delete(t.loads, key)
+	t.mu.Unlock()
+	if t.OnInvalidate != nil {
+		t.OnInvalidate(docTreeID)
+	}
+}
+
+// Clear drops every cached tree
+func (t *TreeCache) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = nil
+	t.loads = nil
+}
+
+// HandleEvent invalidates the tree of an EventTreeUpdated event, other events are ignored.
+// Pass it every webhook event or add it as a handler of an event subscription.
+func (t *TreeCache) HandleEvent(event *WebhookEvent) {
+	if event.Type == EventTreeUpdated && event.Data.Tree != nil {
+		t.Invalidate(event.Data.Tree.ID)
+	}
+}
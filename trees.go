@@ -0,0 +1,224 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TreeRevision is a data model for a past version of a DocumentTree
+type TreeRevision struct {
+	Version int `json:"version"`
+	// Author is the user or api key that made the change
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RevisionSelector picks a revision of a DocumentTree by version or by time
+type RevisionSelector struct {
+	// Version selects the revision with this version
+	Version int
+	// At selects the revision that was current at this time
+	At time.Time
+}
+
+// AtVersion selects the revision with the version
+func AtVersion(version int) RevisionSelector {
+	return RevisionSelector{Version: version}
+}
+
+// AtTime selects the revision that was current at t
+func AtTime(t time.Time) RevisionSelector {
+	return RevisionSelector{At: t}
+}
+
+func (s RevisionSelector) body() (map[string]interface{}, error) {
+	switch {
+	case s.Version > 0 && s.At.IsZero():
+		return map[string]interface{}{"version": s.Version}, nil
+	case s.Version == 0 && !s.At.IsZero():
+		return map[string]interface{}{"at": s.At.UTC().Format(time.RFC3339)}, nil
+	}
+	return nil, errors.New("docubot: revision selector needs exactly one of a version or a time")
+}
+
+// DocumentTreeResponse is the response received from getting or changing a DocumentTree
+type DocumentTreeResponse struct {
+	Data DocumentTreeData       `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// DocumentTreeData is the response data received from getting or changing a DocumentTree
+type DocumentTreeData struct {
+	DocumentTree DocumentTree `json:"documentTree"`
+}
+
+// TreeRevisionListResponse is the response received from listing a tree's revisions
+type TreeRevisionListResponse struct {
+	Data TreeRevisionListData   `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// TreeRevisionListData is the response data received from listing a tree's revisions
+type TreeRevisionListData struct {
+	Revisions []TreeRevision `json:"revisions"`
+}
+
+// ListTreeRevisions lists the past versions of a tree, newest first
+func (c *Client) ListTreeRevisions(ctx context.Context, docTreeID string, callOpts ...CallOption) (*TreeRevisionListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees/%v/revisions", c.DocubotAPIURLBase, docTreeID)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response TreeRevisionListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// RollbackDocumentTree restores a previous revision of a tree.
+// The restored tree is saved as a new version, so the rollback can itself be rolled back.
+func (c *Client) RollbackDocumentTree(ctx context.Context, docTreeID string, to RevisionSelector, callOpts ...CallOption) (*DocumentTreeResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	body, err := to.body()
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%v/api/v1/trees/%v/rollback", c.DocubotAPIURLBase, docTreeID)
+	req, err := c.newRequest(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentTreeResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ListOptions pages through list endpoints without other filters
+type ListOptions struct {
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	// Limit is the maximum number of items in the page, the server default is used when zero
+	Limit int
+}
+
+func (o ListOptions) params() url.Values {
+	params := url.Values{}
+	if o.Cursor != "" {
+		params.Set("cursor", o.Cursor)
+	}
+	if o.Limit > 0 {
+		params.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return params
+}
+
+// DocumentTreeListResponse is the response received from listing DocumentTrees
+type DocumentTreeListResponse struct {
+	Data DocumentTreeListData `json:"data"`
+	Meta Page                 `json:"meta"`
+}
+
+// DocumentTreeListData is the response data received from listing DocumentTrees
+type DocumentTreeListData struct {
+	DocumentTrees []DocumentTree `json:"documentTrees"`
+}
+
+// ListDocumentTrees lists a page of the account's DocumentTrees
+func (c *Client) ListDocumentTrees(ctx context.Context, opts ListOptions, callOpts ...CallOption) (*DocumentTreeListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees?%v", c.DocubotAPIURLBase, opts.params().Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentTreeListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetDocumentTree gets a DocumentTree
+func (c *Client) GetDocumentTree(ctx context.Context, docTreeID string, callOpts ...CallOption) (*DocumentTreeResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees/%v", c.DocubotAPIURLBase, docTreeID)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentTreeResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetDocumentTreeVersion gets the DocumentTree as it was at one of its versions, such as the version a thread is pinned to
+func (c *Client) GetDocumentTreeVersion(ctx context.Context, docTreeID string, version int, callOpts ...CallOption) (*DocumentTreeResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	params.Set("version", strconv.Itoa(version))
+	url := fmt.Sprintf("%v/api/v1/trees/%v?%v", c.DocubotAPIURLBase, docTreeID, params.Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentTreeResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetThreadTree gets the DocumentTree the thread interviews with, at the version it is pinned to when it is pinned
+func (c *Client) GetThreadTree(ctx context.Context, thread *Thread, callOpts ...CallOption) (*DocumentTreeResponse, error) {
+	if thread.TreeVersion > 0 {
+		return c.GetDocumentTreeVersion(ctx, thread.DocumentTreeID, thread.TreeVersion, callOpts...)
+	}
+	return c.GetDocumentTree(ctx, thread.DocumentTreeID, callOpts...)
+}
+
+// CreateDocumentTree creates a DocumentTree, docubot assigns its ID
+func (c *Client) CreateDocumentTree(ctx context.Context, docTree *DocumentTree, callOpts ...CallOption) (*DocumentTreeResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees", c.DocubotAPIURLBase)
+	return c.saveDocumentTree(ctx, "POST", url, docTree)
+}
+
+// UpdateDocumentTree replaces the DocumentTree with docTree.ID
+func (c *Client) UpdateDocumentTree(ctx context.Context, docTree *DocumentTree, callOpts ...CallOption) (*DocumentTreeResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees/%v", c.DocubotAPIURLBase, docTree.ID)
+	return c.saveDocumentTree(ctx, "PUT", url, docTree)
+}
+
+func (c *Client) saveDocumentTree(ctx context.Context, method string, url string, docTree *DocumentTree) (*DocumentTreeResponse, error) {
+	req, err := c.newRequest(
+		ctx,
+		method,
+		url,
+		map[string]interface{}{
+			"documentTree": docTree,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response DocumentTreeResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteDocumentTree deletes a DocumentTree, or moves it to the trash when SoftDelete is set
+func (c *Client) DeleteDocumentTree(ctx context.Context, docTreeID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	if c.SoftDelete {
+		_, err := c.TrashDocumentTree(ctx, docTreeID)
+		return err
+	}
+	url := fmt.Sprintf("%v/api/v1/trees/%v", c.DocubotAPIURLBase, docTreeID)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
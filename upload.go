@@ -0,0 +1,205 @@
+package docubotlib
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Defaults used by UploadFileResumable
+const (
+	defaultUploadChunkSize   int64 = 8 << 20
+	defaultUploadMaxAttempts int   = 5
+	defaultUploadBackoff           = time.Second
+)
+
+// chunkChecksumHeader carries the hex sha256 of an uploaded chunk so docubot can reject corrupted chunks
+const chunkChecksumHeader string = "X-Docubot-Chunk-Sha256"
+
+// UploadSession is a data model for a file being uploaded to a variable in chunks
+type UploadSession struct {
+	ID        string `json:"id"`
+	Filename  string `json:"filename"`
+	Size      int64  `json:"size"`
+	ChunkSize int64  `json:"chunkSize"`
+	// ReceivedChunks are the indexes of the chunks docubot has stored and verified
+	ReceivedChunks []int     `json:"receivedChunks"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+// UploadSessionResponse is the response received from creating or getting an upload session
+type UploadSessionResponse struct {
+	Data UploadSessionData      `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// UploadSessionData is the response data received from creating or getting an upload session
+type UploadSessionData struct {
+	Session UploadSession `json:"session"`
+}
+
+// ResumableUploadOptions configures UploadFileResumable
+type ResumableUploadOptions struct {
+	// SessionID resumes an earlier upload of the same file, only the chunks docubot is missing are sent
+	SessionID string
+	// OnSession is called with the session once it is known, store its ID to resume after a failure
+	OnSession func(UploadSession)
+	// ChunkSize is the size of each chunk, 8MiB when zero
+	ChunkSize int64
+	// MaxAttempts is the number of times each chunk is sent before giving up, 5 when zero
+	MaxAttempts int
+}
+
+// UploadFileResumable uploads a file to the variable in chunks, retrying failed chunks and
+// checking each one's sha256 so that large files survive flaky connections
+func (c *Client) UploadFileResumable(ctx context.Context, thread string, user string, variable string, filename string, contentType string, file io.ReaderAt, size int64, opts ResumableUploadOptions, callOpts ...CallOption) (*FileUploadResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	var session *UploadSessionResponse
+	var err error
+	if opts.SessionID != "" {
+		session, err = c.GetUploadSession(ctx, opts.SessionID)
+	} else {
+		session, err = c.createUploadSession(ctx, thread, user, variable, filename, contentType, size, opts.ChunkSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.Data.Session.Size != size {
+		return nil, fmt.Errorf("docubot: upload session is for %v bytes, file has %v", session.Data.Session.Size, size)
+	}
+	if opts.OnSession != nil {
+		opts.OnSession(session.Data.Session)
+	}
+	chunkSize := session.Data.Session.ChunkSize
+	if chunkSize <= 0 {
+		return nil, errors.New("docubot: upload session has no chunk size")
+	}
+	received := map[int]bool{}
+	for _, index := range session.Data.Session.ReceivedChunks {
+		received[index] = true
+	}
+	whole := sha256.New()
+	chunk := make([]byte, chunkSize)
+	for index, offset := 0, int64(0); offset < size; index, offset = index+1, offset+chunkSize {
+		n := chunkSize
+		if size-offset < n {
+			n = size - offset
+		}
+		if _, err := file.ReadAt(chunk[:n], offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		whole.Write(chunk[:n])
+		if received[index] {
+			continue
+		}
+		if err := c.uploadChunk(ctx, session.Data.Session.ID, index, chunk[:n], opts.MaxAttempts); err != nil {
+			return nil, err
+		}
+	}
+	return c.completeUpload(ctx, session.Data.Session.ID, hex.EncodeToString(whole.Sum(nil)))
+}
+
+// GetUploadSession gets an upload session, its ReceivedChunks show what is left to upload
+func (c *Client) GetUploadSession(ctx context.Context, id string, callOpts ...CallOption) (*UploadSessionResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/uploads/%v", c.DocubotAPIURLBase, id)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response UploadSessionResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+func (c *Client) createUploadSession(ctx context.Context, thread string, user string, variable string, filename string, contentType string, size int64, chunkSize int64) (*UploadSessionResponse, error) {
+	if chunkSize <= 0 {
+		chunkSize = defaultUploadChunkSize
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	params := url.Values{}
+	params.Set("user", user)
+	url := fmt.Sprintf(
+		"%v/api/v1/docubot/%v/variables/%v/uploads?%v",
+		c.DocubotAPIURLBase,
+		thread,
+		variable,
+		params.Encode(),
+	)
+	req, err := c.newRequest(
+		ctx,
+		"POST",
+		url,
+		map[string]interface{}{
+			"filename":    filename,
+			"contentType": contentType,
+			"size":        size,
+			"chunkSize":   chunkSize,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response UploadSessionResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// uploadChunk sends one chunk, retrying retryable failures with a doubling backoff
+func (c *Client) uploadChunk(ctx context.Context, session string, index int, chunk []byte, maxAttempts int) error {
+	if maxAttempts < 1 {
+		maxAttempts = defaultUploadMaxAttempts
+	}
+	sum := sha256.Sum256(chunk)
+	url := fmt.Sprintf("%v/api/v1/uploads/%v/chunks/%v", c.DocubotAPIURLBase, session, index)
+	backoff := defaultUploadBackoff
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+		var req *http.Request
+		req, err = c.newRequest(ctx, "PUT", url, nil)
+		if err != nil {
+			return err
+		}
+		setReplayableBody(req, chunk)
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set(chunkChecksumHeader, hex.EncodeToString(sum[:]))
+		if err = c.doJSON(req, nil, nil); err == nil || !IsRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *Client) completeUpload(ctx context.Context, session string, checksum string) (*FileUploadResponse, error) {
+	url := fmt.Sprintf("%v/api/v1/uploads/%v/complete", c.DocubotAPIURLBase, session)
+	req, err := c.newRequest(
+		ctx,
+		"POST",
+		url,
+		map[string]interface{}{
+			"sha256": checksum,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response FileUploadResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
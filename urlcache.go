@@ -0,0 +1,104 @@
+package docubotlib
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// documentURLCacheKey identifies a cached url, urls requested for different durations are cached apart
+type documentURLCacheKey struct {
+	thread string
+	user   string
+	exp    time.Duration
+}
+
+// DocumentURLCache keeps the signed urls of docubot documents, so a page showing a download button doesn't
+// request a url every time it renders. A url is returned until Margin before it expires, then a new one is
+// requested. Urls are invalidated with Invalidate, or by passing EventDocumentGenerated webhook events to
+// HandleEvent. It is safe for concurrent use.
+type DocumentURLCache struct {
+	Client *Client
+	// Margin is how long before expiry a url is replaced, a minute when zero
+	Margin time.Duration
+
+	mu      sync.Mutex
+	entries map[documentURLCacheKey]*documentURLCacheEntry
+}
+
+type documentURLCacheEntry struct {
+	provider *DocumentURLProvider
+	// expires is when the provider's url expires, zero until it has one
+	expires time.Time
+}
+
+// NewDocumentURLCache initializes a cache requesting urls with client and replacing them margin before they expire
+func NewDocumentURLCache(client *Client, margin time.Duration) *DocumentURLCache {
+	return &DocumentURLCache{Client: client, Margin: margin}
+}
+
+// Get returns a url for the provided user's docubot document requested for exp, from the cache while it is valid
+// for at least Margin
+func (c *DocumentURLCache) Get(ctx context.Context, thread string, user string, exp time.Duration, callOpts ...CallOption) (*DocumentURLResponse, error) {
+	entry := c.entry(thread, user, exp)
+	response, err := entry.provider.Response(withCallOptions(ctx, callOpts))
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	entry.expires = response.ExpiresAt
+	c.mu.Unlock()
+	return response, nil
+}
+
+// URL returns the url of Get
+func (c *DocumentURLCache) URL(ctx context.Context, thread string, user string, exp time.Duration, callOpts ...CallOption) (string, error) {
+	response, err := c.Get(ctx, thread, user, exp, callOpts...)
+	if err != nil {
+		return "", err
+	}
+	return response.Data.URL, nil
+}
+
+// entry returns the cache entry of the key, dropping the entries whose url has expired
+func (c *DocumentURLCache) entry(thread string, user string, exp time.Duration) *documentURLCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[documentURLCacheKey]*documentURLCacheEntry{}
+	}
+	now := time.Now()
+	for key, entry := range c.entries {
+		if !entry.expires.IsZero() && !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	key := documentURLCacheKey{thread: thread, user: user, exp: exp}
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &documentURLCacheEntry{provider: c.Client.NewDocumentURLProvider(thread, user, exp)}
+		if c.Margin > 0 {
+			entry.provider.Margin = c.Margin
+		}
+		c.entries[key] = entry
+	}
+	return entry
+}
+
+// Invalidate discards the cached urls of the thread's document, whatever user and duration they were requested for
+func (c *DocumentURLCache) Invalidate(thread string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.thread == thread {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// HandleEvent invalidates the urls of the thread of an EventDocumentGenerated event, other events are ignored
+func (c *DocumentURLCache) HandleEvent(event *WebhookEvent) {
+	if event.Type == EventDocumentGenerated && event.Data.Thread != nil {
+		c.Invalidate(event.Data.Thread.ID)
+	}
+}
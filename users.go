@@ -0,0 +1,129 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// User is a data model
+type User struct {
+	// ID is the value passed as the user or sender of threads
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// ExternalID maps the user to an identity in another system
+	ExternalID string    `json:"externalId,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ListUsersOptions filters the users returned by ListUsers
+type ListUsersOptions struct {
+	// ExternalID only returns the user mapped to this external id when set
+	ExternalID string
+	// Email only returns users with this email when set
+	Email string
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	// Limit is the maximum number of users in the page, the server default is used when zero
+	Limit int
+}
+
+// UserResponse is the response received from creating or getting a user
+type UserResponse struct {
+	Data UserData               `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// UserData is the response data received from creating or getting a user
+type UserData struct {
+	User User `json:"user"`
+}
+
+// UserListResponse is the response received from listing users
+type UserListResponse struct {
+	Data UserListData `json:"data"`
+	Meta Page         `json:"meta"`
+}
+
+// UserListData is the response data received from listing users
+type UserListData struct {
+	Users []User `json:"users"`
+}
+
+// CreateUser creates a managed end user, docubot assigns the ID when it is empty
+func (c *Client) CreateUser(ctx context.Context, user *User, callOpts ...CallOption) (*UserResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/users", c.DocubotAPIURLBase)
+	req, err := c.newRequest(
+		ctx,
+		"POST",
+		url,
+		map[string]interface{}{
+			"user": user,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response UserResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetUser gets a managed end user
+func (c *Client) GetUser(ctx context.Context, id string, callOpts ...CallOption) (*UserResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/users/%v", c.DocubotAPIURLBase, id)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response UserResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ListUsers lists a page of the account's managed end users
+func (c *Client) ListUsers(ctx context.Context, opts ListUsersOptions, callOpts ...CallOption) (*UserListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	params := url.Values{}
+	if opts.ExternalID != "" {
+		params.Set("externalId", opts.ExternalID)
+	}
+	if opts.Email != "" {
+		params.Set("email", opts.Email)
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	url := fmt.Sprintf(
+		"%v/api/v1/users?%v",
+		c.DocubotAPIURLBase,
+		params.Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response UserListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteUser deletes a managed end user, use DeleteUserData to also remove their threads
+func (c *Client) DeleteUser(ctx context.Context, id string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/users/%v", c.DocubotAPIURLBase, id)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
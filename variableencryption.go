@@ -0,0 +1,173 @@
+package docubotlib
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedVariablePrefix starts every answer encrypted by VariableEncryption
+const encryptedVariablePrefix string = "docubot-enc:v1:"
+
+// ErrEncryptedForOtherThread is returned when an encrypted answer is decrypted for a thread it wasn't encrypted for
+var ErrEncryptedForOtherThread = errors.New("docubot: encrypted answer belongs to another thread")
+
+// VariableEncryption encrypts the answers to designated variables on the client, so docubot only ever stores
+// ciphertext for them. Text values written with SetDocubotVariables, StartThread, and GenerateDocument are
+// encrypted too. Answers are encrypted with keys from Keys, typically backed by a KMS, and bound to their thread
+// so they can't be copied into another one. Docubot can't validate, branch on, or render an encrypted answer,
+// designate free text variables that only your own systems read.
+type VariableEncryption struct {
+	Keys KeyProvider
+	// Variables are the names of the variables whose answers are encrypted
+	Variables []string
+}
+
+// Encrypts reports whether answers to the variable are encrypted
+func (e *VariableEncryption) Encrypts(name string) bool {
+	return e != nil && containsString(e.Variables, name)
+}
+
+// Encrypt encrypts an answer to the thread
+func (e *VariableEncryption) Encrypt(ctx context.Context, thread string, answer string) (string, error) {
+	ciphertext, err := EncryptDocument(ctx, e.Keys, []byte(thread+"\x00"+answer))
+	if err != nil {
+		return "", err
+	}
+	return encryptedVariablePrefix + base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts an answer encrypted for the thread, values that aren't encrypted are returned unchanged
+func (e *VariableEncryption) Decrypt(ctx context.Context, thread string, value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedVariablePrefix) {
+		return value, nil
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, encryptedVariablePrefix))
+	if err != nil {
+		return "", ErrNotEncrypted
+	}
+	plaintext, err := DecryptDocument(ctx, e.Keys, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	prefix := []byte(thread + "\x00")
+	if !bytes.HasPrefix(plaintext, prefix) {
+		return "", ErrEncryptedForOtherThread
+	}
+	return string(plaintext[len(prefix):]), nil
+}
+
+// decryptVariables replaces the encrypted values among the thread's variables with their answers
+func (e *VariableEncryption) decryptVariables(ctx context.Context, thread string, variables map[string]interface{}) error {
+	for name, v := range variables {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, encryptedVariablePrefix) {
+			continue
+		}
+		answer, err := e.Decrypt(ctx, thread, s)
+		if err != nil {
+			return fmt.Errorf("docubot: decrypting %v: %w", name, err)
+		}
+		variables[name] = answer
+	}
+	return nil
+}
+
+// encryptVariables returns a copy of the variables with the designated text values encrypted for the thread
+func (e *VariableEncryption) encryptVariables(ctx context.Context, thread string, variables map[string]interface{}) (map[string]interface{}, error) {
+	if e == nil {
+		return variables, nil
+	}
+	encrypted := copyVariables(variables)
+	for name, v := range variables {
+		s, ok := v.(string)
+		if !ok || !e.Encrypts(name) || strings.HasPrefix(s, encryptedVariablePrefix) {
+			continue
+		}
+		var err error
+		if encrypted[name], err = e.Encrypt(ctx, thread, s); err != nil {
+			return nil, err
+		}
+	}
+	return encrypted, nil
+}
+
+// rebindVariables returns a copy of the variables with the designated text values encrypted for the thread,
+// values encrypted for one of the other threads are decrypted with it and encrypted again for the thread
+func (e *VariableEncryption) rebindVariables(ctx context.Context, thread string, others []string, variables map[string]interface{}) (map[string]interface{}, error) {
+	if e == nil {
+		return variables, nil
+	}
+	rebound := copyVariables(variables)
+	for name, v := range variables {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, encryptedVariablePrefix) {
+			continue
+		}
+		_, err := e.Decrypt(ctx, thread, s)
+		if err == nil {
+			continue
+		}
+		for _, other := range others {
+			if err != ErrEncryptedForOtherThread {
+				break
+			}
+			rebound[name], err = e.Decrypt(ctx, other, s)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("docubot: decrypting %v: %w", name, err)
+		}
+	}
+	return e.encryptVariables(ctx, thread, rebound)
+}
+
+// splitVariables separates the designated text values that aren't encrypted yet from the other variables
+func (e *VariableEncryption) splitVariables(variables map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	if e == nil {
+		return variables, nil
+	}
+	var plain, designated map[string]interface{}
+	for name, v := range variables {
+		if s, ok := v.(string); ok && e.Encrypts(name) && !strings.HasPrefix(s, encryptedVariablePrefix) {
+			if designated == nil {
+				plain, designated = copyVariables(variables), map[string]interface{}{}
+			}
+			designated[name] = s
+			delete(plain, name)
+		}
+	}
+	if designated == nil {
+		return variables, nil
+	}
+	return plain, designated
+}
+
+// EncryptAnswer returns the answer to send to the thread for the variable, encrypted when the client's
+// VariableEncryption designates the variable, for callers sending answers with SendMessage
+func (c *Client) EncryptAnswer(ctx context.Context, thread string, variableName string, answer string) (string, error) {
+	if !c.VariableEncryption.Encrypts(variableName) {
+		return answer, nil
+	}
+	return c.VariableEncryption.Encrypt(ctx, thread, answer)
+}
+
+// encrypt encrypts the message when it answers a variable the client's VariableEncryption designates,
+// the pending question is found with the conversation's tree. Without a tree no answer is sent, since whether
+// it needs encrypting can't be told.
+func (conv *Conversation) encrypt(ctx context.Context, message string) (string, error) {
+	if conv.Client.VariableEncryption == nil || len(conv.Client.VariableEncryption.Variables) == 0 {
+		return message, nil
+	}
+	if conv.Tree == nil && conv.Trees == nil {
+		return "", errors.New("docubot: variable encryption needs the conversation's Tree or Trees to tell which answers to encrypt")
+	}
+	question, _, err := conv.pendingQuestion(ctx)
+	if err != nil || question == nil {
+		return message, err
+	}
+	// the answer is normalized before it is encrypted, docubot can't normalize the ciphertext
+	return conv.Client.EncryptAnswer(ctx, conv.Thread, question.VariableName, conv.Client.normalizeAnswer(message))
+}
@@ -0,0 +1,81 @@
+package docubotlib
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testSSN = "123-45-6789"
+
+func testEncryption() *VariableEncryption {
+	return &VariableEncryption{Keys: StaticKey([]byte("0123456789abcdef0123456789abcdef")), Variables: []string{"ssn"}}
+}
+
+func TestVariableEncryptionBindsToThread(t *testing.T) {
+	ctx := context.Background()
+	e := testEncryption()
+	ciphertext, err := e.Encrypt(ctx, "thread_1", testSSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(ciphertext, testSSN) || !strings.HasPrefix(ciphertext, encryptedVariablePrefix) {
+		t.Fatalf("got ciphertext %q", ciphertext)
+	}
+	if answer, err := e.Decrypt(ctx, "thread_1", ciphertext); err != nil || answer != testSSN {
+		t.Errorf("got %q, %v, want %q", answer, err, testSSN)
+	}
+	if _, err := e.Decrypt(ctx, "thread_2", ciphertext); err != ErrEncryptedForOtherThread {
+		t.Errorf("got %v decrypting for another thread, want ErrEncryptedForOtherThread", err)
+	}
+	if answer, err := e.Decrypt(ctx, "thread_1", "plain"); err != nil || answer != "plain" {
+		t.Errorf("got %q, %v for a value that isn't encrypted", answer, err)
+	}
+}
+
+func TestVariableEncryptionWrites(t *testing.T) {
+	ctx := context.Background()
+	f, c := newFakeDocubot(t)
+	c.VariableEncryption = testEncryption()
+	variables := map[string]interface{}{"ssn": testSSN, "name": "Jane Roe"}
+
+	if _, err := c.SetDocubotVariables(ctx, "thread_1", "user", variables); err != nil {
+		t.Fatal(err)
+	}
+	started, err := c.StartThread(ctx, "tree", "user", StartThreadOptions{Variables: variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GenerateDocument(ctx, variables, GenerateDocumentOptions{DocumentTreeID: "tree"}); err != nil {
+		t.Fatal(err)
+	}
+	if f.sent(testSSN) {
+		t.Error("an encrypted variable was sent in plaintext")
+	}
+	if !f.sent("Jane Roe") {
+		t.Error("a variable that isn't designated wasn't sent")
+	}
+	thread := started.Data.Thread.ID
+	got, err := c.GetDocubotVariables(thread, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Data.Variables["ssn"] != testSSN {
+		t.Errorf("read back %v for the started thread, want %v", got.Data.Variables["ssn"], testSSN)
+	}
+	if stored, _ := f.stored(thread)["ssn"].(string); !strings.HasPrefix(stored, encryptedVariablePrefix) {
+		t.Errorf("docubot stored %q for the started thread", stored)
+	}
+}
+
+func TestConversationWithoutTreeRefusesToSend(t *testing.T) {
+	f, c := newFakeDocubot(t)
+	c.VariableEncryption = testEncryption()
+	conv := c.NewConversation("thread_1", "user", "tree")
+	if _, err := conv.Send(context.Background(), testSSN, func(string) error { return nil }); err == nil {
+		t.Fatal("got no error sending without a tree")
+	}
+	if f.sent(testSSN) {
+		t.Error("the answer was sent in plaintext")
+	}
+}
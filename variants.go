@@ -0,0 +1,179 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// controlVariant is the name of the variant served by the tree itself
+const controlVariant string = "control"
+
+// TreeVariant is a data model for an alternative version of a DocumentTree used in experiments
+type TreeVariant struct {
+	ID string `json:"id,omitempty"`
+	// Name identifies the variant in message responses and analytics
+	Name           string        `json:"name"`
+	DocumentTreeID string        `json:"documentTreeId"`
+	EntryQuestion  *QuestionNode `json:"entryQuestion,omitempty"`
+	UpdatedAt      time.Time     `json:"updatedAt"`
+	CreatedAt      time.Time     `json:"createdAt"`
+}
+
+// TrafficSplit maps variant names to their relative weight of new threads,
+// the tree itself is weighted under the name "control"
+type TrafficSplit map[string]int
+
+// Validate checks the split has at least one positive weight and no negative weights
+func (s TrafficSplit) Validate() error {
+	total := 0
+	for name, weight := range s {
+		if weight < 0 {
+			return fmt.Errorf("docubot: variant %q has a negative weight", name)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return errors.New("docubot: traffic split has no weight")
+	}
+	return nil
+}
+
+// TreeVariantResponse is the response received from creating a tree variant
+type TreeVariantResponse struct {
+	Data TreeVariantData        `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// TreeVariantData is the response data received from creating a tree variant
+type TreeVariantData struct {
+	Variant TreeVariant `json:"variant"`
+}
+
+// TreeVariantListResponse is the response received from listing a tree's variants
+type TreeVariantListResponse struct {
+	Data TreeVariantListData    `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// TreeVariantListData is the response data received from listing a tree's variants
+type TreeVariantListData struct {
+	Variants []TreeVariant `json:"variants"`
+	Split    TrafficSplit  `json:"split"`
+}
+
+// VariantMetrics is a data model comparing how threads served by a variant performed
+type VariantMetrics struct {
+	Variant   string `json:"variant"`
+	Threads   int    `json:"threads"`
+	Completed int    `json:"completed"`
+}
+
+// CompletionRate is the fraction of the variant's threads that completed the interview
+func (m VariantMetrics) CompletionRate() float64 {
+	if m.Threads == 0 {
+		return 0
+	}
+	return float64(m.Completed) / float64(m.Threads)
+}
+
+// VariantAnalyticsResponse is the response received from getting a tree's variant analytics
+type VariantAnalyticsResponse struct {
+	Data VariantAnalyticsData   `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// VariantAnalyticsData is the response data received from getting a tree's variant analytics
+type VariantAnalyticsData struct {
+	Variants []VariantMetrics `json:"variants"`
+}
+
+// CreateTreeVariant creates a variant of a tree, it serves no threads until it is given weight with SetTrafficSplit
+func (c *Client) CreateTreeVariant(ctx context.Context, docTreeID string, variant *TreeVariant, callOpts ...CallOption) (*TreeVariantResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	if variant == nil {
+		return nil, errors.New("docubot: no variant to create")
+	}
+	if variant.Name == "" || variant.Name == controlVariant {
+		return nil, fmt.Errorf("docubot: variant name must not be empty or %q", controlVariant)
+	}
+	url := fmt.Sprintf("%v/api/v1/trees/%v/variants", c.DocubotAPIURLBase, docTreeID)
+	req, err := c.newRequest(
+		ctx,
+		"POST",
+		url,
+		map[string]interface{}{
+			"variant": variant,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response TreeVariantResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ListTreeVariants lists a tree's variants and the current traffic split
+func (c *Client) ListTreeVariants(ctx context.Context, docTreeID string, callOpts ...CallOption) (*TreeVariantListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees/%v/variants", c.DocubotAPIURLBase, docTreeID)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response TreeVariantListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteTreeVariant deletes a variant, threads already using it keep it until they complete
+func (c *Client) DeleteTreeVariant(ctx context.Context, docTreeID string, variantID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/trees/%v/variants/%v", c.DocubotAPIURLBase, docTreeID, variantID)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// SetTrafficSplit sets how new threads for a tree are divided between its variants
+func (c *Client) SetTrafficSplit(ctx context.Context, docTreeID string, split TrafficSplit, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	if err := split.Validate(); err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%v/api/v1/trees/%v/variants/split", c.DocubotAPIURLBase, docTreeID)
+	req, err := c.newRequest(
+		ctx,
+		"PUT",
+		url,
+		map[string]interface{}{
+			"split": split,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// GetVariantAnalytics gets completion metrics for each of a tree's variants
+func (c *Client) GetVariantAnalytics(ctx context.Context, docTreeID string, opts AnalyticsOptions, callOpts ...CallOption) (*VariantAnalyticsResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf(
+		"%v/api/v1/analytics/trees/%v/variants?%v",
+		c.DocubotAPIURLBase,
+		docTreeID,
+		opts.params().Encode(),
+	)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response VariantAnalyticsResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
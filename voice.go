@@ -0,0 +1,307 @@
+package docubotlib
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxReprompts is how many times a VoiceAdapter asks a question again before giving up on the call
+const defaultMaxReprompts int = 2
+
+// VoiceAdapter runs docubot interviews over the phone as the voice webhook of a Twilio number. Docubot's
+// messages are read to the caller with text to speech and the caller answers with speech or the keypad.
+// Each call gets its own thread, the caller's number is the thread's user.
+type VoiceAdapter struct {
+	Client         *Client
+	DocumentTreeID string
+	// Tree, when set, lets callers pick choices with the keypad, choices are read out with their number,
+	// and lets spoken answers be normalized with Locale
+	Tree   *DocumentTree
+	Locale Locale
+	// Voice and Language are passed to Twilio's text to speech and speech recognition, such as "Polly.Joanna" and "en-US"
+	Voice    string
+	Language string
+	// MaxReprompts is how many times a rejected or missing answer is asked for again before the call ends, 2 when zero
+	MaxReprompts int
+	// Goodbye is read before hanging up once the interview is complete
+	Goodbye string
+	// GiveUp is read before hanging up when the caller ran out of reprompts
+	GiveUp string
+	// Fallback, when set, is delivered the question a caller who ran out of reprompts couldn't answer, such as
+	// by SMS to the caller's number, so the interview carries on there. FallbackNotice is read instead of GiveUp
+	// once it is delivered.
+	Fallback       MessageChannel
+	FallbackNotice string
+	// AuthToken verifies requests are signed by Twilio, URL is the webhook's public url they are signed for.
+	// Both are required in production, requests are refused while AuthToken is empty unless Insecure is set.
+	AuthToken string
+	URL       string
+	// Insecure accepts unsigned requests when AuthToken is empty, such as to try the adapter locally. Anyone who
+	// can reach the webhook can then answer for any caller.
+	Insecure bool
+}
+
+// NewVoiceAdapter initializes an adapter interviewing callers with the tree
+func NewVoiceAdapter(client *Client, docTreeID string) *VoiceAdapter {
+	return &VoiceAdapter{
+		Client:         client,
+		DocumentTreeID: docTreeID,
+		Locale:         LocaleEnUS,
+		Goodbye:        "Thank you, we have everything we need. Goodbye.",
+		GiveUp:         "Sorry, we couldn't get your answer. Please call back later. Goodbye.",
+		FallbackNotice: "Sorry, we couldn't get your answer. We've sent you the question to answer in writing. Goodbye.",
+	}
+}
+
+// twiml is a TwiML response
+type twiml struct {
+	XMLName xml.Name `xml:"Response"`
+	Verbs   []interface{}
+}
+
+type twimlSay struct {
+	XMLName  xml.Name `xml:"Say"`
+	Voice    string   `xml:"voice,attr,omitempty"`
+	Language string   `xml:"language,attr,omitempty"`
+	Text     string   `xml:",chardata"`
+}
+
+type twimlGather struct {
+	XMLName  xml.Name `xml:"Gather"`
+	Input    string   `xml:"input,attr"`
+	Action   string   `xml:"action,attr"`
+	Method   string   `xml:"method,attr"`
+	Language string   `xml:"language,attr,omitempty"`
+	Says     []twimlSay
+}
+
+type twimlRedirect struct {
+	XMLName xml.Name `xml:"Redirect"`
+	Method  string   `xml:"method,attr"`
+	URL     string   `xml:",chardata"`
+}
+
+type twimlHangup struct {
+	XMLName xml.Name `xml:"Hangup"`
+}
+
+// ServeHTTP handles a Twilio voice webhook, the first request of a call starts its thread and every
+// later one carries the caller's answer
+func (a *VoiceAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	if a.AuthToken == "" && !a.Insecure {
+		a.Client.reportError(errors.New("docubot: voice adapter has no AuthToken"))
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if a.AuthToken != "" && !a.verify(r) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	response, err := a.respond(r)
+	if err != nil {
+		a.Client.reportError(err)
+		response = &twiml{Verbs: []interface{}{a.say(a.GiveUp), twimlHangup{}}}
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(response)
+}
+
+func (a *VoiceAdapter) respond(r *http.Request) (*twiml, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	thread := query.Get("thread")
+	caller := r.PostForm.Get("From")
+	if thread == "" {
+		started, err := a.Client.StartThread(ctx, a.DocumentTreeID, caller, StartThreadOptions{})
+		if err != nil {
+			return nil, err
+		}
+		conv := a.conversation(started.Data.Thread.ID, caller)
+		return a.ask(r, conv, started.Data.Messages, started.Data.Complete, 0)
+	}
+	conv := a.conversation(thread, caller)
+	attempt, _ := strconv.Atoi(query.Get("attempt"))
+	answer := r.PostForm.Get("Digits")
+	if answer == "" {
+		answer = strings.TrimSpace(r.PostForm.Get("SpeechResult"))
+	}
+	if answer == "" {
+		return a.reprompt(r, conv, attempt, "Sorry, I didn't get an answer.")
+	}
+	answer, err := a.normalize(r, conv, answer)
+	if err != nil {
+		return a.reprompt(r, conv, attempt, "Sorry, I didn't understand that.")
+	}
+	var messages []string
+	response, err := conv.Send(ctx, answer, func(message string) error {
+		messages = append(messages, message)
+		return nil
+	})
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		return a.reprompt(r, conv, attempt, validation.Reprompt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a.ask(r, conv, messages, response.Data.Complete, 0)
+}
+
+func (a *VoiceAdapter) conversation(thread string, caller string) *Conversation {
+	// replies are read out together, so the suggested pauses are left to the text to speech
+	conv := a.Client.NewConversation(thread, caller, a.DocumentTreeID)
+	conv.IgnorePacing = true
+	conv.Tree = a.Tree
+	return conv
+}
+
+// normalize reads keypad and spoken answers the way the question being asked expects them
+func (a *VoiceAdapter) normalize(r *http.Request, conv *Conversation, answer string) (string, error) {
+	if a.Tree == nil {
+		return answer, nil
+	}
+	question, _, err := conv.pendingQuestion(r.Context())
+	if err != nil || question == nil {
+		return answer, err
+	}
+	return NormalizeAnswerFor(a.Locale, a.Tree, question.VariableName, answer)
+}
+
+// ask reads messages to the caller and gathers the answer to the last of them, or hangs up when complete
+func (a *VoiceAdapter) ask(r *http.Request, conv *Conversation, messages []string, complete bool, attempt int) (*twiml, error) {
+	if complete {
+		verbs := []interface{}{}
+		for _, message := range messages {
+			verbs = append(verbs, a.say(message))
+		}
+		return &twiml{Verbs: append(verbs, a.say(a.Goodbye), twimlHangup{})}, nil
+	}
+	if a.Tree != nil {
+		question, _, err := conv.pendingQuestion(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		if question != nil && strings.EqualFold(question.EntityType, EntityTypeMultipleChoice) {
+			messages = append(messages, choicePrompt(question))
+		}
+	}
+	gather := twimlGather{
+		Input:    "speech dtmf",
+		Action:   a.action(conv.Thread, attempt),
+		Method:   "POST",
+		Language: a.Language,
+	}
+	for _, message := range messages {
+		gather.Says = append(gather.Says, a.say(message))
+	}
+	// a gather that times out without input falls through to the redirect, which reprompts
+	redirect := twimlRedirect{Method: "POST", URL: a.action(conv.Thread, attempt)}
+	return &twiml{Verbs: []interface{}{gather, redirect}}, nil
+}
+
+// reprompt asks the question again after a missing or rejected answer, or gives up after MaxReprompts
+func (a *VoiceAdapter) reprompt(r *http.Request, conv *Conversation, attempt int, reason string) (*twiml, error) {
+	max := a.MaxReprompts
+	if max <= 0 {
+		max = defaultMaxReprompts
+	}
+	if attempt >= max {
+		goodbye := a.GiveUp
+		if a.Fallback != nil && a.fallback(r.Context(), conv) {
+			goodbye = a.FallbackNotice
+		}
+		return &twiml{Verbs: []interface{}{a.say(goodbye), twimlHangup{}}}, nil
+	}
+	messages := []string{}
+	if reason != "" {
+		messages = append(messages, reason)
+	}
+	if a.Tree != nil {
+		question, _, err := conv.pendingQuestion(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		if question != nil && !containsString(messages, question.Question) {
+			messages = append(messages, question.Question)
+		}
+	}
+	return a.ask(r, conv, messages, false, attempt+1)
+}
+
+// fallback delivers the pending question through the Fallback, reporting whether it was delivered
+func (a *VoiceAdapter) fallback(ctx context.Context, conv *Conversation) bool {
+	question, _, err := conv.pendingQuestion(ctx)
+	if err == nil && question == nil {
+		return false
+	}
+	if err == nil {
+		err = a.Fallback.Deliver(ctx, conv.Sender, []string{question.Question})
+	}
+	if err != nil {
+		a.Client.reportError(err)
+		return false
+	}
+	return true
+}
+
+// choicePrompt reads out the choices of a multiple choice question with the keys that pick them
+func choicePrompt(question *QuestionNode) string {
+	var prompts []string
+	for i, key := range ChoiceKeys(question) {
+		prompts = append(prompts, fmt.Sprintf("For %v, press %v.", question.MetaData.Choices[key], i+1))
+	}
+	return strings.Join(prompts, " ")
+}
+
+func (a *VoiceAdapter) say(text string) twimlSay {
+	return twimlSay{Voice: a.Voice, Language: a.Language, Text: text}
+}
+
+// action is the url, relative to the webhook, that the caller's answer is posted to
+func (a *VoiceAdapter) action(thread string, attempt int) string {
+	params := url.Values{}
+	params.Set("thread", thread)
+	params.Set("attempt", strconv.Itoa(attempt))
+	return "?" + params.Encode()
+}
+
+// verify checks the X-Twilio-Signature header, the base64 hmac-sha1 of the url followed by
+// the form's parameters sorted by name
+func (a *VoiceAdapter) verify(r *http.Request) bool {
+	u := a.URL
+	if r.URL.RawQuery != "" {
+		u += "?" + r.URL.RawQuery
+	}
+	names := make([]string, 0, len(r.PostForm))
+	for name := range r.PostForm {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(u)
+	for _, name := range names {
+		for _, value := range r.PostForm[name] {
+			b.WriteString(name)
+			b.WriteString(value)
+		}
+	}
+	mac := hmac.New(sha1.New, []byte(a.AuthToken))
+	mac.Write([]byte(b.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Twilio-Signature")))
+}
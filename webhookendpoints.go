@@ -0,0 +1,104 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WebhookEndpoint is a data model for a url docubot delivers webhook events to
+type WebhookEndpoint struct {
+	ID  string `json:"id,omitempty"`
+	URL string `json:"url"`
+	// Events are the event types delivered to the endpoint, every type when empty
+	Events []string `json:"events,omitempty"`
+	// IncludeTranscript and IncludeVariables add the thread's transcript and final variables to
+	// EventThreadCompleted deliveries, so handlers don't need to fetch the thread
+	IncludeTranscript bool `json:"includeTranscript,omitempty"`
+	IncludeVariables  bool `json:"includeVariables,omitempty"`
+	// StuckAfter is how many answers in a row a question rejects before EventThreadStuck is delivered,
+	// docubot's default when zero
+	StuckAfter int `json:"stuckAfter,omitempty"`
+	// Variables limit EventVariableAnswered deliveries to answers of these variables, such as "opposing_party"
+	// for a conflicts check, answers of every variable are delivered when empty
+	Variables []string  `json:"variables,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookEndpointResponse is the response received from getting or saving a WebhookEndpoint
+type WebhookEndpointResponse struct {
+	Data WebhookEndpointData    `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// WebhookEndpointData is the response data received from getting or saving a WebhookEndpoint
+type WebhookEndpointData struct {
+	WebhookEndpoint WebhookEndpoint `json:"webhookEndpoint"`
+}
+
+// WebhookEndpointListResponse is the response received from listing WebhookEndpoints
+type WebhookEndpointListResponse struct {
+	Data WebhookEndpointListData `json:"data"`
+	Meta Page                    `json:"meta"`
+}
+
+// WebhookEndpointListData is the response data received from listing WebhookEndpoints
+type WebhookEndpointListData struct {
+	WebhookEndpoints []WebhookEndpoint `json:"webhookEndpoints"`
+}
+
+// ListWebhookEndpoints lists a page of the account's WebhookEndpoints
+func (c *Client) ListWebhookEndpoints(ctx context.Context, opts ListOptions, callOpts ...CallOption) (*WebhookEndpointListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/webhooks?%v", c.DocubotAPIURLBase, opts.params().Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response WebhookEndpointListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// CreateWebhookEndpoint creates a WebhookEndpoint, docubot assigns its ID
+func (c *Client) CreateWebhookEndpoint(ctx context.Context, endpoint *WebhookEndpoint, callOpts ...CallOption) (*WebhookEndpointResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/webhooks", c.DocubotAPIURLBase)
+	return c.saveWebhookEndpoint(ctx, "POST", url, endpoint)
+}
+
+// UpdateWebhookEndpoint replaces the WebhookEndpoint with endpoint.ID
+func (c *Client) UpdateWebhookEndpoint(ctx context.Context, endpoint *WebhookEndpoint, callOpts ...CallOption) (*WebhookEndpointResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/webhooks/%v", c.DocubotAPIURLBase, endpoint.ID)
+	return c.saveWebhookEndpoint(ctx, "PUT", url, endpoint)
+}
+
+func (c *Client) saveWebhookEndpoint(ctx context.Context, method string, url string, endpoint *WebhookEndpoint) (*WebhookEndpointResponse, error) {
+	req, err := c.newRequest(
+		ctx,
+		method,
+		url,
+		map[string]interface{}{
+			"webhookEndpoint": endpoint,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var response WebhookEndpointResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteWebhookEndpoint deletes a WebhookEndpoint, docubot stops delivering to it
+func (c *Client) DeleteWebhookEndpoint(ctx context.Context, endpointID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/webhooks/%v", c.DocubotAPIURLBase, endpointID)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
@@ -0,0 +1,159 @@
+package docubotlib
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrStaleWebhook is returned for webhook deliveries signed too long ago, they may be replayed
+var ErrStaleWebhook = errors.New("docubot: stale webhook delivery")
+
+// Defaults of a WebhookHandler
+const (
+	defaultWebhookTolerance time.Duration = 5 * time.Minute
+	defaultWebhookSeenTTL   time.Duration = 72 * time.Hour
+	maxWebhookPayload       int64         = 1 << 20
+	// memorySeenPruneInterval is how often a MemorySeenStore forgets the expired event IDs
+	memorySeenPruneInterval time.Duration = time.Minute
+)
+
+// SeenEventStore remembers the IDs of webhook events already handled, implement it with a shared store,
+// such as redis, when several instances receive the same webhooks
+type SeenEventStore interface {
+	// Claim records the event ID for ttl, it returns false when the ID was already claimed.
+	// It must be atomic so only one of concurrent deliveries of an event claims it.
+	Claim(ctx context.Context, id string, ttl time.Duration) (bool, error)
+}
+
+// MemorySeenStore is a SeenEventStore keeping event IDs in memory, it only protects a single instance
+type MemorySeenStore struct {
+	mu     sync.Mutex
+	seen   map[string]time.Time
+	pruned time.Time
+}
+
+// NewMemorySeenStore initializes an empty in-memory store
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: map[string]time.Time{}}
+}
+
+// Claim records the event ID, expired IDs are forgotten about once a minute as new ones are claimed
+func (s *MemorySeenStore) Claim(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen == nil {
+		s.seen = map[string]time.Time{}
+	}
+	now := time.Now()
+	if now.Sub(s.pruned) >= memorySeenPruneInterval {
+		for seenID, expiry := range s.seen {
+			if now.After(expiry) {
+				delete(s.seen, seenID)
+			}
+		}
+		s.pruned = now
+	}
+	if expiry, ok := s.seen[id]; ok && !now.After(expiry) {
+		return false, nil
+	}
+	s.seen[id] = now.Add(ttl)
+	return true, nil
+}
+
+// WebhookHandler is an http.Handler receiving docubot webhooks, it verifies their signature, refuses deliveries
+// signed more than Tolerance ago, and calls Handle at most once per event ID however often docubot redelivers it.
+// An event is claimed before Handle is called, so a Handle that fails isn't called again for the same event.
+// Events without an ID can't be told apart, they are handled on every delivery.
+type WebhookHandler struct {
+	// Client verifies the signatures with its api secret
+	Client *Client
+	// Store remembers the handled events, a MemorySeenStore when nil
+	Store SeenEventStore
+	// Tolerance is how old a delivery's signature can be, 5 minutes when zero
+	Tolerance time.Duration
+	// SeenTTL is how long an event ID is remembered, 72 hours when zero. It should outlast docubot's redeliveries.
+	SeenTTL time.Duration
+	// Handle is called with every new event, its error is reported to the client's OnError
+	Handle func(ctx context.Context, event *WebhookEvent) error
+
+	storeOnce sync.Once
+}
+
+func (h *WebhookHandler) store() SeenEventStore {
+	h.storeOnce.Do(func() {
+		if h.Store == nil {
+			h.Store = NewMemorySeenStore()
+		}
+	})
+	return h.Store
+}
+
+// ServeHTTP handles a webhook delivery, duplicates and EventPing deliveries are acknowledged without calling Handle
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayload))
+	if err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	signature := r.Header.Get(WebhookSignatureHeader)
+	event, err := h.Client.ParseWebhookEvent(payload, signature)
+	if errors.Is(err, ErrInvalidWebhookSignature) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if err := h.checkFreshness(signature); err != nil {
+		http.Error(w, "stale delivery", http.StatusBadRequest)
+		return
+	}
+	if event.Type == EventPing {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	ttl := h.SeenTTL
+	if ttl <= 0 {
+		ttl = defaultWebhookSeenTTL
+	}
+	claimed := true
+	if event.ID != "" {
+		claimed, err = h.store().Claim(r.Context(), event.ID, ttl)
+	}
+	if err != nil {
+		// docubot redelivers on errors, so the event isn't lost when the store is down
+		h.Client.reportError(err)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if claimed && h.Handle != nil {
+		if err := h.Handle(r.Context(), event); err != nil {
+			h.Client.reportError(err)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// checkFreshness refuses signatures whose time is further than Tolerance from now
+func (h *WebhookHandler) checkFreshness(signature string) error {
+	timestamp, _ := parseWebhookSignature(signature)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+	tolerance := h.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultWebhookTolerance
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrStaleWebhook
+	}
+	return nil
+}
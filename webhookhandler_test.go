@@ -0,0 +1,98 @@
+package docubotlib
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemorySeenStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemorySeenStore()
+	if claimed, _ := s.Claim(ctx, "evt_1", time.Hour); !claimed {
+		t.Fatal("didn't claim a new event")
+	}
+	if claimed, _ := s.Claim(ctx, "evt_1", time.Hour); claimed {
+		t.Error("claimed an event twice")
+	}
+	if claimed, _ := s.Claim(ctx, "evt_2", -time.Second); !claimed {
+		t.Fatal("didn't claim a new event")
+	}
+	if claimed, _ := s.Claim(ctx, "evt_2", time.Hour); !claimed {
+		t.Error("an expired event is still claimed")
+	}
+}
+
+// deliver posts a webhook delivery to h and returns the status it answered
+func deliver(h http.Handler, payload string, signature string) int {
+	req := httptest.NewRequest("POST", "/webhooks", strings.NewReader(payload))
+	req.Header.Set(WebhookSignatureHeader, signature)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestWebhookHandler(t *testing.T) {
+	c := NewClient("http://docubot.test", "key", "secret")
+	handled := map[string]int{}
+	h := &WebhookHandler{Client: c, Handle: func(ctx context.Context, event *WebhookEvent) error {
+		handled[event.ID]++
+		return nil
+	}}
+	sign := func(payload string, t time.Time) string { return c.signWebhookPayload([]byte(payload), t) }
+	event := `{"id":"evt_1","type":"thread.completed"}`
+
+	for i := 0; i < 3; i++ {
+		if status := deliver(h, event, sign(event, time.Now())); status != http.StatusOK {
+			t.Fatalf("delivery %v answered %v", i+1, status)
+		}
+	}
+	if handled["evt_1"] != 1 {
+		t.Errorf("a redelivered event was handled %v times, want once", handled["evt_1"])
+	}
+
+	stale := `{"id":"evt_2","type":"thread.completed"}`
+	if status := deliver(h, stale, sign(stale, time.Now().Add(-time.Hour))); status != http.StatusBadRequest {
+		t.Errorf("a stale delivery answered %v", status)
+	}
+	if status := deliver(h, stale, sign(stale, time.Now().Add(time.Hour))); status != http.StatusBadRequest {
+		t.Errorf("a delivery signed in the future answered %v", status)
+	}
+	other := NewClient("http://docubot.test", "key", "other secret")
+	if status := deliver(h, stale, other.signWebhookPayload([]byte(stale), time.Now())); status != http.StatusUnauthorized {
+		t.Errorf("a delivery signed with another secret answered %v", status)
+	}
+	if status := deliver(h, stale, ""); status != http.StatusUnauthorized {
+		t.Errorf("an unsigned delivery answered %v", status)
+	}
+	if handled["evt_2"] != 0 {
+		t.Error("a rejected delivery was handled")
+	}
+
+	anonymous := `{"type":"thread.completed"}`
+	for i := 0; i < 2; i++ {
+		deliver(h, anonymous, sign(anonymous, time.Now()))
+	}
+	if handled[""] != 2 {
+		t.Errorf("events without an ID were handled %v times, want every delivery", handled[""])
+	}
+}
+
+func TestWebhookHandlerWithoutSecret(t *testing.T) {
+	c := NewClient("http://docubot.test", "key", "")
+	called := false
+	h := &WebhookHandler{Client: c, Handle: func(context.Context, *WebhookEvent) error {
+		called = true
+		return nil
+	}}
+	event := `{"id":"evt_1","type":"thread.completed"}`
+	if status := deliver(h, event, c.signWebhookPayload([]byte(event), time.Now())); status != http.StatusUnauthorized {
+		t.Errorf("a delivery signed with an empty secret answered %v", status)
+	}
+	if called {
+		t.Error("a delivery signed with an empty secret was handled")
+	}
+}
@@ -0,0 +1,146 @@
+package docubotlib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader is the header docubot signs webhook deliveries with
+const WebhookSignatureHeader string = "X-Docubot-Signature"
+
+// Webhook event types
+const (
+	EventThreadCreated     string = "thread.created"
+	EventThreadUpdated     string = "thread.updated"
+	EventThreadCompleted   string = "thread.completed"
+	EventDocumentGenerated string = "document.generated"
+	EventThreadReminder    string = "thread.reminder"
+	EventThreadExpired     string = "thread.expired"
+	EventThreadPaused      string = "thread.paused"
+	EventThreadResumed     string = "thread.resumed"
+	// EventAnswerRejected is delivered every time an answer fails a question's validation
+	EventAnswerRejected string = "thread.answerRejected"
+	// EventThreadStuck is delivered once a question has rejected several answers in a row, see WebhookEndpoint.StuckAfter
+	EventThreadStuck string = "thread.stuck"
+	// EventTreeUpdated is delivered when a tree is changed, its Data.Tree carries the tree's ID and new version
+	EventTreeUpdated string = "tree.updated"
+	// EventVariableAnswered is delivered the moment a question is answered, its Data.Answer carries the answer.
+	// Webhook endpoints and subscriptions can limit it to some variables, see WebhookEndpoint.Variables.
+	EventVariableAnswered string = "thread.variableAnswered"
+)
+
+// ErrInvalidWebhookSignature is returned for webhook deliveries that weren't signed with the client's api secret,
+// and for every delivery when the client has no api secret
+var ErrInvalidWebhookSignature = errors.New("docubot: invalid webhook signature")
+
+// WebhookEvent is the payload docubot delivers to webhook endpoints
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      string           `json:"type"`
+	Data      WebhookEventData `json:"data"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// WebhookEventData is the data of a webhook event, Thread includes its labels and attributes
+type WebhookEventData struct {
+	Thread   *Thread         `json:"thread,omitempty"`
+	Document *ThreadDocument `json:"document,omitempty"`
+	// Transcript and Variables are included in EventThreadCompleted events for webhook endpoints
+	// and subscriptions that ask for them
+	Transcript []TranscriptMessage    `json:"transcript,omitempty"`
+	Variables  map[string]interface{} `json:"variables,omitempty"`
+	// Validation is the failure of EventAnswerRejected and EventThreadStuck events
+	Validation *ValidationFailure `json:"validation,omitempty"`
+	// Tree is the tree of EventTreeUpdated events
+	Tree *DocumentTree `json:"tree,omitempty"`
+	// Answer is the answer of EventVariableAnswered events, docubot masks the values of sensitive variables
+	Answer *AnswerAuditEntry `json:"answer,omitempty"`
+}
+
+// ValidationFailure describes the answers a question rejected
+type ValidationFailure struct {
+	ValidationErrorDetail
+	// Attempts is how many answers in a row the question has rejected
+	Attempts int `json:"attempts"`
+	// Answer is the last rejected answer, docubot masks the answers of sensitive variables
+	Answer string `json:"answer,omitempty"`
+}
+
+// TranscriptMessage is a data model for one message in a thread's conversation
+type TranscriptMessage struct {
+	// Role is who the message came from, docubot's own messages have SenderBot
+	Role SenderRole `json:"role"`
+	// Sender is the id of the user or agent who sent the message, empty for docubot's messages
+	Sender string `json:"sender,omitempty"`
+	Text   string `json:"text"`
+	// VariableName is the variable the message answered or asked for, if any
+	VariableName string    `json:"variableName,omitempty"`
+	SentAt       time.Time `json:"sentAt"`
+}
+
+// ParseWebhookEvent verifies a webhook delivery's signature header and decodes its payload
+func (c *Client) ParseWebhookEvent(payload []byte, signature string) (*WebhookEvent, error) {
+	if err := c.verifyWebhookSignature(payload, signature); err != nil {
+		return nil, err
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// verifyWebhookSignature checks a signature header of the form "t=<unix time>,v1=<hex hmac>",
+// the hmac is of the time, a dot, and the payload
+func (c *Client) verifyWebhookSignature(payload []byte, signature string) error {
+	if c.DocubotAPISecret == "" {
+		return ErrInvalidWebhookSignature
+	}
+	timestamp, signatures := parseWebhookSignature(signature)
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		return ErrInvalidWebhookSignature
+	}
+	expected := c.webhookMAC(payload, timestamp)
+	for _, s := range signatures {
+		if got, err := hex.DecodeString(s); err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidWebhookSignature
+}
+
+// signWebhookPayload returns the signature header docubot would send with the payload at t
+func (c *Client) signWebhookPayload(payload []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(c.webhookMAC(payload, timestamp))
+}
+
+func (c *Client) webhookMAC(payload []byte, timestamp string) []byte {
+	mac := hmac.New(sha256.New, []byte(c.DocubotAPISecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseWebhookSignature splits a signature header into its time and signatures
+func parseWebhookSignature(signature string) (timestamp string, signatures []string) {
+	for _, part := range strings.Split(signature, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	return timestamp, signatures
+}
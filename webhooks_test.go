@@ -0,0 +1,38 @@
+package docubotlib
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	c := NewClient("http://docubot.test", "key", "secret")
+	payload := []byte(`{"id":"evt_1","type":"thread.completed"}`)
+	event, err := c.ParseWebhookEvent(payload, c.signWebhookPayload(payload, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != "evt_1" || event.Type != EventThreadCompleted {
+		t.Errorf("got event %+v", event)
+	}
+	other := NewClient("http://docubot.test", "key", "other secret")
+	rejected := map[string]string{
+		"signed with another secret": other.signWebhookPayload(payload, time.Now()),
+		"tampered payload":           c.signWebhookPayload([]byte(`{"id":"evt_2"}`), time.Now()),
+		"no time":                    "v1=00",
+		"no signature":               "",
+	}
+	for name, signature := range rejected {
+		if _, err := c.ParseWebhookEvent(payload, signature); err != ErrInvalidWebhookSignature {
+			t.Errorf("%v: got %v, want ErrInvalidWebhookSignature", name, err)
+		}
+	}
+}
+
+func TestParseWebhookEventWithoutSecret(t *testing.T) {
+	c := NewClient("http://docubot.test", "key", "")
+	payload := []byte(`{"id":"evt_1","type":"thread.completed"}`)
+	if _, err := c.ParseWebhookEvent(payload, c.signWebhookPayload(payload, time.Now())); err != ErrInvalidWebhookSignature {
+		t.Fatalf("got %v for a delivery signed with an empty key, want ErrInvalidWebhookSignature", err)
+	}
+}
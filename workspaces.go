@@ -0,0 +1,169 @@
+package docubotlib
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Workspace is a data model for a sub-account, such as a tenant of a multi-tenant integration,
+// with its own trees, threads and api keys
+type Workspace struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	// ExternalID maps the workspace to a tenant in another system
+	ExternalID string `json:"externalId,omitempty"`
+	// DocumentTreeIDs are the trees assigned to the workspace
+	DocumentTreeIDs []string  `json:"documentTreeIds,omitempty"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// WorkspaceResponse is the response received from getting or changing a Workspace
+type WorkspaceResponse struct {
+	Data WorkspaceData          `json:"data"`
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// WorkspaceData is the response data received from getting or changing a Workspace
+type WorkspaceData struct {
+	Workspace Workspace `json:"workspace"`
+}
+
+// WorkspaceListResponse is the response received from listing Workspaces
+type WorkspaceListResponse struct {
+	Data WorkspaceListData `json:"data"`
+	Meta Page              `json:"meta"`
+}
+
+// WorkspaceListData is the response data received from listing Workspaces
+type WorkspaceListData struct {
+	Workspaces []Workspace `json:"workspaces"`
+}
+
+// CreateWorkspace creates a Workspace under the account, docubot assigns its ID
+func (c *Client) CreateWorkspace(ctx context.Context, workspace *Workspace, callOpts ...CallOption) (*WorkspaceResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/workspaces", c.DocubotAPIURLBase)
+	req, err := c.newRequest(ctx, "POST", url, map[string]interface{}{"workspace": workspace})
+	if err != nil {
+		return nil, err
+	}
+	var response WorkspaceResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// GetWorkspace gets a Workspace
+func (c *Client) GetWorkspace(ctx context.Context, id string, callOpts ...CallOption) (*WorkspaceResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/workspaces/%v", c.DocubotAPIURLBase, id)
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response WorkspaceResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ListWorkspaces lists a page of the account's Workspaces
+func (c *Client) ListWorkspaces(ctx context.Context, opts ListOptions, callOpts ...CallOption) (*WorkspaceListResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/workspaces?%v", c.DocubotAPIURLBase, opts.params().Encode())
+	req, err := c.newRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var response WorkspaceListResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// DeleteWorkspace deletes a Workspace with its threads and api keys
+func (c *Client) DeleteWorkspace(ctx context.Context, id string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/workspaces/%v", c.DocubotAPIURLBase, id)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// AssignTree makes one of the account's trees available to the workspace
+func (c *Client) AssignTree(ctx context.Context, workspaceID string, docTreeID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/workspaces/%v/trees", c.DocubotAPIURLBase, workspaceID)
+	req, err := c.newRequest(ctx, "POST", url, map[string]interface{}{"documentTreeId": docTreeID})
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// UnassignTree stops the workspace from starting threads with the tree, its threads in progress can finish
+func (c *Client) UnassignTree(ctx context.Context, workspaceID string, docTreeID string, callOpts ...CallOption) error {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/workspaces/%v/trees/%v", c.DocubotAPIURLBase, workspaceID, docTreeID)
+	req, err := c.newRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, nil, nil)
+}
+
+// CreateWorkspaceAPIKey creates an api key for the workspace, the returned secret can't be retrieved again
+func (c *Client) CreateWorkspaceAPIKey(ctx context.Context, workspaceID string, opts CreateAPIKeyOptions, callOpts ...CallOption) (*APIKeyResponse, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	url := fmt.Sprintf("%v/api/v1/workspaces/%v/keys", c.DocubotAPIURLBase, workspaceID)
+	req, err := c.newRequest(ctx, "POST", url, opts)
+	if err != nil {
+		return nil, err
+	}
+	var response APIKeyResponse
+	err = c.doJSON(req, nil, &response)
+	return &response, err
+}
+
+// ProvisionedWorkspace is a workspace set up by ProvisionWorkspace
+type ProvisionedWorkspace struct {
+	Workspace Workspace
+	// APIKey holds the workspace's credentials, including the secret
+	APIKey APIKey
+}
+
+// ProvisionWorkspace creates a workspace, assigns it the trees and issues its api key, such as when onboarding
+// a tenant. When a step fails the workspace is left as far as it got and returned with the error.
+func (c *Client) ProvisionWorkspace(ctx context.Context, workspace *Workspace, docTreeIDs []string, key CreateAPIKeyOptions, callOpts ...CallOption) (*ProvisionedWorkspace, error) {
+	ctx = withCallOptions(ctx, callOpts)
+	created, err := c.CreateWorkspace(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	provisioned := &ProvisionedWorkspace{Workspace: created.Data.Workspace}
+	for _, id := range docTreeIDs {
+		if err := c.AssignTree(ctx, provisioned.Workspace.ID, id); err != nil {
+			return provisioned, err
+		}
+		if !containsString(provisioned.Workspace.DocumentTreeIDs, id) {
+			provisioned.Workspace.DocumentTreeIDs = append(provisioned.Workspace.DocumentTreeIDs, id)
+		}
+	}
+	apiKey, err := c.CreateWorkspaceAPIKey(ctx, provisioned.Workspace.ID, key)
+	if err != nil {
+		return provisioned, err
+	}
+	provisioned.APIKey = apiKey.Data.APIKey
+	return provisioned, nil
+}
+
+// Client returns a client authenticated with the workspace's api key, configured like parent
+func (w *ProvisionedWorkspace) Client(parent *Client) *Client {
+	client := NewClient(parent.DocubotAPIURLBase, w.APIKey.Key, w.APIKey.Secret)
+	client.DocubotPreviewAPIURLBase = parent.DocubotPreviewAPIURLBase
+	client.DocubotMessagesAPIURLBase = parent.DocubotMessagesAPIURLBase
+	client.DocubotDocumentsAPIURLBase = parent.DocubotDocumentsAPIURLBase
+	client.HTTPClient = parent.HTTPClient
+	return client
+}